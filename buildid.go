@@ -19,8 +19,14 @@ package gore
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"math"
+	"strings"
 )
 
 var (
@@ -70,3 +76,136 @@ func parseBuildIDFromRaw(data []byte) (string, error) {
 	}
 	return string(data[idx+len(goNoteRawStart) : end]), nil
 }
+
+const (
+	// buildIDHashLen is the length of each part of a build ID. Each part
+	// is the first 15 bytes of a SHA-256 hash encoded with unpadded
+	// URL-safe base64.
+	buildIDHashLen = 20
+	// legacyBuildIDLen is the length of the hex encoded SHA-1 hash used as
+	// the build ID before Go 1.10.
+	legacyBuildIDLen = 40
+)
+
+// VerifyBuildID checks the build ID of the binary. A binary built by Go 1.10
+// or later has a build ID made of four slash-separated hashes where the last
+// one is the hash of the file's content with the build ID zeroed out. The
+// format of the ID is checked and the content hash is recomputed and
+// compared with the last part. False is returned if the ID is malformed or
+// if the content doesn't match, which is the case if the ID or the binary
+// has been edited after it was linked. Older build IDs are a hash of the
+// inputs so only their format can be checked. ErrNoBuildID is returned if the
+// binary has no build ID.
+func (f *GoFile) VerifyBuildID() (bool, error) {
+	if f.BuildID == "" {
+		return false, ErrNoBuildID
+	}
+	parts, ok := splitBuildID(f.BuildID)
+	if !ok {
+		return false, nil
+	}
+	if len(parts) == 1 {
+		// Legacy build ID that can't be verified against the content.
+		return true, nil
+	}
+
+	var r io.Reader = io.NewSectionReader(f.fh.getReader(), 0, math.MaxInt64)
+	// The code signature and the host build ID, the GNU build ID or the
+	// Mach-O UUID, are updated after the build ID has been written so they
+	// are excluded from the hash.
+	switch fh := f.fh.(type) {
+	case *elfFile:
+		if off, size := fh.gnuBuildIDRange(); size != 0 {
+			r = &zeroRangeReader{r: r, start: int64(off), end: int64(off + size)}
+		}
+	case *machoFile:
+		if off, size := fh.codeSignatureRange(); size != 0 {
+			r = &zeroRangeReader{r: r, start: int64(off), end: int64(off + size)}
+		}
+		if off, size := fh.uuidRange(); size != 0 {
+			r = &zeroRangeReader{r: r, start: int64(off), end: int64(off + size)}
+		}
+	}
+	sum, err := hashWithoutBuildID(r, []byte(f.BuildID))
+	if err != nil {
+		return false, fmt.Errorf("failed to hash the file: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(sum[:15]) == parts[len(parts)-1], nil
+}
+
+// splitBuildID splits the build ID into its parts and checks the format of
+// each part. A legacy build ID is returned as a single part.
+func splitBuildID(id string) ([]string, bool) {
+	if len(id) == legacyBuildIDLen {
+		if _, err := hex.DecodeString(id); err == nil {
+			return []string{id}, true
+		}
+	}
+	parts := strings.Split(id, "/")
+	if len(parts) != 4 {
+		return nil, false
+	}
+	for _, p := range parts {
+		if len(p) != buildIDHashLen {
+			return nil, false
+		}
+		if _, err := base64.RawURLEncoding.DecodeString(p); err != nil {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+// hashWithoutBuildID returns the SHA-256 hash of the content read from r
+// where all the occurrences of the build ID have been replaced with zeros.
+// This is the same hash the go command uses as the content ID of the binary.
+func hashWithoutBuildID(r io.Reader, id []byte) ([]byte, error) {
+	h := sha256.New()
+	zeros := make([]byte, len(id))
+	chunk := make([]byte, 64*1024)
+	var buf []byte
+	for {
+		n, err := r.Read(chunk)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		buf = append(buf, chunk[:n]...)
+		for {
+			i := bytes.Index(buf, id)
+			if i < 0 {
+				break
+			}
+			h.Write(buf[:i])
+			h.Write(zeros)
+			buf = buf[i+len(id):]
+		}
+		if err == io.EOF {
+			h.Write(buf)
+			return h.Sum(nil), nil
+		}
+		// Keep enough of the tail to find an ID that straddles two chunks.
+		if keep := len(id) - 1; len(buf) > keep {
+			h.Write(buf[:len(buf)-keep])
+			buf = append(buf[:0], buf[len(buf)-keep:]...)
+		}
+	}
+}
+
+// zeroRangeReader returns the data from the underlying reader except for the
+// bytes between start and end that are returned as zeros.
+type zeroRangeReader struct {
+	r          io.Reader
+	off        int64
+	start, end int64
+}
+
+func (z *zeroRangeReader) Read(p []byte) (int, error) {
+	n, err := z.r.Read(p)
+	for i := 0; i < n; i++ {
+		if off := z.off + int64(i); off >= z.start && off < z.end {
+			p[i] = 0
+		}
+	}
+	z.off += int64(n)
+	return n, err
+}
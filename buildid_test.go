@@ -19,7 +19,9 @@ package gore
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,3 +61,49 @@ func TestParseBuildIDRaw(t *testing.T) {
 	assert.NoError(err, "Parsing the note should not fail.")
 	assert.Equal(expectedID, actual, "Extracted ID does not match.")
 }
+
+func TestSplitBuildID(t *testing.T) {
+	parts, ok := splitBuildID("DrtsigZmOidE-wfbFVNF/io-X8KB-ByimyyODdYUe/Z7tIlu8GbOwt0Jup-Hji/fofocVx5sk8UpaKMTx0a")
+	assert.True(t, ok)
+	assert.Len(t, parts, 4)
+
+	parts, ok = splitBuildID("3e5b6a1f09c2b4d8e7f6a5b4c3d2e1f0a9b8c7d6")
+	assert.True(t, ok)
+	assert.Len(t, parts, 1)
+
+	for _, id := range []string{
+		"DrtsigZmOidE-wfbFVNF/io-X8KB-ByimyyODdYUe/Z7tIlu8GbOwt0Jup-Hji",
+		"DrtsigZmOidE-wfbFVNF/io-X8KB-ByimyyODdYUe/Z7tIlu8GbOwt0Jup-Hji/fofocVx5sk8UpaKMTx0",
+		"DrtsigZmOidE-wfbFVNF/io-X8KB-ByimyyODdYUe/Z7tIlu8GbOwt0Jup-Hji/fofocVx5sk8UpaKMTx0+",
+		"custom",
+	} {
+		_, ok = splitBuildID(id)
+		assert.False(t, ok, id)
+	}
+}
+
+func TestHashWithoutBuildID(t *testing.T) {
+	id := []byte("buildid")
+	// Place the ID so it straddles the chunk boundary.
+	data := bytes.Repeat([]byte{0xcc}, 64*1024-3)
+	data = append(data, id...)
+	data = append(data, 0xcc)
+	data = append(data, id...)
+
+	expected := bytes.Repeat([]byte{0xcc}, 64*1024-3)
+	expected = append(expected, make([]byte, len(id))...)
+	expected = append(expected, 0xcc)
+	expected = append(expected, make([]byte, len(id))...)
+	sum := sha256.Sum256(expected)
+
+	actual, err := hashWithoutBuildID(bytes.NewReader(data), id)
+	assert.NoError(t, err)
+	assert.Equal(t, sum[:], actual)
+}
+
+func TestZeroRangeReader(t *testing.T) {
+	r := &zeroRangeReader{r: bytes.NewReader([]byte{1, 2, 3, 4, 5}), start: 1, end: 3}
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 0, 4, 5}, data)
+}
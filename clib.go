@@ -0,0 +1,99 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"sort"
+	"strings"
+)
+
+// cLibrarySignatures maps native libraries to the prefixes of the symbols
+// they export. The prefixes are chosen to be specific enough to not match
+// symbols from the Go runtime or the C runtime.
+var cLibrarySignatures = map[string][]string{
+	"sqlite":    {"sqlite3_"},
+	"libpcap":   {"pcap_"},
+	"zlib":      {"zlibVersion", "deflateInit", "inflateInit"},
+	"openssl":   {"OPENSSL_init", "SSL_CTX_", "EVP_"},
+	"libcurl":   {"curl_easy_", "curl_multi_"},
+	"libgit2":   {"git_libgit2_"},
+	"rocksdb":   {"rocksdb_"},
+	"leveldb":   {"leveldb_"},
+	"libusb":    {"libusb_"},
+	"libsodium": {"sodium_init"},
+	"zstd":      {"ZSTD_"},
+	"lz4":       {"LZ4_"},
+	"libxml2":   {"xmlReadMemory", "xmlParseDoc"},
+	"sdl2":      {"SDL_Init"},
+	"glfw":      {"glfwInit"},
+	"yara":      {"yr_initialize", "yr_rules_"},
+	"libbpf":    {"bpf_object__"},
+}
+
+// DetectEmbeddedCLibraries returns the names of known C libraries that have
+// been compiled into the binary via cgo. The libraries are detected by
+// matching known symbol prefixes against the symbol table and against the
+// cgo call wrappers in the pclntab. The wrappers are also present in
+// stripped binaries. The returned list is sorted.
+func (f *GoFile) DetectEmbeddedCLibraries() ([]string, error) {
+	names, err := f.fh.getSymbolNames()
+	if err != nil {
+		return nil, err
+	}
+
+	tab, err := f.PCLNTab()
+	if err != nil {
+		return nil, err
+	}
+	// The wrappers generated by cgo are named "_Cfunc_" followed by the C
+	// function name.
+	const cfunc = "._Cfunc_"
+	for _, fn := range tab.Funcs {
+		if i := strings.Index(fn.Name, cfunc); i != -1 {
+			names = append(names, fn.Name[i+len(cfunc):])
+		}
+	}
+
+	return matchCLibraries(names), nil
+}
+
+// matchCLibraries returns the sorted names of the libraries with symbols
+// matching their signatures.
+func matchCLibraries(names []string) []string {
+	var libs []string
+	for lib, prefixes := range cLibrarySignatures {
+		if hasSymbolPrefix(names, prefixes) {
+			libs = append(libs, lib)
+		}
+	}
+	sort.Strings(libs)
+	return libs
+}
+
+func hasSymbolPrefix(names []string, prefixes []string) bool {
+	for _, name := range names {
+		// Mach-O symbols have a leading underscore.
+		name = strings.TrimPrefix(name, "_")
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchCLibraries(t *testing.T) {
+	names := []string{
+		"runtime.main",
+		"_sqlite3_open_v2",
+		"pcap_open_live",
+		"main._Cfunc_free",
+		"ZSTD_compress",
+	}
+	require.Equal(t, []string{"libpcap", "sqlite", "zstd"}, matchCLibraries(names))
+	require.Empty(t, matchCLibraries([]string{"runtime.main", "_cgo_topofstack"}))
+}
@@ -0,0 +1,135 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ResolveContainerElementType returns the type of the elements stored in the
+// container at the address. The container type is the type of the global
+// variable at the address and can be a pointer, slice, array, map or channel.
+// If the element type is an interface, the concrete type is resolved from
+// the first element's type word, following the pointer or the slice's data
+// pointer if needed. This is only possible for containers that are
+// initialized statically by the compiler. For nil containers, nil interface
+// values, maps and channels, the storage is allocated at run time and the
+// element type from the type metadata is returned instead.
+func (f *GoFile) ResolveContainerElementType(addr uint64, containerType *GoType) (*GoType, error) {
+	if containerType == nil {
+		return nil, errors.New("no container type")
+	}
+	switch containerType.Kind {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+	default:
+		return nil, fmt.Errorf("%s is not a container type", containerType)
+	}
+	elem := containerType.Element
+	if elem == nil {
+		return nil, fmt.Errorf("the element type of %s is not known", containerType)
+	}
+	if elem.Kind != reflect.Interface {
+		return elem, nil
+	}
+
+	err := f.initModuleData()
+	if err != nil {
+		return nil, err
+	}
+	if GoVersionCompare(f.FileInfo.goversion.Name, "go1.7beta1") < 0 {
+		return nil, fmt.Errorf("resolving interface values is not supported for %s", f.FileInfo.goversion.Name)
+	}
+
+	// Find the address of the first element.
+	var slot uint64
+	switch containerType.Kind {
+	case reflect.Ptr:
+		ptr, err := f.readVarPointer(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the pointer at 0x%x: %w", addr, err)
+		}
+		slot = ptr
+	case reflect.Slice:
+		data, err := f.readVarPointer(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the slice data pointer at 0x%x: %w", addr, err)
+		}
+		n, err := f.readVarPointer(addr + uint64(f.FileInfo.WordSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the slice length at 0x%x: %w", addr, err)
+		}
+		if n > 0 {
+			slot = data
+		}
+	case reflect.Array:
+		if containerType.Length > 0 {
+			slot = addr
+		}
+	}
+	if slot == 0 {
+		return elem, nil
+	}
+	return f.resolveInterfaceType(slot, elem)
+}
+
+// resolveInterfaceType returns the concrete type of the interface value at
+// the address. Empty interfaces store a pointer to the type while the other
+// interfaces store a pointer to the itab, which holds the type after the
+// interface type pointer. If the interface value is nil, the interface type
+// is returned.
+func (f *GoFile) resolveInterfaceType(addr uint64, iface *GoType) (*GoType, error) {
+	typAddr, err := f.readVarPointer(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the interface value at 0x%x: %w", addr, err)
+	}
+	if typAddr == 0 {
+		return iface, nil
+	}
+	if len(iface.Methods) > 0 {
+		itab := typAddr
+		typAddr, err = f.readPointer(itab + uint64(f.FileInfo.WordSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the type from the itab at 0x%x: %w", itab, err)
+		}
+	}
+
+	md := f.moduledata
+	types, err := md.Types().Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get types data section: %w", err)
+	}
+	typ, err := newTypeParser(types, md.Types().Address, f.FileInfo).parseType(typAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the type at 0x%x: %w", typAddr, err)
+	}
+	return typ, nil
+}
+
+// readVarPointer reads the pointer stored in a variable at the address. The
+// bss sections are zeroed at run time and don't have any data in the file, so
+// variables in them are read as nil.
+func (f *GoFile) readVarPointer(addr uint64) (uint64, error) {
+	for _, sect := range []ModuleDataSection{f.moduledata.Bss(), f.moduledata.NoPtrBss()} {
+		if addr >= sect.Address && addr < sect.Address+sect.Length {
+			return 0, nil
+		}
+	}
+	return f.readPointer(addr)
+}
@@ -0,0 +1,46 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveContainerElementType(t *testing.T) {
+	f := new(GoFile)
+	elem := &GoType{Kind: reflect.Int, Name: "int"}
+
+	for _, kind := range []reflect.Kind{reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan} {
+		// Elements that are not interfaces are resolved without reading the
+		// container.
+		typ, err := f.ResolveContainerElementType(0x1000, &GoType{Kind: kind, Element: elem})
+		require.NoError(t, err, kind)
+		assert.Same(t, elem, typ, kind)
+	}
+
+	_, err := f.ResolveContainerElementType(0x1000, &GoType{Kind: reflect.Struct, Name: "sync.Pool"})
+	assert.Error(t, err)
+	_, err = f.ResolveContainerElementType(0x1000, &GoType{Kind: reflect.Slice})
+	assert.Error(t, err, "unknown element type")
+	_, err = f.ResolveContainerElementType(0x1000, nil)
+	assert.Error(t, err)
+}
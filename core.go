@@ -0,0 +1,384 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"cmp"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+)
+
+// GoroutineStatus is the scheduling state of a goroutine, as stored in the
+// atomicstatus field of the runtime's g structure.
+type GoroutineStatus uint32
+
+const (
+	// GoroutineIdle is a goroutine that has just been allocated.
+	GoroutineIdle GoroutineStatus = 0
+	// GoroutineRunnable is a goroutine on a run queue.
+	GoroutineRunnable GoroutineStatus = 1
+	// GoroutineRunning is a goroutine executing user code.
+	GoroutineRunning GoroutineStatus = 2
+	// GoroutineSyscall is a goroutine executing a system call.
+	GoroutineSyscall GoroutineStatus = 3
+	// GoroutineWaiting is a goroutine blocked in the runtime, for example on
+	// a channel operation or a lock.
+	GoroutineWaiting GoroutineStatus = 4
+	// GoroutineDead is a goroutine that is unused. It has either exited or
+	// is on a free list.
+	GoroutineDead GoroutineStatus = 6
+	// GoroutineCopyStack is a goroutine whose stack is being moved.
+	GoroutineCopyStack GoroutineStatus = 8
+	// GoroutinePreempted is a goroutine that stopped itself for a suspend
+	// preemption.
+	GoroutinePreempted GoroutineStatus = 9
+	// GoroutineLeaked is a goroutine detected as leaked by the garbage
+	// collector. Only used by Go 1.26 and later.
+	GoroutineLeaked GoroutineStatus = 10
+	// GoroutineDeadExtra is an unused goroutine reserved for the threads
+	// created outside of Go. Only used by Go 1.26 and later.
+	GoroutineDeadExtra GoroutineStatus = 11
+
+	// goroutineScan is the bit set by the garbage collector while it scans
+	// the goroutine's stack.
+	goroutineScan GoroutineStatus = 0x1000
+)
+
+var goroutineStatusNames = map[GoroutineStatus]string{
+	GoroutineIdle:      "idle",
+	GoroutineRunnable:  "runnable",
+	GoroutineRunning:   "running",
+	GoroutineSyscall:   "syscall",
+	GoroutineWaiting:   "waiting",
+	GoroutineDead:      "dead",
+	GoroutineCopyStack: "copystack",
+	GoroutinePreempted: "preempted",
+	GoroutineLeaked:    "leaked",
+	GoroutineDeadExtra: "dead",
+}
+
+// Scanning returns true if the garbage collector was scanning the
+// goroutine's stack.
+func (s GoroutineStatus) Scanning() bool {
+	return s&goroutineScan != 0
+}
+
+// String returns the name of the status, as used by the runtime in stack
+// traces. The scan bit is ignored.
+func (s GoroutineStatus) String() string {
+	if name, ok := goroutineStatusNames[s&^goroutineScan]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint32(s))
+}
+
+// GoroutineInfo holds the state of a goroutine read from a core dump.
+type GoroutineInfo struct {
+	// ID is the goroutine ID, as shown in stack traces.
+	ID uint64
+	// Status is the scheduling state of the goroutine.
+	Status GoroutineStatus
+	// PC is the program counter saved when the goroutine was last
+	// descheduled. For goroutines running at the time of the dump, it
+	// doesn't reflect the current location.
+	PC uint64
+	// SP is the stack pointer saved together with the PC.
+	SP uint64
+	// Function is the function containing the PC. It is nil if the PC is
+	// not within any of the functions in the pclntab.
+	Function *Function
+	// StartFunction is the function the goroutine was launched with. It is
+	// nil if it could not be resolved.
+	StartFunction *Function
+}
+
+// ReadGoroutines returns the goroutines of the process in the core dump set
+// by Options.Core. The goroutines are enumerated by walking the runtime.allgs
+// slice and the layout of the runtime's g structure is taken from the type
+// information in the binary. Dead goroutines are skipped. The addresses are
+// adjusted for the load address of position independent executables. The
+// result is sorted by the goroutine ID.
+// The binary must have a symbol table. Only linux/amd64 ELF core dumps are
+// supported. For other architectures, ErrArchNotSupported is returned. If no
+// core dump was set, ErrNoCoreDump is returned.
+func (f *GoFile) ReadGoroutines() ([]GoroutineInfo, error) {
+	if f.core == nil {
+		return nil, ErrNoCoreDump
+	}
+	e, ok := f.fh.(*elfFile)
+	if !ok {
+		return nil, ErrUnsupportedFile
+	}
+	if f.FileInfo.Arch != ArchAMD64 {
+		return nil, ErrArchNotSupported
+	}
+
+	mem, err := openCoreMemory(f.core)
+	if err != nil {
+		return nil, err
+	}
+	bias := mem.loadBias(e.file.Entry)
+
+	allgs, err := f.GetSymbol("runtime.allgs")
+	if err != nil {
+		return nil, fmt.Errorf("error when looking up runtime.allgs: %w", err)
+	}
+	layout, err := f.goroutineLayout()
+	if err != nil {
+		return nil, err
+	}
+
+	// allgs is a slice of pointers to the g structures.
+	hdr := make([]byte, 16)
+	if err = mem.readAt(hdr, allgs.Value+bias); err != nil {
+		return nil, fmt.Errorf("error when reading runtime.allgs: %w", err)
+	}
+	ptr, n := mem.order.Uint64(hdr), mem.order.Uint64(hdr[8:])
+	if n > maxCoreGoroutines {
+		return nil, fmt.Errorf("runtime.allgs has too many entries: %d", n)
+	}
+	// Check the mapping before allocating the buffer since the length comes
+	// from the core dump.
+	if mem.segment(ptr, n*8) == nil {
+		return nil, fmt.Errorf("error when reading the goroutine pointers: address range 0x%x-0x%x not mapped in the core dump", ptr, ptr+n*8)
+	}
+	ptrs := make([]byte, n*8)
+	if err = mem.readAt(ptrs, ptr); err != nil {
+		return nil, fmt.Errorf("error when reading the goroutine pointers: %w", err)
+	}
+
+	var gs []GoroutineInfo
+	buf := make([]byte, layout.size)
+	for i := uint64(0); i < n; i++ {
+		if err = mem.readAt(buf, mem.order.Uint64(ptrs[i*8:])); err != nil {
+			return nil, fmt.Errorf("error when reading goroutine %d: %w", i, err)
+		}
+		status := GoroutineStatus(mem.order.Uint32(buf[layout.status:]))
+		if s := status &^ goroutineScan; s == GoroutineDead || s == GoroutineDeadExtra {
+			continue
+		}
+		g := GoroutineInfo{
+			ID:     mem.order.Uint64(buf[layout.id:]),
+			Status: status,
+			PC:     mem.order.Uint64(buf[layout.pc:]),
+			SP:     mem.order.Uint64(buf[layout.sp:]),
+		}
+		g.Function, _ = f.FunctionForAddress(g.PC - bias)
+		g.StartFunction, _ = f.FunctionForAddress(mem.order.Uint64(buf[layout.startPC:]) - bias)
+		gs = append(gs, g)
+	}
+
+	slices.SortFunc(gs, func(a, b GoroutineInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return gs, nil
+}
+
+// maxCoreGoroutines is the upper limit for the length of runtime.allgs. It
+// guards against reading a corrupted core dump.
+const maxCoreGoroutines = 1 << 24
+
+// goroutineLayout holds the offsets of the fields read from the runtime's g
+// structure.
+type goroutineLayout struct {
+	size    uint64
+	id      uint64
+	status  uint64
+	pc      uint64
+	sp      uint64
+	startPC uint64
+}
+
+// goroutineLayout returns the layout of the runtime's g structure for the
+// amd64 version of the runtime in the binary.
+func (f *GoFile) goroutineLayout() (goroutineLayout, error) {
+	types, err := f.GetTypes()
+	if err != nil {
+		return goroutineLayout{}, err
+	}
+	idx := slices.IndexFunc(types, func(t *GoType) bool {
+		return t.Name == "runtime.g" && t.Kind == reflect.Struct
+	})
+	if idx < 0 {
+		return goroutineLayout{}, errors.New("runtime.g type not found")
+	}
+	g := types[idx]
+
+	var l goroutineLayout
+	for _, fld := range []struct {
+		dst  *uint64
+		path []string
+	}{
+		{&l.id, []string{"goid"}},
+		{&l.status, []string{"atomicstatus"}},
+		{&l.pc, []string{"sched", "pc"}},
+		{&l.sp, []string{"sched", "sp"}},
+		{&l.startPC, []string{"startpc"}},
+	} {
+		off, ok := structFieldOffset(g, fld.path...)
+		if !ok {
+			return goroutineLayout{}, fmt.Errorf("field %v not found in runtime.g", fld.path)
+		}
+		*fld.dst = off
+		// All the fields are at most 8 bytes long.
+		l.size = max(l.size, off+8)
+	}
+	return l, nil
+}
+
+// structFieldOffset returns the offset of the nested field from the start of
+// the struct.
+func structFieldOffset(t *GoType, path ...string) (uint64, bool) {
+	var off uint64
+	for _, name := range path {
+		idx := slices.IndexFunc(t.Fields, func(f *GoType) bool {
+			return f.FieldName == name
+		})
+		if idx < 0 {
+			return 0, false
+		}
+		t = t.Fields[idx]
+		off += t.FieldOffset
+	}
+	return off, true
+}
+
+// coreMemory gives access to the memory of the process in a core dump.
+type coreMemory struct {
+	segments []*elf.Prog
+	order    binary.ByteOrder
+	// entry is the entry point of the executable, taken from the auxiliary
+	// vector. It is zero if not available.
+	entry uint64
+}
+
+func openCoreMemory(r io.ReaderAt) (*coreMemory, error) {
+	file, err := elf.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing the core dump: %w", err)
+	}
+	if file.Type != elf.ET_CORE {
+		return nil, fmt.Errorf("not a core dump: %s", file.Type)
+	}
+	if file.Machine != elf.EM_X86_64 {
+		return nil, ErrArchNotSupported
+	}
+	size, err := readerSize(r)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the size of the core dump: %w", err)
+	}
+
+	m := &coreMemory{order: file.ByteOrder}
+	for _, p := range file.Progs {
+		switch p.Type {
+		case elf.PT_LOAD:
+			m.segments = append(m.segments, p)
+		case elf.PT_NOTE:
+			if m.entry == 0 {
+				m.entry = auxvEntry(p, file.ByteOrder, uint64(size))
+			}
+		}
+	}
+	return m, nil
+}
+
+// loadBias returns the difference between the address the executable was
+// loaded at and its link address.
+func (m *coreMemory) loadBias(entry uint64) uint64 {
+	if m.entry == 0 {
+		return 0
+	}
+	return m.entry - entry
+}
+
+// readAt reads len(buf) bytes of the process memory at the address. The part
+// of a segment not stored in the core dump is read as zeros.
+func (m *coreMemory) readAt(buf []byte, addr uint64) error {
+	p := m.segment(addr, uint64(len(buf)))
+	if p == nil {
+		return fmt.Errorf("address 0x%x not mapped in the core dump", addr)
+	}
+	off := addr - p.Vaddr
+	clear(buf)
+	if off < p.Filesz {
+		n := min(uint64(len(buf)), p.Filesz-off)
+		if _, err := p.ReadAt(buf[:n], int64(off)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// segment returns the segment holding size bytes of the process memory at
+// the address. Nil is returned if the range isn't mapped by a single segment.
+func (m *coreMemory) segment(addr, size uint64) *elf.Prog {
+	for _, p := range m.segments {
+		if addr >= p.Vaddr && addr-p.Vaddr <= p.Memsz && size <= p.Memsz-(addr-p.Vaddr) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Note type and auxiliary vector key used to locate the entry point of the
+// executable.
+const (
+	ntAuxv  = 6
+	atEntry = 9
+)
+
+// auxvEntry returns the AT_ENTRY value from the NT_AUXV note in the segment.
+// Zero is returned if the note doesn't exist or if the segment extends past
+// the end of the core dump of the given size.
+func auxvEntry(p *elf.Prog, order binary.ByteOrder, coreSize uint64) uint64 {
+	if p.Off > coreSize || p.Filesz > coreSize-p.Off {
+		return 0
+	}
+	data := make([]byte, p.Filesz)
+	if _, err := p.ReadAt(data, 0); err != nil {
+		return 0
+	}
+	for len(data) >= 12 {
+		namesz := uint64(order.Uint32(data))
+		descsz := uint64(order.Uint32(data[4:]))
+		typ := order.Uint32(data[8:])
+		data = data[12:]
+		nameLen := (namesz + 3) &^ 3
+		descLen := (descsz + 3) &^ 3
+		if nameLen+descsz > uint64(len(data)) {
+			return 0
+		}
+		desc := data[nameLen : nameLen+descsz]
+		if typ == ntAuxv && bytes.HasPrefix(data[:namesz], []byte("CORE")) {
+			for ; len(desc) >= 16; desc = desc[16:] {
+				if order.Uint64(desc) == atEntry {
+					return order.Uint64(desc[8:])
+				}
+			}
+			return 0
+		}
+		data = data[min(nameLen+descLen, uint64(len(data))):]
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoroutineStatusString(t *testing.T) {
+	assert.Equal(t, "waiting", GoroutineWaiting.String())
+	assert.Equal(t, "runnable", (GoroutineRunnable | goroutineScan).String())
+	assert.True(t, (GoroutineRunnable | goroutineScan).Scanning())
+	assert.False(t, GoroutineRunnable.Scanning())
+	assert.Equal(t, "unknown(5)", GoroutineStatus(5).String())
+}
+
+func TestCoreMemoryReadAt(t *testing.T) {
+	data := []byte{1, 2, 3, 4}
+	m := &coreMemory{segments: []*elf.Prog{{
+		ProgHeader: elf.ProgHeader{Type: elf.PT_LOAD, Vaddr: 0x1000, Filesz: 4, Memsz: 8},
+		ReaderAt:   bytes.NewReader(data),
+	}}}
+
+	buf := make([]byte, 4)
+	assert.NoError(t, m.readAt(buf, 0x1002))
+	assert.Equal(t, []byte{3, 4, 0, 0}, buf, "bytes past the file size are zero")
+
+	assert.NoError(t, m.readAt(buf, 0x1004))
+	assert.Equal(t, []byte{0, 0, 0, 0}, buf)
+
+	assert.Error(t, m.readAt(buf, 0x1006), "read past the end of the segment")
+	assert.Error(t, m.readAt(buf, 0x800), "unmapped address")
+
+	assert.NotNil(t, m.segment(0x1000, 8))
+	assert.Nil(t, m.segment(0x1000, maxCoreGoroutines*8), "range past the end of the segment")
+}
+
+func TestAuxvEntry(t *testing.T) {
+	var note bytes.Buffer
+	writeNote := func(name string, typ uint32, desc []byte) {
+		le := binary.LittleEndian
+		_ = binary.Write(&note, le, []uint32{uint32(len(name) + 1), uint32(len(desc)), typ})
+		note.WriteString(name)
+		note.Write(make([]byte, 4-len(name)%4))
+		note.Write(desc)
+	}
+	writeNote("CORE", 1, make([]byte, 6))
+	note.Write(make([]byte, 2))
+	auxv := make([]byte, 48)
+	binary.LittleEndian.PutUint64(auxv, 6)
+	binary.LittleEndian.PutUint64(auxv[8:], 0x1000)
+	binary.LittleEndian.PutUint64(auxv[16:], atEntry)
+	binary.LittleEndian.PutUint64(auxv[24:], 0x555555554000+0x1234)
+	writeNote("CORE", ntAuxv, auxv)
+
+	p := &elf.Prog{
+		ProgHeader: elf.ProgHeader{Type: elf.PT_NOTE, Filesz: uint64(note.Len())},
+		ReaderAt:   bytes.NewReader(note.Bytes()),
+	}
+	entry := auxvEntry(p, binary.LittleEndian, uint64(note.Len()))
+	assert.Equal(t, uint64(0x555555555234), entry)
+
+	// A segment larger than the core dump.
+	p.Filesz = 1 << 62
+	assert.Equal(t, uint64(0), auxvEntry(p, binary.LittleEndian, uint64(note.Len())))
+
+	m := &coreMemory{entry: entry}
+	assert.Equal(t, uint64(0x555555554000), m.loadBias(0x1234))
+	assert.Equal(t, uint64(0), (&coreMemory{}).loadBias(0x1234))
+}
+
+func TestStructFieldOffset(t *testing.T) {
+	gobuf := &GoType{Name: "runtime.gobuf", FieldName: "sched", FieldOffset: 0x38, Fields: []*GoType{
+		{Name: "uintptr", FieldName: "sp", FieldOffset: 0},
+		{Name: "uintptr", FieldName: "pc", FieldOffset: 8},
+	}}
+	g := &GoType{Name: "runtime.g", Fields: []*GoType{
+		{Name: "runtime.stack", FieldName: "stack"},
+		gobuf,
+	}}
+
+	off, ok := structFieldOffset(g, "sched", "pc")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0x40), off)
+
+	_, ok = structFieldOffset(g, "sched", "lr")
+	assert.False(t, ok)
+}
+
+func TestReadGoroutinesNoCore(t *testing.T) {
+	f := &GoFile{fh: &elfFile{}, FileInfo: &FileInfo{Arch: ArchAMD64}}
+	_, err := f.ReadGoroutines()
+	assert.ErrorIs(t, err, ErrNoCoreDump)
+}
@@ -0,0 +1,434 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// refKind is the kind of reference an instruction makes.
+type refKind uint8
+
+const (
+	// refCall is a call to a function.
+	refCall refKind = iota + 1
+	// refJump is a jump to a location outside the function.
+	refJump
+	// refData is a load or store of a data address.
+	refData
+)
+
+// codeRef is an address referenced by an instruction.
+type codeRef struct {
+	// pc is the address of the instruction making the reference.
+	pc uint64
+	// target is the referenced address.
+	target uint64
+	kind   refKind
+}
+
+// FunctionReferences returns all the addresses referenced by the function.
+// This includes the targets of calls, jumps to locations outside the function
+// and the addresses of global data that is loaded or stored. The addresses
+// are returned sorted and each address is only included once.
+// Only x86 (i386 and amd64) and arm64 binaries are supported. For other
+// architectures, ErrArchNotSupported is returned.
+func (f *GoFile) FunctionReferences(fn *Function) ([]uint64, error) {
+	refs, err := f.functionRefs(fn)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]uint64, 0, len(refs))
+	for _, r := range refs {
+		addrs = append(addrs, r.target)
+	}
+	slices.Sort(addrs)
+	return slices.Compact(addrs), nil
+}
+
+// CallersOf returns all the functions that contain a direct call to the
+// function with the given name. The name is the full symbol name of the
+// function, for example "main.main" or "os.(*File).Write". Calls made via
+// function values or interfaces are not found. The functions are returned
+// sorted by their address.
+// Only x86 (i386 and amd64) and arm64 binaries are supported. For other
+// architectures, ErrArchNotSupported is returned.
+func (f *GoFile) CallersOf(funcName string) ([]*Function, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	target := f.pclntab.LookupFunc(funcName)
+	if target == nil {
+		return nil, fmt.Errorf("function %s not found", funcName)
+	}
+
+	var callers []*Function
+	for _, fn := range f.functionsByEntry() {
+		refs, err := f.functionRefs(fn)
+		if errors.Is(err, ErrArchNotSupported) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+		if slices.ContainsFunc(refs, func(r codeRef) bool {
+			return r.kind == refCall && r.target == target.Entry
+		}) {
+			callers = append(callers, fn)
+		}
+	}
+
+	slices.SortFunc(callers, func(a, b *Function) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+	return callers, nil
+}
+
+// Degree is the number of distinct callers and callees of a function in the
+// call graph.
+type Degree struct {
+	// In is the number of functions calling the function.
+	In int `json:"in"`
+	// Out is the number of functions called by the function.
+	Out int `json:"out"`
+}
+
+// FunctionDegrees returns the in-degree and out-degree of all the functions
+// in the call graph built from the direct calls in the binary. The map is
+// keyed by the full symbol name of the function, as used by CallersOf. Calls
+// made via function values or interfaces are not included and recursive
+// calls are not counted. Functions with a high in-degree are often utility
+// functions, while functions with a high out-degree orchestrate the work of
+// others.
+// Only x86 (i386 and amd64) and arm64 binaries are supported. For other
+// architectures, ErrArchNotSupported is returned.
+func (f *GoFile) FunctionDegrees() (map[string]Degree, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	fcns := f.functionsByEntry()
+	names := make(map[uint64]string, len(fcns))
+	for _, fn := range f.pclntab.Funcs {
+		if _, ok := fcns[fn.Entry]; ok {
+			names[fn.Entry] = fn.Name
+		}
+	}
+
+	type edge struct{ from, to uint64 }
+	edges := make(map[edge]bool)
+	for entry, fn := range fcns {
+		refs, err := f.functionRefs(fn)
+		if errors.Is(err, ErrArchNotSupported) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+		for _, r := range refs {
+			if _, ok := fcns[r.target]; ok && r.kind == refCall && r.target != entry {
+				edges[edge{entry, r.target}] = true
+			}
+		}
+	}
+
+	degrees := make(map[string]Degree, len(names))
+	for _, name := range names {
+		degrees[name] = Degree{}
+	}
+	for e := range edges {
+		from, to := degrees[names[e.from]], degrees[names[e.to]]
+		from.Out++
+		degrees[names[e.from]] = from
+		to.In++
+		degrees[names[e.to]] = to
+	}
+	return degrees, nil
+}
+
+// UnreferencedFunctions returns the functions that are not referenced by any
+// instruction in the binary. This means they are not called, jumped to or
+// have their address loaded by any other function. These functions are
+// instead reached through function values stored in data, for example via
+// reflection, itabs or the runtime, which makes them candidates for hidden
+// entry points. The functions are returned sorted by their address.
+// Only x86 (i386 and amd64) and arm64 binaries are supported. For other
+// architectures, ErrArchNotSupported is returned.
+func (f *GoFile) UnreferencedFunctions() ([]*Function, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	fcns := f.functionsByEntry()
+	referenced := make(map[uint64]bool)
+	for _, fn := range fcns {
+		refs, err := f.functionRefs(fn)
+		if errors.Is(err, ErrArchNotSupported) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+		for _, r := range refs {
+			// Recursive calls don't make the function reachable.
+			if r.target != fn.Offset {
+				referenced[r.target] = true
+			}
+		}
+	}
+
+	var unreferenced []*Function
+	for entry, fn := range fcns {
+		if !referenced[entry] {
+			unreferenced = append(unreferenced, fn)
+		}
+	}
+	slices.SortFunc(unreferenced, func(a, b *Function) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+	return unreferenced, nil
+}
+
+// CyclomaticEstimate returns an estimate of the cyclomatic complexity of the
+// function. The estimate is the number of conditional branches in the
+// function plus one. Since the compiler may add branches, for example for
+// bounds checks and stack growth, the value is higher than the complexity
+// of the source code. It is best used to compare functions with each other.
+// Only x86 (i386 and amd64) and arm64 binaries are supported. For other
+// architectures, ErrArchNotSupported is returned.
+func (f *GoFile) CyclomaticEstimate(fn *Function) (int, error) {
+	buf, err := f.FunctionData(fn)
+	if err != nil {
+		return 0, err
+	}
+
+	switch f.FileInfo.Arch {
+	case Arch386, ArchAMD64:
+		return x86CondBranches(buf, f.FileInfo.WordSize*8) + 1, nil
+	case ArchARM64:
+		return arm64CondBranches(buf, f.FileInfo.ByteOrder) + 1, nil
+	default:
+		return 0, ErrArchNotSupported
+	}
+}
+
+// functionRefs disassembles the function and returns the references made by
+// its instructions in the order they appear in the function.
+func (f *GoFile) functionRefs(fn *Function) ([]codeRef, error) {
+	buf, err := f.FunctionData(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f.FileInfo.Arch {
+	case Arch386, ArchAMD64:
+		return x86Refs(buf, fn.Offset, f.FileInfo.WordSize*8), nil
+	case ArchARM64:
+		return arm64Refs(buf, fn.Offset, f.FileInfo.ByteOrder), nil
+	default:
+		return nil, ErrArchNotSupported
+	}
+}
+
+func x86Refs(buf []byte, start uint64, mode int) []codeRef {
+	var refs []codeRef
+	end := start + uint64(len(buf))
+	s := 0
+	for s < len(buf) {
+		inst, err := x86asm.Decode(buf[s:], mode)
+		if err != nil {
+			// Skip the byte and try to resynchronize. The function may
+			// contain padding or data that is not valid code.
+			s++
+			continue
+		}
+		pc := start + uint64(s)
+		next := pc + uint64(inst.Len)
+		s += inst.Len
+
+		for _, arg := range inst.Args {
+			if arg == nil {
+				break
+			}
+			switch a := arg.(type) {
+			case x86asm.Rel:
+				target := uint64(int64(next) + int64(a))
+				if inst.Op == x86asm.CALL {
+					refs = append(refs, codeRef{pc: pc, target: target, kind: refCall})
+				} else if target < start || target >= end {
+					// Jumps within the function are not references.
+					refs = append(refs, codeRef{pc: pc, target: target, kind: refJump})
+				}
+			case x86asm.Mem:
+				switch {
+				case a.Base == x86asm.RIP || a.Base == x86asm.EIP:
+					// If the addressing is based on the instruction pointer, fix the address.
+					refs = append(refs, codeRef{pc: pc, target: uint64(int64(next) + a.Disp), kind: refData})
+				case a.Base == 0 && a.Index == 0 && a.Disp > 0:
+					// Direct addressing used by 32 bit binaries.
+					refs = append(refs, codeRef{pc: pc, target: uint64(a.Disp), kind: refData})
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// arm64Refs decodes the references made by arm64 instructions. Only the
+// instructions used by the Go compiler to reference code and data are
+// decoded:
+//
+//	BL   label
+//	B    label
+//	ADR  Xd, label
+//	ADRP Xd, page
+//	ADD  Xd, Xn, #imm          // After ADRP.
+//	LDR  Xt, [Xn, #imm]        // After ADRP.
+//	STR  Xt, [Xn, #imm]        // After ADRP.
+func arm64Refs(buf []byte, start uint64, order binary.ByteOrder) []codeRef {
+	var refs []codeRef
+	end := start + uint64(len(buf))
+
+	// The page loaded by the last ADRP instruction. The Go compiler emits the
+	// instruction consuming the page directly after the ADRP instruction.
+	var adrpReg uint32
+	var adrpPage uint64
+	adrpValid := false
+
+	for i := 0; i+4 <= len(buf); i += 4 {
+		pc := start + uint64(i)
+		x := order.Uint32(buf[i:])
+
+		lastValid := adrpValid
+		adrpValid = false
+
+		switch {
+		case x&0xfc000000 == 0x94000000, x&0xfc000000 == 0x14000000:
+			// BL and B with a 26 bit signed word offset.
+			off := int64(int32(x<<6)>>6) * 4
+			target := uint64(int64(pc) + off)
+			if x&0x80000000 != 0 {
+				refs = append(refs, codeRef{pc: pc, target: target, kind: refCall})
+			} else if target < start || target >= end {
+				refs = append(refs, codeRef{pc: pc, target: target, kind: refJump})
+			}
+
+		case x&0x9f000000 == 0x10000000, x&0x9f000000 == 0x90000000:
+			// ADR and ADRP.
+			imm := int64(int32((x>>5&0x7ffff)<<2|x>>29&0x3) << 11 >> 11)
+			rd := x & 0x1f
+			if x&0x80000000 == 0 {
+				refs = append(refs, codeRef{pc: pc, target: uint64(int64(pc) + imm), kind: refData})
+				continue
+			}
+			adrpReg = rd
+			adrpPage = uint64(int64(pc&^0xfff) + imm<<12)
+			adrpValid = true
+
+		case x&0xff800000 == 0x91000000:
+			// ADD (immediate), 64 bit.
+			rn := x >> 5 & 0x1f
+			if !lastValid || rn != adrpReg {
+				continue
+			}
+			imm := uint64(x >> 10 & 0xfff)
+			if x>>22&1 != 0 {
+				imm <<= 12
+			}
+			refs = append(refs, codeRef{pc: pc, target: adrpPage + imm, kind: refData})
+
+		case x&0x3b000000 == 0x39000000:
+			// Load and store register (unsigned immediate).
+			rn := x >> 5 & 0x1f
+			if !lastValid || rn != adrpReg {
+				continue
+			}
+			scale := x >> 30
+			if x>>26&1 != 0 && x>>23&1 != 0 {
+				// 128 bit SIMD register.
+				scale = 4
+			}
+			imm := uint64(x>>10&0xfff) << scale
+			refs = append(refs, codeRef{pc: pc, target: adrpPage + imm, kind: refData})
+		}
+	}
+	return refs
+}
+
+// x86CondBranchOps are the x86 conditional branch instructions.
+var x86CondBranchOps = map[x86asm.Op]bool{
+	x86asm.JA: true, x86asm.JAE: true, x86asm.JB: true, x86asm.JBE: true,
+	x86asm.JE: true, x86asm.JNE: true, x86asm.JG: true, x86asm.JGE: true,
+	x86asm.JL: true, x86asm.JLE: true, x86asm.JO: true, x86asm.JNO: true,
+	x86asm.JP: true, x86asm.JNP: true, x86asm.JS: true, x86asm.JNS: true,
+	x86asm.JCXZ: true, x86asm.JECXZ: true, x86asm.JRCXZ: true,
+	x86asm.LOOP: true, x86asm.LOOPE: true, x86asm.LOOPNE: true,
+}
+
+func x86CondBranches(buf []byte, mode int) int {
+	n := 0
+	s := 0
+	for s < len(buf) {
+		inst, err := x86asm.Decode(buf[s:], mode)
+		if err != nil {
+			s++
+			continue
+		}
+		s += inst.Len
+		if x86CondBranchOps[inst.Op] {
+			n++
+		}
+	}
+	return n
+}
+
+// arm64CondBranches counts the conditional branch instructions:
+//
+//	B.cond label
+//	CBZ    Rt, label
+//	CBNZ   Rt, label
+//	TBZ    Rt, #bit, label
+//	TBNZ   Rt, #bit, label
+func arm64CondBranches(buf []byte, order binary.ByteOrder) int {
+	n := 0
+	for i := 0; i+4 <= len(buf); i += 4 {
+		x := order.Uint32(buf[i:])
+		switch {
+		case x&0xff000010 == 0x54000000:
+			// B.cond.
+			n++
+		case x&0x7e000000 == 0x34000000:
+			// CBZ and CBNZ.
+			n++
+		case x&0x7e000000 == 0x36000000:
+			// TBZ and TBNZ.
+			n++
+		}
+	}
+	return n
+}
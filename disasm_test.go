@@ -0,0 +1,138 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"debug/gosym"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestX86Refs(t *testing.T) {
+	assert := assert.New(t)
+
+	code := []byte{
+		0x48, 0x8d, 0x05, 0x10, 0x00, 0x00, 0x00, // lea rax, [rip+0x10]
+		0xe8, 0x00, 0x01, 0x00, 0x00, // call +0x100
+		0x74, 0x00, // je +0 (internal jump)
+		0xe9, 0x00, 0x10, 0x00, 0x00, // jmp +0x1000
+	}
+	refs := x86Refs(code, 0x1000, 64)
+
+	assert.Equal([]codeRef{
+		{pc: 0x1000, target: 0x1017, kind: refData},
+		{pc: 0x1007, target: 0x110c, kind: refCall},
+		{pc: 0x100e, target: 0x2013, kind: refJump},
+	}, refs)
+}
+
+func TestARM64Refs(t *testing.T) {
+	assert := assert.New(t)
+
+	insts := []uint32{
+		0x94000004, // bl +0x10
+		0xb0000000, // adrp x0, +0x1000
+		0x91004000, // add x0, x0, #0x10
+		0xb0000001, // adrp x1, +0x1000
+		0xf9400421, // ldr x1, [x1, #8]
+		0x17fffffb, // b -0x14 (internal jump)
+	}
+	code := make([]byte, 4*len(insts))
+	for i, inst := range insts {
+		binary.LittleEndian.PutUint32(code[i*4:], inst)
+	}
+	refs := arm64Refs(code, 0x10000, binary.LittleEndian)
+
+	assert.Equal([]codeRef{
+		{pc: 0x10000, target: 0x10010, kind: refCall},
+		{pc: 0x10008, target: 0x11010, kind: refData},
+		{pc: 0x10010, target: 0x11008, kind: refData},
+	}, refs)
+}
+
+func TestCondBranches(t *testing.T) {
+	assert := assert.New(t)
+
+	code := []byte{
+		0x74, 0x00, // je +0
+		0x75, 0x00, // jne +0
+		0xeb, 0x00, // jmp +0
+		0xe8, 0x00, 0x00, 0x00, 0x00, // call +0
+		0x0f, 0x8c, 0x00, 0x00, 0x00, 0x00, // jl +0
+	}
+	assert.Equal(3, x86CondBranches(code, 64))
+
+	insts := []uint32{
+		0x54000040, // b.eq +8
+		0xb4000040, // cbz x0, +8
+		0x37000040, // tbnz w0, #0, +8
+		0x14000001, // b +4
+		0x94000001, // bl +4
+	}
+	arm := make([]byte, 4*len(insts))
+	for i, inst := range insts {
+		binary.LittleEndian.PutUint32(arm[i*4:], inst)
+	}
+	assert.Equal(3, arm64CondBranches(arm, binary.LittleEndian))
+}
+
+func TestFunctionDegrees(t *testing.T) {
+	code := []byte{
+		// main.a
+		0xe8, 0x0b, 0x00, 0x00, 0x00, // call main.b
+		0xe8, 0x16, 0x00, 0x00, 0x00, // call main.c
+		0xe8, 0x11, 0x00, 0x00, 0x00, // call main.c
+		0xc3, // ret
+		// main.b
+		0xe8, 0x0b, 0x00, 0x00, 0x00, // call main.c
+		0xe8, 0xf6, 0xff, 0xff, 0xff, // call main.b
+		0xc3, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc,
+		// main.c
+		0xc3, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc,
+		0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc,
+	}
+	a := &Function{Name: "a", Offset: 0x1000, End: 0x1010, PackageName: "main"}
+	b := &Function{Name: "b", Offset: 0x1010, End: 0x1020, PackageName: "main"}
+	c := &Function{Name: "c", Offset: 0x1020, End: 0x1030, PackageName: "main"}
+	f := &GoFile{
+		FileInfo: &FileInfo{Arch: ArchAMD64, WordSize: intSize64},
+		fh: &mockFileHandler{
+			mGetSectionDataFromAddress: func(uint64) (uint64, []byte, error) {
+				return 0x1000, code, nil
+			},
+		},
+		pclntab: &gosym.Table{Funcs: []gosym.Func{
+			{Entry: 0x1000, End: 0x1010, Sym: &gosym.Sym{Name: "main.a"}},
+			{Entry: 0x1010, End: 0x1020, Sym: &gosym.Sym{Name: "main.b"}},
+			{Entry: 0x1020, End: 0x1030, Sym: &gosym.Sym{Name: "main.c"}},
+		}},
+		pkgs: []*Package{{Name: "main", Functions: []*Function{a, b, c}}},
+	}
+	// The packages are already set.
+	f.initPackagesDone = true
+
+	degrees, err := f.FunctionDegrees()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Degree{
+		"main.a": {In: 0, Out: 2},
+		"main.b": {In: 1, Out: 1},
+		"main.c": {In: 2, Out: 0},
+	}, degrees)
+}
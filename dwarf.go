@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"debug/dwarf"
 	"encoding/binary"
+	"fmt"
 )
 
 const (
@@ -40,6 +41,53 @@ func getBuildVersionFromDwarf(fh fileHandler) (string, bool) {
 	return getDwarfString(fh, getDwarfStringCheck("runtime.buildVersion"))
 }
 
+// GetDWARFProducer returns the producer strings of the compilation units in
+// the DWARF data. The producer string records the compiler used for the
+// compilation unit. For Go, it includes the compiler version and some of the
+// flags used, for example "Go cmd/compile go1.21.0; regabi". Compilation units
+// produced by other compilers, for example C code linked in via cgo, are also
+// included. Each unique producer string is only returned once.
+func (f *GoFile) GetDWARFProducer() ([]string, error) {
+	data, err := f.fh.getDwarf()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DWARF data: %w", err)
+	}
+	return getDwarfProducers(data)
+}
+
+func getDwarfProducers(data *dwarf.Data) ([]string, error) {
+	var producers []string
+	seen := make(map[string]struct{})
+
+	r := data.Reader()
+	for {
+		entry, err := r.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read DWARF entry: %w", err)
+		}
+		if entry == nil {
+			break
+		}
+		if entry.Tag != dwarf.TagCompileUnit {
+			r.SkipChildren()
+			continue
+		}
+		// We only need the compile unit entry, not its children.
+		r.SkipChildren()
+
+		producer, ok := entry.Val(dwarf.AttrProducer).(string)
+		if !ok || producer == "" {
+			continue
+		}
+		if _, ok := seen[producer]; ok {
+			continue
+		}
+		seen[producer] = struct{}{}
+		producers = append(producers, producer)
+	}
+	return producers, nil
+}
+
 // DWARF entry plus any associated children
 type dwarfEntryPlus struct {
 	entry    *dwarf.Entry
@@ -0,0 +1,321 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"debug/dwarf"
+	"fmt"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Go specific DWARF attributes. Keep in sync with cmd/internal/dwarf.
+const (
+	dwAttrGoKind          dwarf.Attr = 0x2900
+	dwAttrGoKey           dwarf.Attr = 0x2901
+	dwAttrGoElem          dwarf.Attr = 0x2902
+	dwAttrGoEmbeddedField dwarf.Attr = 0x2903
+	dwAttrGoRuntimeType   dwarf.Attr = 0x2904
+)
+
+// GetDWARFTypes returns the types described by the DWARF data. This is an
+// alternative to GetTypes that doesn't depend on the runtime type metadata,
+// which can be used when the metadata has been trimmed but the DWARF data is
+// intact. It can also be used to cross-check the types returned by GetTypes.
+// The DWARF data doesn't describe the methods of the types or the struct
+// field tags so they are not set. The struct field offsets are set from the
+// member locations. Unlike the runtime type metadata, the type
+// names are qualified with the full package path, for example
+// "github.com/foo/bar.T" instead of "bar.T". The address of the type is the
+// address of the runtime type if it can be resolved, otherwise it's zero.
+func (f *GoFile) GetDWARFTypes() ([]*GoType, error) {
+	data, err := f.fh.getDwarf()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DWARF data: %w", err)
+	}
+
+	// The runtime type address is stored as an offset from the start of
+	// the types data in the moduledata.
+	var typesBase uint64
+	if f.initModuleData() == nil {
+		typesBase = f.moduledata.TypesAddr
+	}
+	return getDwarfTypes(data, typesBase)
+}
+
+// dwarfTypeEntry is a DWARF entry for a type and its children.
+type dwarfTypeEntry struct {
+	entry    *dwarf.Entry
+	children []*dwarf.Entry
+}
+
+func getDwarfTypes(data *dwarf.Data, typesBase uint64) ([]*GoType, error) {
+	// Collect all the top level entries in the Go compilation units. Only
+	// the entries with the Go kind attribute describe Go types. The other
+	// entries are needed to follow the type references.
+	entries := make(map[dwarf.Offset]*dwarfTypeEntry)
+	r := data.Reader()
+	for {
+		cu, err := r.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read DWARF entry: %w", err)
+		}
+		if cu == nil {
+			break
+		}
+		if cu.Tag != dwarf.TagCompileUnit || !cu.Children {
+			r.SkipChildren()
+			continue
+		}
+		if lang, _ := cu.Val(dwarf.AttrLanguage).(int64); lang != dwLangGo {
+			r.SkipChildren()
+			continue
+		}
+		for {
+			e, err := r.Next()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read DWARF entry: %w", err)
+			}
+			if e == nil || e.Tag == 0 {
+				break
+			}
+			te := &dwarfTypeEntry{entry: e}
+			entries[e.Offset] = te
+			if !e.Children {
+				continue
+			}
+			if e.Tag != dwarf.TagStructType && e.Tag != dwarf.TagSubroutineType && e.Tag != dwarf.TagArrayType {
+				r.SkipChildren()
+				continue
+			}
+			for {
+				c, err := r.Next()
+				if err != nil {
+					return nil, fmt.Errorf("failed to read DWARF entry: %w", err)
+				}
+				if c == nil || c.Tag == 0 {
+					break
+				}
+				te.children = append(te.children, c)
+				if c.Children {
+					r.SkipChildren()
+				}
+			}
+		}
+	}
+
+	types := make(map[dwarf.Offset]*GoType)
+	for off, te := range entries {
+		kind, ok := te.entry.Val(dwAttrGoKind).(int64)
+		if !ok {
+			continue
+		}
+		typ := &GoType{Kind: reflect.Kind(kind & kindMask)}
+		typ.Name, _ = te.entry.Val(dwarf.AttrName).(string)
+		if rt, ok := te.entry.Val(dwAttrGoRuntimeType).(uint64); ok && rt != 0 && typesBase != 0 {
+			typ.Addr = typesBase + rt
+		}
+		typ.PackagePath = dwarfTypePackage(typ.Name)
+		types[off] = typ
+	}
+
+	// resolve follows the type references until an entry describing a Go
+	// type is found.
+	resolve := func(v any) *GoType {
+		off, ok := v.(dwarf.Offset)
+		for i := 0; ok && i < 8; i++ {
+			if t, found := types[off]; found {
+				return t
+			}
+			te, found := entries[off]
+			if !found {
+				return nil
+			}
+			off, ok = te.entry.Val(dwarf.AttrType).(dwarf.Offset)
+		}
+		return nil
+	}
+
+	// Link the types together. The struct fields are copies of the field
+	// types so they are handled last, after all the other references have
+	// been resolved.
+	for off, typ := range types {
+		e := entries[off]
+		switch typ.Kind {
+		case reflect.Ptr:
+			typ.Element = resolve(e.entry.Val(dwarf.AttrType))
+		case reflect.Slice:
+			typ.Element = resolve(e.entry.Val(dwAttrGoElem))
+		case reflect.Chan:
+			typ.Element = resolve(e.entry.Val(dwAttrGoElem))
+			switch {
+			case strings.HasPrefix(typ.Name, "<-chan "):
+				typ.ChanDir = ChanRecv
+			case strings.HasPrefix(typ.Name, "chan<- "):
+				typ.ChanDir = ChanSend
+			default:
+				typ.ChanDir = ChanBoth
+			}
+		case reflect.Map:
+			typ.Key = resolve(e.entry.Val(dwAttrGoKey))
+			typ.Element = resolve(e.entry.Val(dwAttrGoElem))
+		case reflect.Array:
+			typ.Element = resolve(e.entry.Val(dwarf.AttrType))
+			for _, c := range e.children {
+				if n, ok := c.Val(dwarf.AttrCount).(int64); ok && c.Tag == dwarf.TagSubrangeType {
+					typ.Length = int(n)
+				}
+			}
+		}
+	}
+
+	// The return values are resolved via the pointer types so the functions
+	// are handled after the pointers.
+	for off, typ := range types {
+		if typ.Kind != reflect.Func {
+			continue
+		}
+		e := entries[off]
+		te := e
+		if e.entry.Tag == dwarf.TagTypedef {
+			ref, _ := e.entry.Val(dwarf.AttrType).(dwarf.Offset)
+			if te = entries[ref]; te == nil {
+				continue
+			}
+		}
+		// The arguments and the return values are not separated in
+		// the DWARF data so the name is used to find the split.
+		nargs, variadic := funcTypeArgCount(typ.Name)
+		typ.IsVariadic = variadic
+		i := 0
+		for _, c := range te.children {
+			if c.Tag != dwarf.TagFormalParameter {
+				continue
+			}
+			i++
+			arg := resolve(c.Val(dwarf.AttrType))
+			if arg == nil {
+				continue
+			}
+			if i <= nargs {
+				typ.FuncArgs = append(typ.FuncArgs, arg)
+				continue
+			}
+			// The linker describes the return values as pointers to
+			// the return value types.
+			if arg.Kind == reflect.Ptr && arg.Element != nil {
+				arg = arg.Element
+			}
+			typ.FuncReturnVals = append(typ.FuncReturnVals, arg)
+		}
+	}
+
+	for off, typ := range types {
+		if typ.Kind != reflect.Struct {
+			continue
+		}
+		for _, c := range entries[off].children {
+			if c.Tag != dwarf.TagMember {
+				continue
+			}
+			ft := resolve(c.Val(dwarf.AttrType))
+			if ft == nil {
+				continue
+			}
+			field := *ft
+			field.FieldName, _ = c.Val(dwarf.AttrName).(string)
+			field.FieldAnon, _ = c.Val(dwAttrGoEmbeddedField).(bool)
+			if loc, ok := c.Val(dwarf.AttrDataMemberLoc).(int64); ok && loc >= 0 {
+				field.FieldOffset = uint64(loc)
+			}
+			exportName := field.FieldName
+			if i := strings.LastIndexByte(exportName, '.'); i != -1 {
+				exportName = exportName[i+1:]
+			}
+			field.FieldExported = token.IsExported(strings.TrimLeft(exportName, "*"))
+			typ.Fields = append(typ.Fields, &field)
+		}
+	}
+
+	list := make([]*GoType, 0, len(types))
+	for _, t := range types {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Addr < list[j].Addr
+	})
+	return list, nil
+}
+
+// dwarfTypePackage returns the package path of a defined type, for example
+// "main" for "main.T". Type literals, like "[]main.T", don't have a package.
+func dwarfTypePackage(name string) string {
+	for _, p := range []string{"[", "*", "func(", "map[", "chan ", "<-chan ", "struct {", "interface {"} {
+		if strings.HasPrefix(name, p) {
+			return ""
+		}
+	}
+	// Strip the type arguments of generic types.
+	if i := strings.IndexByte(name, '['); i != -1 {
+		name = name[:i]
+	}
+	if i := strings.LastIndexByte(name, '.'); i > 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// funcTypeArgCount returns the number of arguments of the function type
+// from its name, for example 2 for "func(int, string) error". It also
+// returns true if the last argument is variadic.
+func funcTypeArgCount(name string) (int, bool) {
+	s, ok := strings.CutPrefix(name, "func(")
+	if !ok {
+		return 0, false
+	}
+	depth, n, start := 0, 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		case ')':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			arg := strings.TrimSpace(s[start:i])
+			if arg == "" {
+				return n, false
+			}
+			return n + 1, strings.HasPrefix(arg, "...")
+		case ',':
+			if depth == 0 {
+				n++
+				start = i + 1
+			}
+		}
+	}
+	return n, false
+}
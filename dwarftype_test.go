@@ -0,0 +1,72 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDwarfTypePackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"main.T", "main"},
+		{"github.com/foo/bar.T", "github.com/foo/bar"},
+		{"github.com/foo/bar.List[github.com/foo/baz.T]", "github.com/foo/bar"},
+		{"[]main.T", ""},
+		{"*main.T", ""},
+		{"map[string]main.T", ""},
+		{"func(main.T) error", ""},
+		{"chan main.T", ""},
+		{"<-chan main.T", ""},
+		{"struct { main.T }", ""},
+		{"int", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, dwarfTypePackage(test.name))
+		})
+	}
+}
+
+func TestFuncTypeArgCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     int
+		variadic bool
+	}{
+		{"func()", 0, false},
+		{"func() error", 0, false},
+		{"func(int)", 1, false},
+		{"func(int, string) error", 2, false},
+		{"func(string, ...interface {})", 2, true},
+		{"func(func(int, int) bool, map[string]int) (int, error)", 2, false},
+		{"func(struct { a, b int }, [2]int)", 2, false},
+		{"main.T", 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, variadic := funcTypeArgCount(test.name)
+			assert.Equal(t, test.args, n)
+			assert.Equal(t, test.variadic, variadic)
+		})
+	}
+}
@@ -23,15 +23,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 )
 
-func openELF(r io.ReaderAt) (*elfFile, error) {
+func openELF(r io.ReaderAt, opts Options) (*elfFile, error) {
 	f, err := elf.NewFile(r)
 	if err != nil {
 		return nil, fmt.Errorf("error when parsing the ELF file: %w", err)
 	}
-	ret := &elfFile{file: f, reader: r}
+	ret := &elfFile{file: f, reader: r, maxSectionBytes: opts.MaxSectionBytes, ownsReader: !opts.KeepReaderOpen}
 	ret.getsymtab = sync.OnceValues(ret.initSymTab)
 	return ret, nil
 }
@@ -39,9 +40,21 @@ func openELF(r io.ReaderAt) (*elfFile, error) {
 var _ fileHandler = (*elfFile)(nil)
 
 type elfFile struct {
-	file      *elf.File
-	reader    io.ReaderAt
-	getsymtab func() (map[string]Symbol, error)
+	file            *elf.File
+	reader          io.ReaderAt
+	getsymtab       func() (map[string]Symbol, error)
+	maxSectionBytes uint64
+	// ownsReader is true if the reader should be closed together with the
+	// file.
+	ownsReader bool
+}
+
+// sectionData reads the section's data if it's within the size limit.
+func (e *elfFile) sectionData(s *elf.Section) ([]byte, error) {
+	if err := checkSectionSize(s.Name, s.Size, e.maxSectionBytes); err != nil {
+		return nil, err
+	}
+	return s.Data()
 }
 
 func (e *elfFile) initSymTab() (map[string]Symbol, error) {
@@ -76,6 +89,17 @@ func (e *elfFile) getSymbol(name string) (Symbol, error) {
 	return sym, nil
 }
 
+func (e *elfFile) getSymbolNames() ([]string, error) {
+	symm, err := e.getsymtab()
+	if errors.Is(err, ErrSymbolNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return symbolNames(symm), nil
+}
+
 func (e *elfFile) getParsedFile() any {
 	return e.file
 }
@@ -85,9 +109,14 @@ func (e *elfFile) getReader() io.ReaderAt {
 }
 
 func (e *elfFile) Close() error {
-	err := e.file.Close()
-	if err != nil {
-		return err
+	if e.file != nil {
+		err := e.file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if !e.ownsReader {
+		return nil
 	}
 	return tryClose(e.reader)
 }
@@ -97,7 +126,7 @@ func (e *elfFile) getRData() ([]byte, error) {
 	if section == nil {
 		return nil, ErrSectionDoesNotExist
 	}
-	return section.Data()
+	return e.sectionData(section)
 }
 
 func (e *elfFile) getCodeSection() (uint64, []byte, error) {
@@ -105,13 +134,21 @@ func (e *elfFile) getCodeSection() (uint64, []byte, error) {
 	if section == nil {
 		return 0, nil, ErrSectionDoesNotExist
 	}
-	data, err := section.Data()
+	data, err := e.sectionData(section)
 	if err != nil {
 		return 0, nil, fmt.Errorf("error when getting the code section: %w", err)
 	}
 	return section.Addr, data, nil
 }
 
+func (e *elfFile) getCodeSectionReader() (uint64, io.ReaderAt, int64, error) {
+	section := e.file.Section(".text")
+	if section == nil {
+		return 0, nil, 0, ErrSectionDoesNotExist
+	}
+	return section.Addr, section, int64(section.Size), nil
+}
+
 func (e *elfFile) getPCLNTABData() (uint64, []byte, error) {
 	// If the standard linker was used when linking the Go binary, the pclntab is located
 	// in its own section in the ELF. We first check the section used when using the default
@@ -131,13 +168,17 @@ func (e *elfFile) getPCLNTABData() (uint64, []byte, error) {
 
 	// For files that have been linked with an external linker, the table is located
 	// in the .data.rel.ro section. Because it's not in its own section, we will have to
-	// search for it in the section.
+	// search for it in the section. Some custom-linked binaries merge the read-only data
+	// into the code section, so the table is searched for in the .text section instead.
 	start, data, err := e.getSectionData(".data.rel.ro")
+	if errors.Is(err, ErrSectionDoesNotExist) && e.file.Section(".rodata") == nil {
+		start, data, err = e.getCodeSection()
+	}
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed to get section: .data.rel.ro: %w", err)
 	}
 
-	buf, err := searchSectionForTab(data, e.file.FileHeader.ByteOrder)
+	buf, _, err := searchSectionForTab(data, e.file.FileHeader.ByteOrder)
 	if err != nil {
 		return 0, nil, fmt.Errorf("error when search for pclntab: %w", err)
 	}
@@ -151,8 +192,10 @@ func (e *elfFile) getPCLNTABData() (uint64, []byte, error) {
 	return vaddr, buf, err
 }
 
-func (e *elfFile) moduledataSection() string {
-	return ".noptrdata"
+func (e *elfFile) moduledataSections() []string {
+	// The .bss section is not stored in the file, so it can't hold the
+	// initialized moduledata.
+	return []string{".noptrdata", ".data"}
 }
 
 func (e *elfFile) getSectionDataFromAddress(address uint64) (uint64, []byte, error) {
@@ -163,7 +206,7 @@ func (e *elfFile) getSectionDataFromAddress(address uint64) (uint64, []byte, err
 		}
 
 		if section.Addr <= address && address < (section.Addr+section.Size) {
-			data, err := section.Data()
+			data, err := e.sectionData(section)
 			return section.Addr, data, err
 		}
 	}
@@ -175,7 +218,7 @@ func (e *elfFile) getSectionData(name string) (uint64, []byte, error) {
 	if section == nil {
 		return 0, nil, ErrSectionDoesNotExist
 	}
-	data, err := section.Data()
+	data, err := e.sectionData(section)
 	return section.Addr, data, err
 }
 
@@ -199,6 +242,19 @@ func (e *elfFile) getFileInfo() *FileInfo {
 		arch = ArchAMD64
 	case elf.EM_ARM:
 		arch = ArchARM
+	case elf.EM_AARCH64:
+		arch = ArchARM64
+	case elf.EM_RISCV:
+		arch = ArchRISCV64
+	case elf.EM_PPC64:
+		arch = ArchPPC64
+		if e.file.FileHeader.Data == elf.ELFDATA2LSB {
+			arch = ArchPPC64LE
+		}
+	case elf.EM_S390:
+		arch = ArchS390X
+	case elf.EM_LOONGARCH:
+		arch = ArchLoong64
 	}
 
 	return &FileInfo{
@@ -209,6 +265,16 @@ func (e *elfFile) getFileInfo() *FileInfo {
 	}
 }
 
+// isStatic returns true if the file doesn't request a program interpreter.
+func (e *elfFile) isStatic() bool {
+	for _, p := range e.file.Progs {
+		if p.Type == elf.PT_INTERP {
+			return false
+		}
+	}
+	return true
+}
+
 func (e *elfFile) getBuildID() (string, error) {
 	_, data, err := e.getSectionData(".note.go.buildid")
 	// If the note section does not exist, we just ignore the build id.
@@ -221,6 +287,119 @@ func (e *elfFile) getBuildID() (string, error) {
 	return parseBuildIDFromElf(data, e.file.ByteOrder)
 }
 
+func (e *elfFile) getSections() ([]Section, error) {
+	var sections []Section
+	for _, s := range e.file.Sections {
+		if s.Type == elf.SHT_NULL {
+			continue
+		}
+		sec := Section{
+			Name:           s.Name,
+			VirtualAddress: s.Addr,
+			Size:           s.Size,
+			FileOffset:     s.Offset,
+		}
+		if s.Flags&elf.SHF_ALLOC != 0 {
+			sec.Permissions |= SectionRead
+		}
+		if s.Flags&elf.SHF_WRITE != 0 {
+			sec.Permissions |= SectionWrite
+		}
+		if s.Flags&elf.SHF_EXECINSTR != 0 {
+			sec.Permissions |= SectionExecute
+		}
+		sections = append(sections, sec)
+	}
+	return sections, nil
+}
+
+func (e *elfFile) getEntryPoint() (uint64, error) {
+	return e.file.Entry, nil
+}
+
+func (e *elfFile) getImportedLibraries() ([]string, error) {
+	libs, err := e.file.ImportedLibraries()
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the imported libraries: %w", err)
+	}
+	return libs, nil
+}
+
+// getDynamicImports returns the undefined symbols in the dynamic symbol
+// table. The library is only known for versioned symbols.
+func (e *elfFile) getDynamicImports() ([]DynamicImport, error) {
+	syms, err := e.file.ImportedSymbols()
+	if err != nil {
+		if errors.Is(err, elf.ErrNoSymbols) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error when getting the imported symbols: %w", err)
+	}
+	imports := make([]DynamicImport, 0, len(syms))
+	for _, s := range syms {
+		imports = append(imports, DynamicImport{Name: s.Name, Library: s.Library, Version: s.Version})
+	}
+	return imports, nil
+}
+
+// getExports returns the defined global and weak symbols in the dynamic
+// symbol table that are visible outside of the library.
+func (e *elfFile) getExports() ([]Export, error) {
+	syms, err := e.file.DynamicSymbols()
+	if err != nil {
+		if errors.Is(err, elf.ErrNoSymbols) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error when getting the dynamic symbols: %w", err)
+	}
+	var exports []Export
+	for _, s := range syms {
+		if s.Section == elf.SHN_UNDEF {
+			continue
+		}
+		switch elf.ST_BIND(s.Info) {
+		case elf.STB_GLOBAL, elf.STB_WEAK:
+		default:
+			continue
+		}
+		switch elf.ST_TYPE(s.Info) {
+		case elf.STT_FUNC, elf.STT_OBJECT:
+		default:
+			continue
+		}
+		switch elf.ST_VISIBILITY(s.Other) {
+		case elf.STV_DEFAULT, elf.STV_PROTECTED:
+		default:
+			continue
+		}
+		exports = append(exports, Export{Name: s.Name, Address: s.Value})
+	}
+	return exports, nil
+}
+
 func (e *elfFile) getDwarf() (*dwarf.Data, error) {
+	for _, s := range e.file.Sections {
+		if !strings.HasPrefix(s.Name, ".debug_") && !strings.HasPrefix(s.Name, ".zdebug_") {
+			continue
+		}
+		// The size of compressed sections is their uncompressed size.
+		err := checkDwarfSectionSize(s.Name, max(s.Size, s.FileSize), e.reader, int64(s.Offset), e.maxSectionBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return e.file.DWARF()
 }
+
+// gnuBuildIDRange returns the file offset and the size of the descriptor of
+// the GNU build ID note. The linker can derive the GNU build ID from the Go
+// build ID so it's written after the build ID. If the file doesn't have the
+// note, a size of zero is returned.
+func (e *elfFile) gnuBuildIDRange() (uint64, uint64) {
+	sect := e.file.Section(".note.gnu.build-id")
+	// The descriptor follows the 3-word note header and the "GNU\x00" name.
+	if sect == nil || sect.Type == elf.SHT_NOBITS || sect.Size < 16 {
+		return 0, 0
+	}
+	return sect.Offset + 16, sect.Size - 16
+}
@@ -0,0 +1,306 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// WriteWithSymbols writes a copy of the binary to w with a symbol table that
+// maps the given addresses to names. This allows tools like IDA and Ghidra to
+// use the names recovered by the library. If the binary already has a symbol
+// table, the symbols at the given addresses are renamed and the missing
+// symbols are added. The rest of the file is left unchanged; the new tables
+// are appended to the end of the file.
+// Only ELF files are supported. For other file types, ErrUnsupportedFile is
+// returned.
+func (f *GoFile) WriteWithSymbols(w io.Writer, names map[uint64]string) error {
+	e, ok := f.fh.(*elfFile)
+	if !ok {
+		return ErrUnsupportedFile
+	}
+
+	// Use the function sizes from the pclntab if it's available.
+	sizes := make(map[uint64]uint64)
+	if err := f.initPackages(); err == nil {
+		for addr, fn := range f.functionsByEntry() {
+			sizes[addr] = fn.End - fn.Offset
+		}
+	}
+
+	size, err := readerSize(e.reader)
+	if err != nil {
+		return fmt.Errorf("failed to get the file size: %w", err)
+	}
+	// The whole file is read into memory.
+	if err = checkSectionSize("file", uint64(size), e.maxSectionBytes); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.NewSectionReader(e.reader, 0, size))
+	if err != nil {
+		return fmt.Errorf("failed to read the file: %w", err)
+	}
+	out, err := e.withSymbols(data, names, sizes)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// elfSectionHeader is a section header that is written to the file.
+type elfSectionHeader struct {
+	name string
+	elf.SectionHeader
+}
+
+// withSymbols returns a copy of the file data with a new symbol table.
+func (e *elfFile) withSymbols(data []byte, names map[uint64]string, sizes map[uint64]uint64) ([]byte, error) {
+	is64 := e.file.Class == elf.ELFCLASS64
+	order := e.file.ByteOrder
+	secs := e.file.Sections
+	if len(secs) == 0 {
+		return nil, errors.New("the file has no section headers")
+	}
+	if len(secs)+2 >= int(elf.SHN_LORESERVE) {
+		return nil, errors.New("too many sections in the file")
+	}
+
+	var shstrndx int
+	if is64 {
+		shstrndx = int(order.Uint16(data[0x3e:]))
+	} else {
+		shstrndx = int(order.Uint16(data[0x32:]))
+	}
+
+	// Rename the existing symbols and add the missing ones.
+	existing, err := e.file.Symbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return nil, fmt.Errorf("error when getting the symbols: %w", err)
+	}
+	renamed := make(map[uint64]bool)
+	var locals, globals []elf.Symbol
+	for _, s := range existing {
+		typ := elf.ST_TYPE(s.Info)
+		if n, ok := names[s.Value]; ok && (typ == elf.STT_FUNC || typ == elf.STT_OBJECT) {
+			s.Name = n
+			renamed[s.Value] = true
+		}
+		if elf.ST_BIND(s.Info) == elf.STB_LOCAL {
+			locals = append(locals, s)
+		} else {
+			globals = append(globals, s)
+		}
+	}
+
+	addrs := make([]uint64, 0, len(names))
+	for addr := range names {
+		if !renamed[addr] {
+			addrs = append(addrs, addr)
+		}
+	}
+	slices.Sort(addrs)
+	for _, addr := range addrs {
+		shndx := elf.SHN_ABS
+		typ := elf.STT_OBJECT
+		for i, s := range secs {
+			if s.Flags&elf.SHF_ALLOC == 0 || addr < s.Addr || addr >= s.Addr+s.Size {
+				continue
+			}
+			shndx = elf.SectionIndex(i)
+			if s.Flags&elf.SHF_EXECINSTR != 0 {
+				typ = elf.STT_FUNC
+			}
+			break
+		}
+		globals = append(globals, elf.Symbol{
+			Name:    names[addr],
+			Info:    elf.ST_INFO(elf.STB_GLOBAL, typ),
+			Section: shndx,
+			Value:   addr,
+			Size:    sizes[addr],
+		})
+	}
+	syms := append(locals, globals...)
+
+	// Build the string and symbol tables.
+	strtab := []byte{0}
+	strOffs := make(map[string]uint32)
+	strOff := func(s string) uint32 {
+		if s == "" {
+			return 0
+		}
+		if off, ok := strOffs[s]; ok {
+			return off
+		}
+		off := uint32(len(strtab))
+		strtab = append(append(strtab, s...), 0)
+		strOffs[s] = off
+		return off
+	}
+	symtab := new(bytes.Buffer)
+	var entSize uint64
+	if is64 {
+		entSize = uint64(binary.Size(elf.Sym64{}))
+		_ = binary.Write(symtab, order, elf.Sym64{})
+		for _, s := range syms {
+			_ = binary.Write(symtab, order, elf.Sym64{
+				Name:  strOff(s.Name),
+				Info:  s.Info,
+				Other: s.Other,
+				Shndx: uint16(s.Section),
+				Value: s.Value,
+				Size:  s.Size,
+			})
+		}
+	} else {
+		entSize = uint64(binary.Size(elf.Sym32{}))
+		_ = binary.Write(symtab, order, elf.Sym32{})
+		for _, s := range syms {
+			_ = binary.Write(symtab, order, elf.Sym32{
+				Name:  strOff(s.Name),
+				Value: uint32(s.Value),
+				Size:  uint32(s.Size),
+				Info:  s.Info,
+				Other: s.Other,
+				Shndx: uint16(s.Section),
+			})
+		}
+	}
+
+	// Copy the section headers. The existing symbol and string tables are
+	// replaced, otherwise new sections are added.
+	hdrs := make([]elfSectionHeader, len(secs))
+	symIdx, strIdx := -1, -1
+	for i, s := range secs {
+		hdrs[i] = elfSectionHeader{name: s.Name, SectionHeader: s.SectionHeader}
+		// The size in the header is the size in the file.
+		hdrs[i].Size = s.FileSize
+		if s.Type == elf.SHT_SYMTAB {
+			symIdx = i
+			strIdx = int(s.Link)
+		}
+	}
+	if symIdx == -1 {
+		symIdx = len(hdrs)
+		hdrs = append(hdrs, elfSectionHeader{name: ".symtab"})
+	}
+	if strIdx <= 0 || strIdx >= len(secs) || strIdx == shstrndx {
+		strIdx = len(hdrs)
+		hdrs = append(hdrs, elfSectionHeader{name: ".strtab"})
+	}
+
+	shstrtab := []byte{0}
+	shstrOffs := make(map[string]uint32)
+	for _, h := range hdrs {
+		if _, ok := shstrOffs[h.name]; ok || h.name == "" {
+			continue
+		}
+		shstrOffs[h.name] = uint32(len(shstrtab))
+		shstrtab = append(append(shstrtab, h.name...), 0)
+	}
+
+	// Append the new tables to the end of the file.
+	out := bytes.NewBuffer(make([]byte, 0, len(data)+len(strtab)+symtab.Len()+len(shstrtab)+len(hdrs)*64+32))
+	out.Write(data)
+	align := func() {
+		for out.Len()%8 != 0 {
+			out.WriteByte(0)
+		}
+	}
+
+	align()
+	hdrs[strIdx].SectionHeader = elf.SectionHeader{
+		Type:      elf.SHT_STRTAB,
+		Offset:    uint64(out.Len()),
+		Size:      uint64(len(strtab)),
+		Addralign: 1,
+	}
+	out.Write(strtab)
+
+	align()
+	symAlign := uint64(4)
+	if is64 {
+		symAlign = 8
+	}
+	hdrs[symIdx].SectionHeader = elf.SectionHeader{
+		Type:      elf.SHT_SYMTAB,
+		Offset:    uint64(out.Len()),
+		Size:      uint64(symtab.Len()),
+		Link:      uint32(strIdx),
+		Info:      uint32(len(locals) + 1),
+		Addralign: symAlign,
+		Entsize:   entSize,
+	}
+	out.Write(symtab.Bytes())
+
+	if shstrndx > 0 && shstrndx < len(hdrs) {
+		hdrs[shstrndx].Offset = uint64(out.Len())
+		hdrs[shstrndx].Size = uint64(len(shstrtab))
+	}
+	out.Write(shstrtab)
+
+	align()
+	shoff := uint64(out.Len())
+	for _, h := range hdrs {
+		if is64 {
+			_ = binary.Write(out, order, elf.Section64{
+				Name:      shstrOffs[h.name],
+				Type:      uint32(h.Type),
+				Flags:     uint64(h.Flags),
+				Addr:      h.Addr,
+				Off:       h.Offset,
+				Size:      h.Size,
+				Link:      h.Link,
+				Info:      h.Info,
+				Addralign: h.Addralign,
+				Entsize:   h.Entsize,
+			})
+		} else {
+			_ = binary.Write(out, order, elf.Section32{
+				Name:      shstrOffs[h.name],
+				Type:      uint32(h.Type),
+				Flags:     uint32(h.Flags),
+				Addr:      uint32(h.Addr),
+				Off:       uint32(h.Offset),
+				Size:      uint32(h.Size),
+				Link:      h.Link,
+				Info:      h.Info,
+				Addralign: uint32(h.Addralign),
+				Entsize:   uint32(h.Entsize),
+			})
+		}
+	}
+
+	// Point the file header to the new section header table.
+	buf := out.Bytes()
+	if is64 {
+		order.PutUint64(buf[0x28:], shoff)
+		order.PutUint16(buf[0x3c:], uint16(len(hdrs)))
+	} else {
+		order.PutUint32(buf[0x20:], uint32(shoff))
+		order.PutUint16(buf[0x30:], uint16(len(hdrs)))
+	}
+	return buf, nil
+}
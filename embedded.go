@@ -0,0 +1,212 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// buildInfoMagic is the magic at the start of the buildinfo structure.
+var buildInfoMagic = []byte("\xff Go buildinf:")
+
+// embeddedScanChunkSize is the number of bytes read at a time when scanning
+// for an embedded binary.
+const embeddedScanChunkSize = 1 << 20
+
+// FindEmbeddedGoBinary scans the file for a Go binary embedded after the
+// initial file header. This is the case for binaries distributed inside
+// AppImages or self-extracting archives where the Go binary is appended to
+// a loader stub. The offset of the first embedded ELF, PE or Mach-O file
+// that contains Go metadata is returned. The offset can be passed to OpenAt
+// to analyze the embedded binary. ErrNoEmbeddedGoBinary is returned if no
+// embedded Go binary is found. The section size limit the file was opened
+// with also applies to the embedded binaries.
+func (f *GoFile) FindEmbeddedGoBinary() (int64, error) {
+	return findEmbeddedGoBinary(f.fh.getReader(), Options{MaxSectionBytes: f.maxSectionBytes})
+}
+
+// OpenAt opens the file and returns a handler to the binary starting at the
+// given offset in the file. This can be used together with
+// FindEmbeddedGoBinary to analyze a binary embedded in another file.
+func OpenAt(filePath string, offset int64) (*GoFile, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if offset < 0 || offset >= fi.Size() {
+		_ = f.Close()
+		return nil, fmt.Errorf("offset 0x%x is outside the file", offset)
+	}
+	gofile, err := OpenReader(&sectionFile{
+		SectionReader: io.NewSectionReader(f, offset, fi.Size()-offset),
+		file:          f,
+	})
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return gofile, nil
+}
+
+// sectionFile is a section of a file. Closing it closes the underlying file.
+type sectionFile struct {
+	*io.SectionReader
+	file *os.File
+}
+
+func (s *sectionFile) Close() error {
+	return s.file.Close()
+}
+
+func findEmbeddedGoBinary(r io.ReaderAt, opts Options) (int64, error) {
+	size, err := readerSize(r)
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, embeddedScanChunkSize+maxMagicBufLen-1)
+	// Start after the magic of the outer file.
+	pos := int64(1)
+	for {
+		n, err := r.ReadAt(buf, pos)
+		if n == 0 {
+			if err != nil && !errors.Is(err, io.EOF) {
+				return 0, err
+			}
+			return 0, ErrNoEmbeddedGoBinary
+		}
+		chunk := buf[:n]
+
+		for i := 0; i < len(chunk) && i < embeddedScanChunkSize; i++ {
+			if !isEmbeddedCandidate(r, pos+int64(i), chunk[i:]) {
+				continue
+			}
+			if isEmbeddedGoBinary(r, pos+int64(i), size, opts) {
+				return pos + int64(i), nil
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, ErrNoEmbeddedGoBinary
+			}
+			return 0, err
+		}
+		pos += embeddedScanChunkSize
+	}
+}
+
+// isEmbeddedCandidate returns true if the data at the offset starts with a
+// file header supported by the library.
+func isEmbeddedCandidate(r io.ReaderAt, off int64, buf []byte) bool {
+	switch {
+	case fileMagicMatch(buf, elfMagic),
+		fileMagicMatch(buf, machoMagic1),
+		fileMagicMatch(buf, machoMagic2),
+		fileMagicMatch(buf, machoMagic3),
+		fileMagicMatch(buf, machoMagic4):
+		return true
+	case fileMagicMatch(buf, peMagic):
+		// The DOS magic is too short to be meaningful on its own. Check that
+		// the DOS header points to a PE signature.
+		var hdr [4]byte
+		if _, err := r.ReadAt(hdr[:], off+0x3c); err != nil {
+			return false
+		}
+		peOff := int64(binary.LittleEndian.Uint32(hdr[:]))
+		if _, err := r.ReadAt(hdr[:], off+peOff); err != nil {
+			return false
+		}
+		return bytes.Equal(hdr[:], []byte{'P', 'E', 0, 0})
+	}
+	return false
+}
+
+// isEmbeddedGoBinary returns true if the data at the offset can be parsed as
+// a Go binary. The size is the size of the reader. Only the candidates with
+// Go metadata in their headers are fully parsed.
+func isEmbeddedGoBinary(r io.ReaderAt, off, size int64, opts Options) bool {
+	if off < 0 || off >= size {
+		return false
+	}
+	sr := io.NewSectionReader(r, off, size-off)
+	if !hasGoMetadata(sr, opts.MaxSectionBytes) {
+		return false
+	}
+	opts.KeepReaderOpen = true
+	f, err := OpenReaderWithOptions(sr, opts)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	if f.BuildInfo != nil {
+		return true
+	}
+	return f.initPclntab() == nil
+}
+
+// hasGoMetadata does a cheap check of the file headers. It returns true if
+// the file has a buildinfo or pclntab section. PE files don't have dedicated
+// sections for them, so the sections they are stored in are searched for the
+// magic values instead. Sections larger than the limit are not searched.
+func hasGoMetadata(r *io.SectionReader, limit uint64) bool {
+	if f, err := elf.NewFile(r); err == nil {
+		for _, name := range []string{".go.buildinfo", ".gopclntab", ".data.rel.ro.gopclntab"} {
+			if f.Section(name) != nil {
+				return true
+			}
+		}
+		return false
+	}
+	if f, err := macho.NewFile(r); err == nil {
+		return f.Section("__go_buildinfo") != nil || f.Section("__gopclntab") != nil
+	}
+	f, err := pe.NewFile(r)
+	if err != nil {
+		return false
+	}
+	for _, name := range []string{".data", ".rdata", ".text"} {
+		sec := f.Section(name)
+		if sec == nil || checkSectionSize(sec.Name, uint64(sec.Size), limit) != nil {
+			continue
+		}
+		data, err := sec.Data()
+		if err != nil {
+			continue
+		}
+		if bytes.Contains(data, buildInfoMagic) {
+			return true
+		}
+		if _, _, err := searchSectionForTab(data, nil); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsEmbeddedCandidate(t *testing.T) {
+	pe := make([]byte, 0x80)
+	copy(pe, peMagic)
+	pe[0x3c] = 0x40
+	copy(pe[0x40:], []byte{'P', 'E', 0, 0})
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected bool
+	}{
+		{"elf", []byte{0x7f, 'E', 'L', 'F', 2, 1, 1}, true},
+		{"macho", []byte{0xcf, 0xfa, 0xed, 0xfe, 0, 0}, true},
+		{"pe", pe, true},
+		{"dos magic without pe header", []byte{'M', 'Z', 0, 0, 0, 0}, false},
+		{"no magic", []byte{0, 1, 2, 3}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := bytes.NewReader(test.data)
+			assert.Equal(t, test.expected, isEmbeddedCandidate(r, 0, test.data))
+		})
+	}
+}
+
+func TestFindEmbeddedGoBinaryNotFound(t *testing.T) {
+	data := append([]byte{0x7f, 'E', 'L', 'F'}, bytes.Repeat([]byte{0x90}, 4096)...)
+	// A header that can't be parsed should not be reported.
+	data = append(data, elfMagic...)
+
+	_, err := findEmbeddedGoBinary(bytes.NewReader(data), Options{})
+	assert.ErrorIs(t, err, ErrNoEmbeddedGoBinary)
+}
+
+func TestFindEmbeddedGoBinary(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	bin, err := os.ReadFile(exe)
+	require.NoError(t, err)
+
+	// A container with a loader stub followed by the Go binary.
+	stub := append([]byte("#!/bin/sh\nexit 1\n"), make([]byte, 4077)...)
+	container := append(stub, bin...)
+	fp := filepath.Join(t.TempDir(), "container")
+	require.NoError(t, os.WriteFile(fp, container, 0o644))
+
+	off, err := findEmbeddedGoBinary(bytes.NewReader(container), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(stub)), off)
+
+	f, err := OpenAt(fp, off)
+	require.NoError(t, err)
+	defer f.Close()
+	expected, err := Open(exe)
+	require.NoError(t, err)
+	defer expected.Close()
+	assert.Equal(t, expected.BuildID, f.BuildID)
+	assert.Equal(t, expected.FileInfo, f.FileInfo)
+
+	_, err = OpenAt(fp, int64(len(container)))
+	assert.Error(t, err)
+	assert.False(t, isEmbeddedGoBinary(bytes.NewReader(container), int64(len(container)), int64(len(container)), Options{}))
+}
+
+func TestHasGoMetadata(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	bin, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.True(t, hasGoMetadata(io.NewSectionReader(bytes.NewReader(bin), 0, int64(len(bin))), 0))
+
+	// An ELF header without any sections.
+	hdr := make([]byte, 64)
+	copy(hdr, []byte{0x7f, 'E', 'L', 'F', 2, 1, 1})
+	binary.LittleEndian.PutUint16(hdr[16:], uint16(elf.ET_EXEC))
+	binary.LittleEndian.PutUint16(hdr[18:], uint16(elf.EM_X86_64))
+	binary.LittleEndian.PutUint32(hdr[20:], 1)
+	binary.LittleEndian.PutUint16(hdr[52:], 64)
+	assert.False(t, hasGoMetadata(io.NewSectionReader(bytes.NewReader(hdr), 0, int64(len(hdr))), 0))
+}
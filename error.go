@@ -30,9 +30,43 @@ var (
 	ErrNoGoVersionFound = errors.New("no goversion found")
 	// ErrNoPCLNTab is returned if no PCLN table can be located.
 	ErrNoPCLNTab = errors.New("no pclntab located")
+	// ErrUnsupportedPCLNTabVersion is returned if the functionality is not supported
+	// for the pclntab version used by the file.
+	ErrUnsupportedPCLNTabVersion = errors.New("unsupported pclntab version")
 	// ErrInvalidGoVersion is returned if the go version set for the file is either invalid
 	// or does not match a known version by the library.
 	ErrInvalidGoVersion = errors.New("invalid go version")
 	// ErrNoGoRootFound is returned if no goroot was found in the binary.
 	ErrNoGoRootFound = errors.New("no goroot found")
+	// ErrNoGoPathFound is returned if no GOPATH could be inferred from the binary.
+	ErrNoGoPathFound = errors.New("no gopath found")
+	// ErrArchNotSupported is returned if the functionality is not supported for the
+	// file's architecture.
+	ErrArchNotSupported = errors.New("architecture not supported")
+	// ErrNoEmbeddedGoBinary is returned if no Go binary is embedded in the file.
+	ErrNoEmbeddedGoBinary = errors.New("no embedded go binary found")
+	// ErrNoMinimumOSVersion is returned if the file has no minimum OS version.
+	ErrNoMinimumOSVersion = errors.New("no minimum os version found")
+	// ErrNoRichHeader is returned if the PE file has no Rich header.
+	ErrNoRichHeader = errors.New("no rich header found")
+	// ErrNoBuildID is returned if the file has no build ID.
+	ErrNoBuildID = errors.New("no build id found")
+	// ErrItabNotFound is returned if no itab exists for an interface and
+	// concrete type pair.
+	ErrItabNotFound = errors.New("itab not found")
+	// ErrFunctionNotFound is returned if an address is not within any of the
+	// functions in the pclntab.
+	ErrFunctionNotFound = errors.New("function not found")
+	// ErrSectionTooLarge is returned if a section is larger than the limit set
+	// by Options.MaxSectionBytes.
+	ErrSectionTooLarge = errors.New("section too large")
+	// ErrNoCoreDump is returned if the analysis requires a core dump but none
+	// was set by Options.Core.
+	ErrNoCoreDump = errors.New("no core dump")
+	// ErrGollvm is returned if the binary was compiled with gollvm. These
+	// binaries don't have the metadata emitted by the gc toolchain.
+	ErrGollvm = errors.New("binaries compiled with gollvm are not supported")
+	// ErrPackagesInitialized is returned if an option affecting the packages
+	// is set after the packages have been enumerated.
+	ErrPackagesInitialized = errors.New("packages already initialized")
 )
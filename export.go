@@ -0,0 +1,58 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Export is a symbol exported by the binary. Go binaries only export
+// symbols when built as a shared library with -buildmode=c-shared or
+// -buildmode=plugin, where the functions marked with "//export" and the
+// plugin's symbols are exported.
+type Export struct {
+	// Name is the name of the symbol as stored in the export table. On
+	// macOS, the C symbols have a leading underscore.
+	Name string `json:"name"`
+	// Address is the virtual address of the symbol.
+	Address uint64 `json:"address"`
+}
+
+// GetExports returns the symbols in the binary's export table, sorted by
+// their address and name. The exports are read from the export directory
+// for PE files, the global and weak dynamic symbols for ELF files and the
+// dyld export information for Mach-O files. Symbols re-exported from other
+// libraries and PE forwarders are not included since they don't have an
+// address in the binary. For executables, the result is usually empty.
+// For WebAssembly modules, the exported functions are returned with the PC
+// the Go linker assigned to them. Other exports, like the memory, are not
+// included.
+func (f *GoFile) GetExports() ([]Export, error) {
+	exports, err := f.fh.getExports()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(exports, func(a, b Export) int {
+		if a.Address != b.Address {
+			return cmp.Compare(a.Address, b.Address)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return exports, nil
+}
@@ -0,0 +1,153 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+var (
+	fatMagic   = []byte{0xca, 0xfe, 0xba, 0xbe}
+	fatMagic64 = []byte{0xca, 0xfe, 0xba, 0xbf}
+)
+
+// maxFatArches is the maximum number of images accepted in a universal
+// binary. Java class files use the same magic as universal binaries. Their
+// version is stored where the number of images is, and it's at least 45.
+const maxFatArches = 32
+
+// OpenFatMachO opens all the images in a Mach-O universal binary, also known
+// as a fat binary, and returns a handler for each of them. Universal binaries
+// hold the same program compiled for multiple architectures, for example
+// amd64 and arm64. The handlers are returned in the order the images are
+// stored in the binary. The reader is shared by the handlers and is never
+// closed by them, so it's up to the caller to close it once the handlers are
+// closed. Open and OpenReader can also open a universal binary, in which case
+// only the first image with Go metadata is opened.
+func OpenFatMachO(r io.ReaderAt, opts Options) ([]*GoFile, error) {
+	images, err := fatMachOSlices(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.KeepReaderOpen = true
+	files := make([]*GoFile, 0, len(images))
+	for i, s := range images {
+		magic := make([]byte, maxMagicBufLen)
+		if _, err = s.ReadAt(magic, 0); err != nil {
+			err = fmt.Errorf("error when reading the magic of image %d: %w", i, err)
+			break
+		}
+		if !fileMagicMatch(magic, machoMagic1) && !fileMagicMatch(magic, machoMagic2) &&
+			!fileMagicMatch(magic, machoMagic3) && !fileMagicMatch(magic, machoMagic4) {
+			err = fmt.Errorf("image %d is not a Mach-O file: %w", i, ErrUnsupportedFile)
+			break
+		}
+		var f *GoFile
+		f, err = OpenReaderWithOptions(&fatSliceReader{SectionReader: s, parent: r}, opts)
+		if err != nil {
+			err = fmt.Errorf("error when opening image %d: %w", i, err)
+			break
+		}
+		files = append(files, f)
+	}
+	if err != nil {
+		for _, f := range files {
+			_ = f.Close()
+		}
+		return nil, err
+	}
+	return files, nil
+}
+
+// openFatMachO opens the first image with Go metadata in the universal
+// binary. If none of the images have Go metadata, the first image is opened.
+func openFatMachO(r io.ReaderAt, opts Options) (*GoFile, error) {
+	files, err := OpenFatMachO(r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	chosen := files[0]
+	for _, f := range files {
+		if f.BuildInfo != nil || f.initPclntab() == nil {
+			chosen = f
+			break
+		}
+	}
+	for _, f := range files {
+		if f != chosen {
+			_ = f.Close()
+		}
+	}
+	// The image reader closes the universal binary's reader.
+	chosen.fh.(*machoFile).ownsReader = !opts.KeepReaderOpen
+	return chosen, nil
+}
+
+// fatMachOSlices returns the readers for the images in the universal binary.
+func fatMachOSlices(r io.ReaderAt) ([]*io.SectionReader, error) {
+	hdr := make([]byte, 8)
+	if _, err := r.ReadAt(hdr, 0); err != nil {
+		return nil, fmt.Errorf("error when reading the universal binary header: %w", err)
+	}
+	entrySize := 20
+	if fileMagicMatch(hdr, fatMagic64) {
+		entrySize = 32
+	} else if !fileMagicMatch(hdr, fatMagic) {
+		return nil, fmt.Errorf("not a universal binary: %w", ErrUnsupportedFile)
+	}
+	n := binary.BigEndian.Uint32(hdr[4:])
+	if n == 0 || n > maxFatArches {
+		return nil, fmt.Errorf("not a universal binary: %w", ErrUnsupportedFile)
+	}
+
+	buf := make([]byte, int(n)*entrySize)
+	if _, err := r.ReadAt(buf, int64(len(hdr))); err != nil {
+		return nil, fmt.Errorf("error when reading the universal binary header: %w", err)
+	}
+	images := make([]*io.SectionReader, 0, n)
+	for i := 0; i < int(n); i++ {
+		// The entries start with the CPU type and subtype.
+		e := buf[i*entrySize+8:]
+		var off, size uint64
+		if entrySize == 32 {
+			off, size = binary.BigEndian.Uint64(e), binary.BigEndian.Uint64(e[8:])
+		} else {
+			off, size = uint64(binary.BigEndian.Uint32(e)), uint64(binary.BigEndian.Uint32(e[4:]))
+		}
+		if off > 1<<62 || size > 1<<62 {
+			return nil, fmt.Errorf("invalid offset or size of image %d in the universal binary", i)
+		}
+		images = append(images, io.NewSectionReader(r, int64(off), int64(size)))
+	}
+	return images, nil
+}
+
+// fatSliceReader reads an image in a universal binary. Closing it closes
+// the reader of the universal binary.
+type fatSliceReader struct {
+	*io.SectionReader
+	parent io.ReaderAt
+}
+
+func (s *fatSliceReader) Close() error {
+	return tryClose(s.parent)
+}
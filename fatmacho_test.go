@@ -0,0 +1,100 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thinMachO returns a 64-bit Mach-O executable header without any load
+// commands.
+func thinMachO(cpu uint32) []byte {
+	buf := make([]byte, 32)
+	binary.LittleEndian.PutUint32(buf, 0xfeedfacf)
+	binary.LittleEndian.PutUint32(buf[4:], cpu)
+	binary.LittleEndian.PutUint32(buf[12:], 2) // MH_EXECUTE
+	return buf
+}
+
+// fatMachO returns a universal binary holding the images.
+func fatMachO(is64 bool, cpus []uint32, images ...[]byte) []byte {
+	var hdr, data bytes.Buffer
+	magic, entrySize := uint32(0xcafebabe), 20
+	if is64 {
+		magic, entrySize = 0xcafebabf, 32
+	}
+	_ = binary.Write(&hdr, binary.BigEndian, []uint32{magic, uint32(len(images))})
+	off := 8 + entrySize*len(images)
+	for i, img := range images {
+		_ = binary.Write(&hdr, binary.BigEndian, []uint32{cpus[i], 0})
+		if is64 {
+			_ = binary.Write(&hdr, binary.BigEndian, []uint64{uint64(off + data.Len()), uint64(len(img))})
+			_ = binary.Write(&hdr, binary.BigEndian, []uint32{0, 0})
+		} else {
+			_ = binary.Write(&hdr, binary.BigEndian, []uint32{uint32(off + data.Len()), uint32(len(img)), 0})
+		}
+		data.Write(img)
+	}
+	return append(hdr.Bytes(), data.Bytes()...)
+}
+
+func TestOpenFatMachO(t *testing.T) {
+	const cpuAMD64, cpuARM64 = 0x01000007, 0x0100000c
+
+	for _, is64 := range []bool{false, true} {
+		fat := fatMachO(is64, []uint32{cpuAMD64, cpuARM64}, thinMachO(cpuAMD64), thinMachO(cpuARM64))
+
+		r := &closeCountingReader{Reader: bytes.NewReader(fat)}
+		files, err := OpenFatMachO(r, Options{})
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		assert.Equal(t, ArchAMD64, files[0].FileInfo.Arch)
+		assert.Equal(t, ArchARM64, files[1].FileInfo.Arch)
+		for _, f := range files {
+			assert.NoError(t, f.Close())
+		}
+		assert.Zero(t, r.closed, "the reader is owned by the caller")
+
+		// Without Go metadata in any of the images, the first one is opened.
+		r = &closeCountingReader{Reader: bytes.NewReader(fat)}
+		f, err := OpenReader(r)
+		require.NoError(t, err)
+		assert.Equal(t, ArchAMD64, f.FileInfo.Arch)
+		assert.NoError(t, f.Close())
+		assert.Equal(t, 1, r.closed, "the reader is closed with the image")
+	}
+}
+
+func TestOpenFatMachOInvalid(t *testing.T) {
+	_, err := OpenFatMachO(bytes.NewReader(thinMachO(0x01000007)), Options{})
+	assert.ErrorIs(t, err, ErrUnsupportedFile, "thin Mach-O")
+
+	// A Java class file with version 52.
+	class := []byte{0xca, 0xfe, 0xba, 0xbe, 0x00, 0x00, 0x00, 0x34, 0x00, 0x00}
+	_, err = OpenReader(bytes.NewReader(class))
+	assert.ErrorIs(t, err, ErrUnsupportedFile, "Java class file")
+
+	elf := append([]byte{0x7f, 0x45, 0x4c, 0x46}, make([]byte, 28)...)
+	_, err = OpenFatMachO(bytes.NewReader(fatMachO(false, []uint32{0}, elf)), Options{})
+	assert.ErrorIs(t, err, ErrUnsupportedFile, "image not a Mach-O file")
+}
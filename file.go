@@ -19,15 +19,22 @@ package gore
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"debug/dwarf"
 	"debug/gosym"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path"
+	"reflect"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/blacktop/go-macho"
@@ -44,8 +51,32 @@ var (
 	machoMagic4    = []byte{0xcf, 0xfa, 0xed, 0xfe}
 )
 
+// Options holds the options used when opening a file.
+type Options struct {
+	// MaxSectionBytes is the maximum number of bytes read into memory from
+	// a single section. Reading a larger section fails with
+	// ErrSectionTooLarge. This guards against malformed files declaring huge
+	// sections when analyzing untrusted files. Zero means no limit.
+	MaxSectionBytes uint64
+	// KeepReaderOpen prevents Close from closing the reader passed to
+	// OpenReaderWithOptions. Only the parsed file is released and the
+	// caller stays in control of the reader's lifecycle. The option is
+	// ignored by OpenWithOptions since the file is opened by the library.
+	KeepReaderOpen bool
+	// Core is a core dump of a process running the binary. It is used by
+	// ReadGoroutines to inspect the state of the process. The caller stays
+	// in control of the reader's lifecycle.
+	Core io.ReaderAt
+}
+
 // Open opens a file and returns a handler to the file.
 func Open(filePath string) (*GoFile, error) {
+	return OpenWithOptions(filePath, Options{})
+}
+
+// OpenWithOptions opens a file using the given options and returns a handler
+// to the file.
+func OpenWithOptions(filePath string, opts Options) (*GoFile, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
@@ -55,11 +86,22 @@ func Open(filePath string) (*GoFile, error) {
 		return nil, err
 	}
 
-	return OpenReader(f)
+	opts.KeepReaderOpen = false
+	return OpenReaderWithOptions(f, opts)
 }
 
-// OpenReader opens a reader and returns a handler to the file.
+// OpenReader opens a reader and returns a handler to the file. The reader
+// doesn't have to be backed by a file on disk, for example it can be a
+// bytes.Reader or a reader fetching the data from object storage. If the
+// reader implements io.Closer, it's closed when the GoFile is closed. Use
+// OpenReaderWithOptions with KeepReaderOpen to keep it open.
 func OpenReader(f io.ReaderAt) (*GoFile, error) {
+	return OpenReaderWithOptions(f, Options{})
+}
+
+// OpenReaderWithOptions opens a reader using the given options and returns a
+// handler to the file.
+func OpenReaderWithOptions(f io.ReaderAt, opts Options) (*GoFile, error) {
 	buf := make([]byte, maxMagicBufLen)
 	n, err := f.ReadAt(buf, 0)
 	if err != nil {
@@ -68,25 +110,33 @@ func OpenReader(f io.ReaderAt) (*GoFile, error) {
 	if n < maxMagicBufLen {
 		return nil, ErrNotEnoughBytesRead
 	}
-	gofile := new(GoFile)
+	gofile := &GoFile{core: opts.Core, maxSectionBytes: opts.MaxSectionBytes}
 	if fileMagicMatch(buf, elfMagic) {
-		elf, err := openELF(f)
+		elf, err := openELF(f, opts)
 		if err != nil {
 			return nil, err
 		}
 		gofile.fh = elf
 	} else if fileMagicMatch(buf, peMagic) {
-		pe, err := openPE(f)
+		pe, err := openPE(f, opts)
 		if err != nil {
 			return nil, err
 		}
 		gofile.fh = pe
 	} else if fileMagicMatch(buf, machoMagic1) || fileMagicMatch(buf, machoMagic2) || fileMagicMatch(buf, machoMagic3) || fileMagicMatch(buf, machoMagic4) {
-		machO, err := openMachO(f)
+		machO, err := openMachO(f, opts)
 		if err != nil {
 			return nil, err
 		}
 		gofile.fh = machO
+	} else if fileMagicMatch(buf, fatMagic) || fileMagicMatch(buf, fatMagic64) {
+		return openFatMachO(f, opts)
+	} else if fileMagicMatch(buf, wasmMagic) {
+		wasm, err := openWasm(f, opts)
+		if err != nil {
+			return nil, err
+		}
+		gofile.fh = wasm
 	} else {
 		return nil, ErrUnsupportedFile
 	}
@@ -125,15 +175,29 @@ type GoFile struct {
 
 	fh fileHandler
 
+	// core is the core dump set by Options.Core.
+	core io.ReaderAt
+	// maxSectionBytes is the limit set by Options.MaxSectionBytes.
+	maxSectionBytes uint64
+
 	stdPkgs   []*Package
 	generated []*Package
 	pkgs      []*Package
 	vendors   []*Package
 	unknown   []*Package
 
-	pclntab *gosym.Table
+	// classifier is the classifier used for the packages.
+	classifier PackageClassifier
+
+	pclntab         *gosym.Table
+	gosymTableOnce  sync.Once
+	gosymTableError error
 
-	initPackagesOnce  sync.Once
+	// initPackagesMu guards the package enumeration. A sync.Once is not used
+	// since the enumeration can be canceled and should then be retried by the
+	// next call.
+	initPackagesMu    sync.Mutex
+	initPackagesDone  bool
 	initPackagesError error
 
 	runtimeText  uint64
@@ -142,26 +206,80 @@ type GoFile struct {
 	pclntabOnce  sync.Once
 	pclntabError error
 
+	pclnTable      *pclnTable
+	pclnTableOnce  sync.Once
+	pclnTableError error
+
 	moduledata moduledata
 
 	versionError error
 
 	initModuleDataOnce  sync.Once
 	initModuleDataError error
+
+	closeOnce  sync.Once
+	closeError error
+
+	// typeConcurrency is the number of goroutines used to parse the types.
+	typeConcurrency int
+
+	typeNameIndex      map[uint64]string
+	typeNameIndexOnce  sync.Once
+	typeNameIndexError error
+
+	itabIndex      map[itabKey]*Itab
+	itabIndexOnce  sync.Once
+	itabIndexError error
+
+	warnings []string
+}
+
+// Warnings returns the non-fatal issues encountered while analyzing the
+// file. For example, if the packages had to be classified with incomplete
+// information.
+func (f *GoFile) Warnings() []string {
+	return f.warnings
 }
 
 func (f *GoFile) initModuleData() error {
 	f.initModuleDataOnce.Do(func() {
 		err := f.ensureCompilerVersion()
+		if err == nil {
+			f.moduledata, err = extractModuledata(f)
+		}
 		if err != nil {
+			if gollvm, _ := f.IsGollvm(); gollvm {
+				err = fmt.Errorf("%w: %w", ErrGollvm, err)
+			}
 			f.initModuleDataError = err
-			return
 		}
-		f.moduledata, f.initModuleDataError = extractModuledata(f)
 	})
 	return f.initModuleDataError
 }
 
+// TextSections returns the sections holding the code of the binary as
+// recorded by the runtime's text section map. Most binaries only have one
+// text section, but very large binaries can have the code split across
+// multiple sections.
+func (f *GoFile) TextSections() ([]TextSection, error) {
+	err := f.initModuleData()
+	if err != nil {
+		return nil, err
+	}
+	return f.moduledata.textSections()
+}
+
+// DataBitmaps returns the location of the GC pointer bitmaps for the data and
+// bss sections as recorded in the moduledata. The bitmaps can be used to
+// determine which global variables hold pointers.
+func (f *GoFile) DataBitmaps() (*DataBitmaps, error) {
+	err := f.initModuleData()
+	if err != nil {
+		return nil, err
+	}
+	return f.moduledata.dataBitmaps()
+}
+
 // Moduledata extracts the file's moduledata.
 func (f *GoFile) Moduledata() (Moduledata, error) {
 	err := f.initModuleData()
@@ -172,16 +290,28 @@ func (f *GoFile) Moduledata() (Moduledata, error) {
 }
 
 func (f *GoFile) initPackages() error {
-	f.initPackagesOnce.Do(func() {
-		tab, err := f.PCLNTab()
-		if err != nil {
-			f.initPackagesError = err
-			return
-		}
-		f.pclntab = tab
-		f.initPackagesError = f.enumPackages()
-	})
-	return f.initPackagesError
+	return f.initPackagesContext(context.Background())
+}
+
+// initPackagesContext enumerates the packages unless it has already been
+// done. If the context is canceled before the enumeration is complete, the
+// context's error is returned and the next call starts over.
+func (f *GoFile) initPackagesContext(ctx context.Context) error {
+	f.initPackagesMu.Lock()
+	defer f.initPackagesMu.Unlock()
+	if f.initPackagesDone {
+		return f.initPackagesError
+	}
+	_, err := f.PCLNTab()
+	if err == nil {
+		err = f.enumPackages(ctx)
+	}
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return err
+	}
+	f.initPackagesDone = true
+	f.initPackagesError = err
+	return err
 }
 
 // GetReader returns the reader passed to the file handler.
@@ -195,7 +325,7 @@ func (f *GoFile) GetReader() io.ReaderAt {
 //   - *pe.File
 //   - *github.com/blacktop/go-macho.File
 //
-// all from the debug package.
+// all from the debug package. For WebAssembly modules, nil is returned.
 func (f *GoFile) GetParsedFile() any {
 	return f.fh.getParsedFile()
 }
@@ -248,6 +378,18 @@ func (f *GoFile) GetGoRoot() (string, error) {
 	return findGoRootPath(f)
 }
 
+// InferGOPATH returns the GOPATH used to compile the binary. This is only
+// possible for binaries built in GOPATH mode, where the source code for the
+// non-standard library packages is located under "$GOPATH/src". If no GOPATH
+// can be determined, ErrNoGoPathFound is returned.
+func (f *GoFile) InferGOPATH() (string, error) {
+	err := f.initPackages()
+	if err != nil {
+		return "", err
+	}
+	return findGoPath(f)
+}
+
 // SetGoVersion sets the assumed compiler version that was used. This
 // can be used to force a version if gore is not able to determine the
 // compiler version used. The version string must match one of the strings
@@ -264,6 +406,33 @@ func (f *GoFile) SetGoVersion(version string) error {
 	return nil
 }
 
+// SetConcurrency sets the number of goroutines used to parse the types. On
+// large binaries with tens of thousands of types, parsing the types
+// concurrently can reduce the time spent by GetTypes. By default, or if n is
+// less than 2, the types are parsed by a single goroutine. The types of
+// binaries compiled with Go versions before 1.7 are always parsed by a single
+// goroutine. It should be called before the types are parsed.
+func (f *GoFile) SetConcurrency(n int) {
+	f.typeConcurrency = n
+}
+
+// SetPackageClassifier sets the classifier used to classify the packages in
+// the binary. By default, a ModPackageClassifier is used if the binary has
+// module information and a PathPackageClassifier otherwise. A custom
+// classifier can for example wrap the default one to override the class of
+// some packages. The classifier must be set before the packages are
+// enumerated by the first call that needs them, for example GetPackages.
+// Otherwise, ErrPackagesInitialized is returned.
+func (f *GoFile) SetPackageClassifier(c PackageClassifier) error {
+	f.initPackagesMu.Lock()
+	defer f.initPackagesMu.Unlock()
+	if f.initPackagesDone {
+		return ErrPackagesInitialized
+	}
+	f.classifier = c
+	return nil
+}
+
 // GetPackages returns the go packages that have been classified as part of the main
 // project.
 func (f *GoFile) GetPackages() ([]*Package, error) {
@@ -271,6 +440,13 @@ func (f *GoFile) GetPackages() ([]*Package, error) {
 	return f.pkgs, err
 }
 
+// GetPackagesContext is like GetPackages but the package enumeration stops
+// early with the context's error if the context is canceled.
+func (f *GoFile) GetPackagesContext(ctx context.Context) ([]*Package, error) {
+	err := f.initPackagesContext(ctx)
+	return f.pkgs, err
+}
+
 // GetVendors returns the third party packages used by the binary.
 func (f *GoFile) GetVendors() ([]*Package, error) {
 	err := f.initPackages()
@@ -296,75 +472,170 @@ func (f *GoFile) GetUnknown() ([]*Package, error) {
 	return f.unknown, err
 }
 
-func (f *GoFile) enumPackages() error {
-	tab := f.pclntab
-	packages := make(map[string]*Package)
-	allPackages := sort.StringSlice{}
+// IsStatic returns true if the binary is statically linked. ELF files are
+// dynamically linked if they have a PT_INTERP program header and Mach-O files
+// if they have a LC_LOAD_DYLINKER load command. The dynamic linker is needed
+// if the binary uses cgo or the system's C library. PE files always import
+// the system DLLs so false is returned. For other file types,
+// ErrUnsupportedFile is returned.
+func (f *GoFile) IsStatic() (bool, error) {
+	switch fh := f.fh.(type) {
+	case *elfFile:
+		return fh.isStatic(), nil
+	case *machoFile:
+		return fh.isStatic(), nil
+	case *peFile:
+		return false, nil
+	}
+	return false, ErrUnsupportedFile
+}
 
-	for _, n := range tab.Funcs {
-		p, ok := packages[n.PackageName()]
-		if !ok {
-			p = &Package{
-				Filepath:  "", // to be filled later by dir(PCToLine())
-				Functions: make([]*Function, 0),
-				Methods:   make([]*Method, 0),
+// EntryPoint returns the virtual address of the binary's entry point. For ELF
+// files, it's the entry in the file header, for PE files the
+// AddressOfEntryPoint in the optional header and for Mach-O files the entry
+// from the LC_MAIN or LC_UNIXTHREAD load command. WebAssembly modules don't
+// have an entry point address, so ErrUnsupportedFile is returned.
+func (f *GoFile) EntryPoint() (uint64, error) {
+	return f.fh.getEntryPoint()
+}
+
+// ImportedLibraries returns the names of the shared libraries the binary
+// depends on. For ELF files, these are the DT_NEEDED entries, for PE files the
+// imported DLLs and for Mach-O files the loaded dylibs. For WebAssembly
+// modules, the modules the host functions are imported from are returned.
+// Pure Go binaries usually don't depend on any library on Linux while cgo
+// binaries depend on the C library and the libraries they were linked with.
+func (f *GoFile) ImportedLibraries() ([]string, error) {
+	return f.fh.getImportedLibraries()
+}
+
+// ClassifyPackage classifies a package by its name and the directory of its
+// source files with the same classifier used for the binary's packages. It
+// can be used to classify packages obtained from another source.
+func (f *GoFile) ClassifyPackage(name, filepath string) (PackageClass, error) {
+	err := f.initPackages()
+	if err != nil {
+		return ClassUnknown, err
+	}
+	return f.classifier.Classify(&Package{Name: name, Filepath: filepath}), nil
+}
+
+// functionsByEntry returns all functions and methods indexed by their entry
+// address. The packages must have been initialized before calling this method.
+func (f *GoFile) functionsByEntry() map[uint64]*Function {
+	fcns := make(map[uint64]*Function)
+	for _, pkgs := range [][]*Package{f.stdPkgs, f.generated, f.pkgs, f.vendors, f.unknown} {
+		for _, p := range pkgs {
+			for _, fn := range p.Functions {
+				fcns[fn.Offset] = fn
+			}
+			for _, m := range p.Methods {
+				fcns[m.Offset] = m.Function
 			}
-			packages[n.PackageName()] = p
-			allPackages = append(allPackages, n.PackageName())
 		}
+	}
+	return fcns
+}
 
+func (f *GoFile) enumPackages(ctx context.Context) error {
+	tab := f.pclntab
+
+	// The symbol name parsing in gosym is not free, so the package name is
+	// resolved once per function. A first pass counts the functions and methods
+	// of each package so the slices can be allocated with the right size.
+	type pkgCount struct {
+		functions, methods int
+	}
+	pkgNames := make([]string, len(tab.Funcs))
+	counts := make(map[string]*pkgCount)
+	numMethods := 0
+	for i := range tab.Funcs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := &tab.Funcs[i]
+		pkgNames[i] = n.PackageName()
+		c, ok := counts[pkgNames[i]]
+		if !ok {
+			c = &pkgCount{}
+			counts[pkgNames[i]] = c
+		}
 		if n.ReceiverName() != "" {
-			m := &Method{
-				Function: &Function{
-					Name:        n.BaseName(),
-					Offset:      n.Entry,
-					End:         n.End,
-					PackageName: n.PackageName(),
-				},
-				Receiver: n.ReceiverName(),
-			}
+			c.methods++
+			numMethods++
+		} else {
+			c.functions++
+		}
+	}
 
-			p.Methods = append(p.Methods, m)
+	packages := make(map[string]*Package, len(counts))
+	for name, c := range counts {
+		packages[name] = &Package{
+			Filepath:  "", // to be filled later by dir(PCToLine())
+			Functions: make([]*Function, 0, c.functions),
+			Methods:   make([]*Method, 0, c.methods),
+		}
+	}
+
+	// Packages that have their file path resolved. This also covers the
+	// unnamed package, where the path is resolved to an empty string.
+	resolved := make(map[*Package]bool, len(packages))
+
+	fcns := make([]Function, len(tab.Funcs))
+	methods := make([]Method, 0, numMethods)
+	for i := range tab.Funcs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := &tab.Funcs[i]
+		pkg := pkgNames[i]
+		p := packages[pkg]
+
+		fn := &fcns[i]
+		*fn = Function{
+			Name:        n.BaseName(),
+			Offset:      n.Entry,
+			End:         n.End,
+			PackageName: pkg,
+		}
+		if recv := n.ReceiverName(); recv != "" {
+			fn.lines = tab
+			methods = append(methods, Method{Function: fn, Receiver: recv})
+			p.Methods = append(p.Methods, &methods[len(methods)-1])
 		} else {
-			f := &Function{
-				Name:        n.BaseName(),
-				Offset:      n.Entry,
-				End:         n.End,
-				PackageName: n.PackageName(),
-			}
-			p.Functions = append(p.Functions, f)
+			p.Functions = append(p.Functions, fn)
 		}
 
-		if p.Filepath == "" {
+		if !resolved[p] {
 			fp, _, _ := tab.PCToLine(n.Entry)
 			switch fp {
 			case "<autogenerated>", "":
-				pkg := n.PackageName()
 				if pkg == "" {
 					p.Filepath = fp
+					resolved[p] = true
 				}
 			default:
 				p.Filepath = path.Dir(fp)
+				resolved[p] = true
 			}
 		}
 	}
 
-	allPackages.Sort()
-
-	var classifier PackageClassifier
-
-	if f.BuildInfo != nil && f.BuildInfo.ModInfo != nil {
-		classifier = NewModPackageClassifier(f.BuildInfo.ModInfo)
-	} else {
-		mainPkg, ok := packages["main"]
-		if !ok {
-			return fmt.Errorf("no main package found")
-		}
-
-		classifier = NewPathPackageClassifier(mainPkg.Filepath)
+	classifier, err := f.packageClassifier(packages)
+	if err != nil {
+		return err
 	}
+	f.classifier = classifier
 
-	for n, p := range packages {
+	// The packages are added in the order of their names so the lists are
+	// the same every time the file is parsed.
+	names := make([]string, 0, len(packages))
+	for n := range packages {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		p := packages[n]
 		p.Name = n
 		class := classifier.Classify(p)
 		switch class {
@@ -383,9 +654,43 @@ func (f *GoFile) enumPackages() error {
 	return nil
 }
 
-// Close releases the file handler.
+// packageClassifier returns the classifier set with SetPackageClassifier. If
+// none has been set, a classifier based on the module information is used if
+// available. Otherwise, the packages are classified by their path relative to
+// the main package.
+func (f *GoFile) packageClassifier(packages map[string]*Package) (PackageClassifier, error) {
+	if f.classifier != nil {
+		return f.classifier, nil
+	}
+	if f.BuildInfo != nil && f.BuildInfo.ModInfo != nil {
+		return NewModPackageClassifier(f.BuildInfo.ModInfo), nil
+	}
+
+	mainPkg, ok := packages["main"]
+	if !ok {
+		// Binaries like c-archives and test binaries may not have a main
+		// package. Instead of failing, make a best-effort guess.
+		var name string
+		name, mainPkg = guessMainPackage(packages)
+		if mainPkg == nil {
+			return nil, fmt.Errorf("no main package found")
+		}
+		f.warnings = append(f.warnings, fmt.Sprintf("no main package found, using %q as the main package for the classification", name))
+	}
+	return NewPathPackageClassifier(mainPkg.Filepath), nil
+}
+
+// Close releases the file handler. It is safe to call Close multiple times,
+// only the first call releases the resources. Subsequent calls return the
+// same error as the first call.
 func (f *GoFile) Close() error {
-	return f.fh.Close()
+	f.closeOnce.Do(func() {
+		if f.fh == nil {
+			return
+		}
+		f.closeError = f.fh.Close()
+	})
+	return f.closeError
 }
 
 // GetSymbol returns the symbol with the given name.
@@ -393,6 +698,33 @@ func (f *GoFile) GetSymbol(name string) (Symbol, error) {
 	return f.fh.getSymbol(name)
 }
 
+// Symbols returns all the symbols in the binary's symbol table, sorted by
+// their address. Symbols with the same address are sorted by name. If more
+// than one symbol has the same name, only the one returned by GetSymbol is
+// included. If the binary doesn't have a symbol table, an empty slice is
+// returned.
+func (f *GoFile) Symbols() ([]Symbol, error) {
+	names, err := f.fh.getSymbolNames()
+	if err != nil {
+		return nil, err
+	}
+	syms := make([]Symbol, 0, len(names))
+	for _, name := range names {
+		sym, err := f.fh.getSymbol(name)
+		if err != nil {
+			return nil, err
+		}
+		syms = append(syms, sym)
+	}
+	slices.SortFunc(syms, func(a, b Symbol) int {
+		if c := cmp.Compare(a.Value, b.Value); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return syms, nil
+}
+
 func (f *GoFile) getPCLNTABDataBySymbol() (uint64, []byte, error) {
 	sym, err := f.fh.getSymbol("runtime.pclntab")
 	if err != nil {
@@ -439,13 +771,7 @@ func (f *GoFile) initPclntab() error {
 			return
 		}
 
-		// Otherwise, we need to search it
-		_, moddataSection, err := f.fh.getSectionData(f.fh.moduledataSection())
-		if err != nil {
-			f.pclntabError = fmt.Errorf("failed to get the section %s where the moduledata structure is stored: %w", f.fh.moduledataSection(), err)
-			return
-		}
-
+		// Otherwise, we need to search it.
 		// At this point, we don't know what compiler version was used so we can't parse the moduledata structure.
 		// We do know the field in different structure versions so we can check these offsets and see if the fall
 		// within the text section.
@@ -456,7 +782,19 @@ func (f *GoFile) initPclntab() error {
 		}
 
 		// Since the moduledata starts with the address to the pclntab, we can use this to find the moduledata structure.
-		runtimeText, err := f.findRuntimeText(textStart, textStart+uint64(len(textData)), f.pclntabAddr, moddataSection)
+		// The sections where the moduledata structure can be stored are searched in order.
+		var runtimeText uint64
+		err = fmt.Errorf("failed to get the sections %v where the moduledata structure is stored: %w", f.fh.moduledataSections(), ErrSectionDoesNotExist)
+		for _, section := range f.fh.moduledataSections() {
+			_, moddataSection, serr := f.fh.getSectionData(section)
+			if serr != nil {
+				continue
+			}
+			runtimeText, err = f.findRuntimeText(textStart, textStart+uint64(len(textData)), f.pclntabAddr, moddataSection)
+			if err == nil {
+				break
+			}
+		}
 		if err != nil {
 			if f.FileInfo.OS == "macOS" && f.FileInfo.Arch == ArchARM64 {
 				t, err := f.findRuntimeTextMachoChainedFixups(f.pclntabAddr)
@@ -476,13 +814,163 @@ func (f *GoFile) initPclntab() error {
 	return f.pclntabError
 }
 
-// PCLNTab returns the PCLN table.
+// checkSectionSize returns ErrSectionTooLarge if the section is larger than
+// the limit. A limit of zero means no limit.
+func checkSectionSize(name string, size, limit uint64) error {
+	if limit != 0 && size > limit {
+		return fmt.Errorf("%w: %s is %d bytes, the limit is %d bytes", ErrSectionTooLarge, name, size, limit)
+	}
+	return nil
+}
+
+// checkDwarfSectionSize returns ErrSectionTooLarge if the DWARF section is
+// larger than the limit. The data of the section is read from r at off.
+// Sections compressed with the "ZLIB" header used by the .zdebug sections
+// are checked against their uncompressed size from the header. A limit of
+// zero means no limit.
+func checkDwarfSectionSize(name string, size uint64, r io.ReaderAt, off int64, limit uint64) error {
+	if err := checkSectionSize(name, size, limit); err != nil || limit == 0 {
+		return err
+	}
+	hdr := make([]byte, 12)
+	if n, _ := r.ReadAt(hdr, off); n < len(hdr) || size < uint64(len(hdr)) || string(hdr[:4]) != "ZLIB" {
+		return nil
+	}
+	return checkSectionSize(name, binary.BigEndian.Uint64(hdr[4:]), limit)
+}
+
+// RawPCLNTab returns the virtual address and the raw bytes of the PCLN table.
+// This is the same data used by PCLNTab to construct the table. It can be
+// used to feed the table to another parser or to dump it. The data should
+// not be modified.
+func (f *GoFile) RawPCLNTab() (uint64, []byte, error) {
+	err := f.initPclntab()
+	if err != nil {
+		return 0, nil, err
+	}
+	return f.pclntabAddr, f.pclntabBytes, nil
+}
+
+// PCLNTab returns the PCLN table. The table is only constructed on the first
+// call and the same table is returned by the following calls, so it should not
+// be modified by the caller.
 func (f *GoFile) PCLNTab() (*gosym.Table, error) {
+	f.gosymTableOnce.Do(func() {
+		err := f.initPclntab()
+		if err != nil {
+			f.gosymTableError = err
+			return
+		}
+		data := f.pclntabBytes
+		// debug/gosym assumes the code is in one text section. For binaries
+		// with more than one, the table is rewritten for the text section map.
+		if sects, ok := f.multipleTextSections(); ok {
+			tab, err := newPclnTable(f.pclntabBytes, f.FileInfo.ByteOrder, f.runtimeText)
+			if err == nil {
+				tab.textSections = sects
+				data, err = tab.contiguousText()
+			}
+			if err != nil {
+				f.gosymTableError = fmt.Errorf("failed to apply the text section map: %w", err)
+				return
+			}
+		}
+		f.pclntab, f.gosymTableError = gosym.NewTable(make([]byte, 0), gosym.NewLineTable(data, f.runtimeText))
+	})
+	return f.pclntab, f.gosymTableError
+}
+
+// GetGoSymtab returns the data of the legacy Go symbol table. The data can be
+// passed to gosym.NewTable when constructing a symbol table. Since Go 1.3
+// the table is empty but the section is still present in the binary.
+// ErrSectionDoesNotExist is returned if the table can't be found.
+func (f *GoFile) GetGoSymtab() ([]byte, error) {
+	if start, err := f.fh.getSymbol("runtime.symtab"); err == nil {
+		if end, err := f.fh.getSymbol("runtime.esymtab"); err == nil && end.Value >= start.Value {
+			if end.Value == start.Value {
+				return []byte{}, nil
+			}
+			return f.Bytes(start.Value, end.Value-start.Value)
+		}
+	}
+	// PIE binaries built by the internal linker store the table in the
+	// relro segment.
+	for _, name := range []string{".gosymtab", ".data.rel.ro.gosymtab", "__gosymtab"} {
+		_, data, err := f.fh.getSectionData(name)
+		if err == nil {
+			return data, nil
+		}
+	}
+	return nil, ErrSectionDoesNotExist
+}
+
+// PclntabVersion returns the Go version family of the pclntab's layout, for
+// example "1.18". The version is derived solely from the magic in the
+// pclntab's header, so it does not depend on the version string embedded in
+// the binary. It can be used to cross-check the compiler version or when the
+// version string has been stripped.
+func (f *GoFile) PclntabVersion() (string, error) {
 	err := f.initPclntab()
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if len(f.pclntabBytes) < 4 {
+		return "", ErrNoPCLNTab
+	}
+	ver, ok := pclntabVersion(f.FileInfo.ByteOrder.Uint32(f.pclntabBytes))
+	if !ok {
+		return "", ErrUnsupportedPCLNTabVersion
 	}
-	return gosym.NewTable(make([]byte, 0), gosym.NewLineTable(f.pclntabBytes, f.runtimeText))
+	return ver, nil
+}
+
+func (f *GoFile) initPclnTable() error {
+	f.pclnTableOnce.Do(func() {
+		err := f.initPclntab()
+		if err != nil {
+			f.pclnTableError = err
+			return
+		}
+		f.pclnTable, f.pclnTableError = newPclnTable(f.pclntabBytes, f.FileInfo.ByteOrder, f.runtimeText)
+		if f.pclnTableError != nil {
+			return
+		}
+		// Binaries with more than one text section need the text section map
+		// to resolve the function addresses.
+		if sects, ok := f.multipleTextSections(); ok {
+			f.pclnTable.textSections = sects
+		}
+	})
+	return f.pclnTableError
+}
+
+// multipleTextSections returns the text section map if the code is split
+// across more than one text section. If the moduledata can't be extracted,
+// the text is assumed to be contiguous. The moduledata can't be parsed
+// before the compiler version is known, so the map isn't used while the
+// version is being extracted.
+func (f *GoFile) multipleTextSections() ([]TextSection, bool) {
+	if f.FileInfo == nil || f.FileInfo.goversion == nil || f.initModuleData() != nil {
+		return nil, false
+	}
+	sects, err := f.moduledata.textSections()
+	if err != nil || len(sects) < 2 {
+		return nil, false
+	}
+	return sects, true
+}
+
+// goFuncValue returns the address of the "go:func.*" symbol. The funcdata for
+// Go 1.18 and later is stored as offsets from this address.
+func (f *GoFile) goFuncValue() uint64 {
+	sym, err := f.fh.getSymbol("go:func.*")
+	if err == nil {
+		return sym.Value
+	}
+	if err = f.initModuleData(); err != nil {
+		return 0
+	}
+	return f.moduledata.GoFuncValue()
 }
 
 func (f *GoFile) findRuntimeTextMachoChainedFixups(pclntabAddr uint64) (uint64, error) {
@@ -557,24 +1045,408 @@ func (f *GoFile) findRuntimeText(textStart, textEnd, pclntabAddr uint64, modSect
 	}
 }
 
-// GetTypes returns a map of all types found in the binary file.
+// GetTypes returns a map of all types found in the binary file. The
+// interfaces implemented by the concrete types are resolved from the itabs
+// in the binary, see GoType.Implements.
 func (f *GoFile) GetTypes() ([]*GoType, error) {
+	return f.GetTypesContext(context.Background())
+}
+
+// GetTypesContext is like GetTypes but the type parsing and the package
+// enumeration stop early with the context's error if the context is
+// canceled.
+func (f *GoFile) GetTypesContext(ctx context.Context) ([]*GoType, error) {
 	err := f.initModuleData()
 	if err != nil {
 		return nil, err
 	}
 	md := f.moduledata
 
-	t, err := getTypes(f.FileInfo, f.fh, md)
+	t, err := getTypes(ctx, f.FileInfo, f.fh, md, f.typeConcurrency)
 	if err != nil {
 		return nil, err
 	}
-	if err = f.initPackages(); err != nil {
+	// The itabs are only available for Go 1.7 and later.
+	if f.initItabIndex() == nil {
+		f.resolveImplements(t)
+	}
+	if err = f.initPackagesContext(ctx); err != nil {
 		return nil, err
 	}
 	return sortTypes(t), nil
 }
 
+// GetTypeRawName returns the undecoded bytes of the type's name. For Go 1.7
+// and later, this is the encoded name structure starting with the flags
+// byte, followed by the name, the struct tag and the package path offset
+// if present. For older versions, the bytes of the name string are returned.
+// This can be used to debug types with a wrongly decoded name.
+func (f *GoFile) GetTypeRawName(t *GoType) ([]byte, error) {
+	err := f.initModuleData()
+	if err != nil {
+		return nil, err
+	}
+
+	if GoVersionCompare(f.FileInfo.goversion.Name, "go1.7beta1") < 0 {
+		// The type has a pointer to a string header.
+		ptr, err := f.readPointer(t.Addr + uint64(typeOffset(f.FileInfo, _typeFieldStr)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the name pointer: %w", err)
+		}
+		data, err := f.readPointer(ptr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the name data pointer: %w", err)
+		}
+		n, err := f.readPointer(ptr + uint64(f.FileInfo.WordSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the name length: %w", err)
+		}
+		return f.Bytes(data, n)
+	}
+
+	types, err := f.moduledata.Types().Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get types data section: %w", err)
+	}
+	base := f.moduledata.Types().Address
+	if t.Addr < base || t.Addr >= base+uint64(len(types)) {
+		return nil, fmt.Errorf("type address 0x%x is outside of the types section", t.Addr)
+	}
+	parser := newTypeParser(types, base, f.FileInfo)
+	err = parser.seekFromStart(t.Addr - base)
+	if err != nil {
+		return nil, err
+	}
+	rtype, _, err := parser.parseRtype(parser)
+	if err != nil {
+		return nil, err
+	}
+	return parser.rawName(uint64(rtype.Str))
+}
+
+// readPointer reads a pointer sized value at the address.
+func (f *GoFile) readPointer(addr uint64) (uint64, error) {
+	b, err := f.Bytes(addr, uint64(f.FileInfo.WordSize))
+	if err != nil {
+		return 0, err
+	}
+	if f.FileInfo.WordSize == intSize32 {
+		return uint64(f.FileInfo.ByteOrder.Uint32(b)), nil
+	}
+	return f.FileInfo.ByteOrder.Uint64(b), nil
+}
+
+// ChannelTypes returns all the channel types found in the binary.
+func (f *GoFile) ChannelTypes() ([]*GoType, error) {
+	return f.typesOfKind(reflect.Chan)
+}
+
+// MapTypes returns all the map types found in the binary.
+func (f *GoFile) MapTypes() ([]*GoType, error) {
+	return f.typesOfKind(reflect.Map)
+}
+
+// SliceTypes returns all the slice types found in the binary.
+func (f *GoFile) SliceTypes() ([]*GoType, error) {
+	return f.typesOfKind(reflect.Slice)
+}
+
+func (f *GoFile) typesOfKind(kind reflect.Kind) ([]*GoType, error) {
+	types, err := f.GetTypes()
+	if err != nil {
+		return nil, err
+	}
+	return filterTypesByKind(types, kind), nil
+}
+
+func filterTypesByKind(types []*GoType, kind reflect.Kind) []*GoType {
+	var filtered []*GoType
+	for _, typ := range types {
+		if typ.Kind == kind {
+			filtered = append(filtered, typ)
+		}
+	}
+	return filtered
+}
+
+// TypeNameIndex returns a map from the address of every type in the binary to
+// the type's name. The index is only built on the first call, making it cheap
+// to resolve type addresses found in itabs, interface values or reflection
+// calls.
+func (f *GoFile) TypeNameIndex() (map[uint64]string, error) {
+	f.typeNameIndexOnce.Do(func() {
+		err := f.initModuleData()
+		if err != nil {
+			f.typeNameIndexError = err
+			return
+		}
+		types, err := getTypes(context.Background(), f.FileInfo, f.fh, f.moduledata, f.typeConcurrency)
+		if err != nil {
+			f.typeNameIndexError = err
+			return
+		}
+		f.typeNameIndex = make(map[uint64]string, len(types))
+		for addr, typ := range types {
+			f.typeNameIndex[addr] = typ.String()
+		}
+	})
+	if f.typeNameIndexError != nil {
+		return nil, f.typeNameIndexError
+	}
+	return maps.Clone(f.typeNameIndex), nil
+}
+
+// reflectMethodFuncs are the functions that allow methods to be looked up
+// dynamically via reflection. If any of them is reachable, the linker keeps
+// all the exported methods of the reachable types.
+var reflectMethodFuncs = []string{
+	"reflect.Value.Method",
+	"reflect.Value.MethodByName",
+	"reflect.(*rtype).Method",
+	"reflect.(*rtype).MethodByName",
+}
+
+// HasFullTypeMetadata returns true if the binary retains the full method
+// metadata for its types. The linker removes methods that can't be reached
+// unless the program can look up methods dynamically via reflection, for
+// example with reflect.Value.MethodByName. If false is returned, the methods
+// returned by GetTypes only include the methods reachable by the program and
+// the list of methods for a type may be partial.
+func (f *GoFile) HasFullTypeMetadata() (bool, error) {
+	err := f.initPackages()
+	if err != nil {
+		return false, err
+	}
+	for _, name := range reflectMethodFuncs {
+		if f.pclntab.LookupFunc(name) != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsBoringCrypto returns true if the binary was built with the BoringCrypto
+// variant of the crypto packages. The check first looks for the
+// boringcrypto experiment in the build settings. If it's not found, the
+// functions are checked for the marker function added by the variant and for
+// the cgo calls to the BoringSSL library.
+func (f *GoFile) IsBoringCrypto() (bool, error) {
+	if f.BuildInfo != nil && f.BuildInfo.ModInfo != nil {
+		for _, s := range f.BuildInfo.ModInfo.Settings {
+			if s.Key == "GOEXPERIMENT" && slices.Contains(strings.Split(s.Value, ","), "boringcrypto") {
+				return true, nil
+			}
+		}
+	}
+
+	err := f.initPackages()
+	if err != nil {
+		return false, err
+	}
+	if f.pclntab.LookupFunc("crypto/internal/boring/sig.BoringCrypto") != nil {
+		return true, nil
+	}
+	for _, fn := range f.pclntab.Funcs {
+		if strings.Contains(fn.Name, "_Cfunc__goboringcrypto_") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+const (
+	// ResolverModeGo is the pure Go DNS resolver.
+	ResolverModeGo = "go"
+	// ResolverModeCgo is the DNS resolver that uses the C library.
+	ResolverModeCgo = "cgo"
+)
+
+// GetResolverMode returns the DNS resolver the net package in the binary
+// uses. If the resolver has been forced with the netgo or netcgo build tags,
+// the mode is taken from the build settings. Otherwise, the mode is
+// ResolverModeCgo if the cgo resolver functions are present and
+// ResolverModeGo if they are not. Note that even when the cgo resolver is
+// included, the runtime may decide to use the Go resolver based on the
+// system's configuration and the GODEBUG setting. If the binary doesn't
+// include the net package, an empty string is returned.
+func (f *GoFile) GetResolverMode() (string, error) {
+	if f.BuildInfo != nil && f.BuildInfo.ModInfo != nil {
+		for _, s := range f.BuildInfo.ModInfo.Settings {
+			if s.Key != "-tags" {
+				continue
+			}
+			tags := strings.Split(s.Value, ",")
+			if slices.Contains(tags, "netgo") {
+				return ResolverModeGo, nil
+			}
+			if slices.Contains(tags, "netcgo") {
+				return ResolverModeCgo, nil
+			}
+		}
+	}
+
+	err := f.initPackages()
+	if err != nil {
+		return "", err
+	}
+	mode := ""
+	for _, fn := range f.pclntab.Funcs {
+		// The cgo resolver calls into the C library via functions named
+		// "_C_", "_Cfunc_" or "_C2func_" depending on the Go version.
+		if strings.HasPrefix(fn.Name, "net._C") {
+			return ResolverModeCgo, nil
+		}
+		if strings.HasPrefix(fn.Name, "net.") {
+			mode = ResolverModeGo
+		}
+	}
+	return mode, nil
+}
+
+// UsesRegABI returns true if the Go functions in the binary use the register
+// based calling convention, where the arguments and results are passed in
+// registers instead of on the stack. It was enabled by default for amd64 in
+// Go 1.17 and has since been added to more architectures. For some releases
+// and architectures, it can be turned off with the regabi or regabiargs
+// GOEXPERIMENT flags. The flags are read from the build settings, which are
+// only available for Go 1.18 and later. Functions written in assembly always
+// use the stack based calling convention.
+func (f *GoFile) UsesRegABI() (bool, error) {
+	err := f.ensureCompilerVersion()
+	if err != nil {
+		return false, err
+	}
+	supported, alwaysOn := regABISupport(f.FileInfo.goversion.Name, f.FileInfo.Arch)
+	if supported && f.FileInfo.Arch == ArchAMD64 && GoVersionCompare(f.FileInfo.goversion.Name, "go1.18beta1") < 0 {
+		// Go 1.17 only enabled it for some of the operating systems.
+		switch f.fh.(type) {
+		case *peFile, *machoFile:
+		default:
+			supported = f.isLinuxELF()
+		}
+	}
+	if !supported {
+		return false, nil
+	}
+	if alwaysOn {
+		return true, nil
+	}
+
+	enabled := true
+	if f.BuildInfo != nil && f.BuildInfo.ModInfo != nil {
+		for _, s := range f.BuildInfo.ModInfo.Settings {
+			if s.Key != "GOEXPERIMENT" {
+				continue
+			}
+			for _, exp := range strings.Split(s.Value, ",") {
+				switch exp {
+				case "regabi", "regabiargs":
+					enabled = true
+				case "noregabi", "noregabiargs", "none":
+					enabled = false
+				}
+			}
+		}
+	}
+	return enabled, nil
+}
+
+// regABISupport returns whether the register based calling convention is
+// enabled by default for the Go version and architecture, and whether it can
+// be turned off. This mirrors the defaults in the toolchain's
+// internal/buildcfg package.
+func regABISupport(version, arch string) (supported, alwaysOn bool) {
+	atLeast := func(v string) bool {
+		return GoVersionCompare(version, v) >= 0
+	}
+	switch arch {
+	case ArchAMD64:
+		return atLeast("go1.17beta1"), atLeast("go1.19beta1")
+	case ArchARM64, ArchPPC64, ArchPPC64LE:
+		return atLeast("go1.18beta1"), atLeast("go1.19beta1")
+	case ArchRISCV64:
+		return atLeast("go1.19beta1"), atLeast("go1.20rc1")
+	case ArchLoong64:
+		return atLeast("go1.22rc1"), atLeast("go1.23rc1")
+	case ArchS390X:
+		return atLeast("go1.26rc1"), false
+	}
+	return false, false
+}
+
+// CodeReader returns a reader over the code section together with the virtual
+// address where the section starts. Offset 0 of the reader corresponds to the
+// start address. Unlike reading the section via Bytes, the section is not
+// loaded into memory, which makes it better suited for disassembling large
+// binaries.
+//
+// If the binary doesn't have a separate read-only data section and the data
+// has been merged into the code section, the reader is limited to the text
+// range recorded in the moduledata.
+func (f *GoFile) CodeReader() (io.ReaderAt, uint64, error) {
+	addr, r, size, err := f.fh.getCodeSectionReader()
+	if err != nil {
+		return nil, 0, err
+	}
+	if etext, ok := f.mergedTextEnd(addr, uint64(size)); ok {
+		size = int64(etext - addr)
+	}
+	return io.NewSectionReader(r, 0, size), addr, nil
+}
+
+// getRData returns the read-only data of the binary. Some minimal or
+// custom-linked binaries don't have a separate read-only data section and
+// instead store the data in the code section. For these, the part of the
+// code section after the Go code is returned. If the end of the code can't
+// be determined, the whole code section is returned.
+func (f *GoFile) getRData() ([]byte, error) {
+	data, err := f.fh.getRData()
+	if !errors.Is(err, ErrSectionDoesNotExist) {
+		return data, err
+	}
+	addr, code, err := f.fh.getCodeSection()
+	if err != nil {
+		return nil, err
+	}
+	etext, ok := f.mergedTextEnd(addr, uint64(len(code)))
+	if !ok {
+		return code, nil
+	}
+	_, rodata := splitMergedSection(addr, code, etext)
+	return rodata, nil
+}
+
+// mergedTextEnd returns the address where the Go code ends if the code
+// section also holds the read-only data. The end is taken from the text
+// range in the moduledata.
+func (f *GoFile) mergedTextEnd(addr, size uint64) (uint64, bool) {
+	// The moduledata can't be parsed before the compiler version is known.
+	// Bail out instead of recursing while the version is being extracted.
+	if f.FileInfo.goversion == nil || f.initModuleData() != nil {
+		return 0, false
+	}
+	etext := f.moduledata.TextAddr + f.moduledata.TextLen
+	if etext <= addr || etext >= addr+size {
+		return 0, false
+	}
+	// External linkers can place C code after the Go code, so the section
+	// is only split if the read-only data doesn't have a section of its own.
+	if _, err := f.fh.getRData(); !errors.Is(err, ErrSectionDoesNotExist) {
+		return 0, false
+	}
+	return etext, true
+}
+
+// splitMergedSection splits a section starting at addr holding both code
+// and read-only data at etext.
+func splitMergedSection(addr uint64, data []byte, etext uint64) ([]byte, []byte) {
+	if etext <= addr {
+		return nil, data
+	}
+	off := min(etext-addr, uint64(len(data)))
+	return data[:off], data[off:]
+}
+
 // Bytes return a slice of raw bytes with the length in the file from the address.
 func (f *GoFile) Bytes(address uint64, length uint64) ([]byte, error) {
 	base, section, err := f.fh.getSectionDataFromAddress(address)
@@ -598,10 +1470,15 @@ func sortTypes(types map[uint64]*GoType) []*GoType {
 		i++
 	}
 	sort.Slice(sortedList, func(i, j int) bool {
-		if sortedList[i].PackagePath == sortedList[j].PackagePath {
+		if sortedList[i].PackagePath != sortedList[j].PackagePath {
+			return sortedList[i].PackagePath < sortedList[j].PackagePath
+		}
+		if sortedList[i].Name != sortedList[j].Name {
 			return sortedList[i].Name < sortedList[j].Name
 		}
-		return sortedList[i].PackagePath < sortedList[j].PackagePath
+		// Types with the same name from different packages without a
+		// package path are ordered by their address to keep the order stable.
+		return sortedList[i].Addr < sortedList[j].Addr
 	})
 	return sortedList
 }
@@ -610,17 +1487,31 @@ type fileHandler interface {
 	io.Closer
 	// returns the value, size and error
 	getSymbol(name string) (Symbol, error)
+	// returns the names of all symbols in the symbol table
+	getSymbolNames() ([]string, error)
 	getRData() ([]byte, error)
 	getCodeSection() (uint64, []byte, error)
+	getCodeSectionReader() (uint64, io.ReaderAt, int64, error)
 	getSectionDataFromAddress(uint64) (uint64, []byte, error)
 	getSectionData(string) (uint64, []byte, error)
 	getFileInfo() *FileInfo
 	getPCLNTABData() (uint64, []byte, error)
-	moduledataSection() string
+	// moduledataSections returns the sections that can hold the moduledata
+	// structure, in the order they should be searched.
+	moduledataSections() []string
 	getBuildID() (string, error)
 	getReader() io.ReaderAt
 	getParsedFile() any
 	getDwarf() (*dwarf.Data, error)
+	// getImportedLibraries returns the names of the shared libraries the
+	// file depends on.
+	getImportedLibraries() ([]string, error)
+	getSections() ([]Section, error)
+	getEntryPoint() (uint64, error)
+	// getExports returns the symbols in the export table.
+	getExports() ([]Export, error)
+	// getDynamicImports returns the symbols imported from shared libraries.
+	getDynamicImports() ([]DynamicImport, error)
 }
 
 func fileMagicMatch(buf, magic []byte) bool {
@@ -630,20 +1521,104 @@ func fileMagicMatch(buf, magic []byte) bool {
 // FileInfo holds information about the file.
 type FileInfo struct {
 	// Arch is the architecture the binary is compiled for.
-	Arch string
+	Arch string `json:"arch"`
 	// OS is the operating system the binary is compiled for.
-	OS string
+	OS string `json:"os"`
 	// ByteOrder is the byte order.
-	ByteOrder binary.ByteOrder
+	ByteOrder binary.ByteOrder `json:"-"`
 	// WordSize is the natural integer size used by the file.
-	WordSize  int
+	WordSize  int `json:"wordSize"`
 	goversion *GoVersion
 }
 
+// MarshalJSON implements the json.Marshaler interface. The byte order is
+// encoded by its name, for example "LittleEndian".
+func (f *FileInfo) MarshalJSON() ([]byte, error) {
+	var order string
+	if f.ByteOrder != nil {
+		order = f.ByteOrder.String()
+	}
+	type fileInfo FileInfo
+	return json.Marshal(&struct {
+		*fileInfo
+		ByteOrder string `json:"byteOrder"`
+	}{(*fileInfo)(f), order})
+}
+
 const (
-	ArchAMD64 = "amd64"
-	ArchARM   = "arm"
-	ArchARM64 = "arm64"
-	Arch386   = "i386"
-	ArchMIPS  = "mips"
+	ArchAMD64   = "amd64"
+	ArchARM     = "arm"
+	ArchARM64   = "arm64"
+	Arch386     = "i386"
+	ArchMIPS    = "mips"
+	ArchRISCV64 = "riscv64"
+	ArchPPC64   = "ppc64"
+	ArchPPC64LE = "ppc64le"
+	ArchS390X   = "s390x"
+	ArchLoong64 = "loong64"
+	ArchWasm    = "wasm"
 )
+
+// Architecture is a typed representation of the architecture the binary is
+// compiled for. It can be used instead of comparing the FileInfo.Arch string.
+type Architecture uint8
+
+const (
+	// ArchitectureUnknown is used for architectures not known by the library.
+	ArchitectureUnknown Architecture = iota
+	// ArchitectureAMD64 is the amd64 architecture.
+	ArchitectureAMD64
+	// Architecture386 is the i386 architecture.
+	Architecture386
+	// ArchitectureARM is the 32 bit arm architecture.
+	ArchitectureARM
+	// ArchitectureARM64 is the arm64 architecture.
+	ArchitectureARM64
+	// ArchitectureMIPS is the mips architecture.
+	ArchitectureMIPS
+	// ArchitectureRISCV64 is the riscv64 architecture.
+	ArchitectureRISCV64
+	// ArchitecturePPC64 is the big endian ppc64 architecture.
+	ArchitecturePPC64
+	// ArchitecturePPC64LE is the little endian ppc64 architecture.
+	ArchitecturePPC64LE
+	// ArchitectureS390X is the s390x architecture.
+	ArchitectureS390X
+	// ArchitectureLoong64 is the 64-bit LoongArch architecture.
+	ArchitectureLoong64
+	// ArchitectureWasm is the WebAssembly architecture.
+	ArchitectureWasm
+)
+
+var architectureNames = map[Architecture]string{
+	ArchitectureAMD64:   ArchAMD64,
+	Architecture386:     Arch386,
+	ArchitectureARM:     ArchARM,
+	ArchitectureARM64:   ArchARM64,
+	ArchitectureMIPS:    ArchMIPS,
+	ArchitectureRISCV64: ArchRISCV64,
+	ArchitecturePPC64:   ArchPPC64,
+	ArchitecturePPC64LE: ArchPPC64LE,
+	ArchitectureS390X:   ArchS390X,
+	ArchitectureLoong64: ArchLoong64,
+	ArchitectureWasm:    ArchWasm,
+}
+
+// String returns the architecture as used in FileInfo.Arch.
+func (a Architecture) String() string {
+	if name, ok := architectureNames[a]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Architecture returns the architecture the binary is compiled for.
+// ArchitectureUnknown is returned if the architecture isn't known.
+func (f *GoFile) Architecture() Architecture {
+	for a, name := range architectureNames {
+		if f.FileInfo.Arch == name {
+			return a
+		}
+	}
+	return ArchitectureUnknown
+}
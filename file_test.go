@@ -18,19 +18,26 @@
 package gore
 
 import (
+	"bytes"
+	"context"
 	"debug/dwarf"
 	"debug/elf"
+	"debug/gosym"
 	"debug/pe"
+	"encoding/binary"
 	"errors"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/blacktop/go-macho"
+	"github.com/blacktop/go-macho/types"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -172,6 +179,16 @@ func TestGoldFiles(t *testing.T) {
 			require.NotNil(version, "Version should not be nil")
 			assert.Equal("go"+actualVersion, version.Name, "Incorrect version for "+file)
 
+			// Since Go 1.21, the function addresses in the pclntab are relative
+			// to runtime.text. Check that they match the symbol table.
+			if sym, err := f.GetSymbol("main.main"); err == nil && GoVersionCompare(version.Name, "go1.21.0") >= 0 {
+				tab, err := f.PCLNTab()
+				require.NoError(err)
+				fn := tab.LookupFunc("main.main")
+				require.NotNil(fn)
+				assert.Equal(sym.Value, fn.Entry, "Incorrect main.main address for "+file)
+			}
+
 			reader := f.GetReader()
 			require.NotNil(reader, "File should not be nil")
 
@@ -218,8 +235,435 @@ func TestSetGoVersion(t *testing.T) {
 	})
 }
 
+func TestCloseIsIdempotent(t *testing.T) {
+	t.Run("handler is only closed once", func(t *testing.T) {
+		calls := 0
+		closeErr := errors.New("close failed")
+		f := &GoFile{fh: &mockFileHandler{mClose: func() error {
+			calls++
+			return closeErr
+		}}}
+
+		assert.ErrorIs(t, f.Close(), closeErr)
+		assert.ErrorIs(t, f.Close(), closeErr)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("no handler", func(t *testing.T) {
+		f := new(GoFile)
+		assert.NoError(t, f.Close())
+		assert.NoError(t, f.Close())
+	})
+}
+
+func TestIsBoringCryptoFromBuildSettings(t *testing.T) {
+	f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+		{Key: "GOEXPERIMENT", Value: "boringcrypto"},
+	}}}}
+	boring, err := f.IsBoringCrypto()
+	assert.NoError(t, err)
+	assert.True(t, boring)
+}
+
+func TestGetResolverModeFromBuildTags(t *testing.T) {
+	for tags, expected := range map[string]string{
+		"netgo":          ResolverModeGo,
+		"osusergo,netgo": ResolverModeGo,
+		"netcgo":         ResolverModeCgo,
+	} {
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "-tags", Value: tags},
+		}}}}
+		mode, err := f.GetResolverMode()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, mode, tags)
+	}
+}
+
+func TestUsesRegABI(t *testing.T) {
+	for _, test := range []struct {
+		version    string
+		arch       string
+		experiment string
+		expected   bool
+	}{
+		{"go1.16.15", ArchAMD64, "", false},
+		{"go1.17.13", ArchAMD64, "", true},
+		{"go1.17.13", ArchARM64, "", false},
+		{"go1.18.10", ArchAMD64, "noregabi", false},
+		{"go1.18.10", ArchARM64, "", true},
+		{"go1.18.10", ArchARM64, "noregabiargs", false},
+		{"go1.19.13", ArchAMD64, "noregabi", true},
+		{"go1.19.13", ArchRISCV64, "noregabi,loopvar", false},
+		{"go1.21.13", ArchRISCV64, "noregabi", true},
+		{"go1.22.8", ArchLoong64, "", true},
+		{"go1.23.4", Arch386, "", false},
+		{"go1.23.4", ArchS390X, "", false},
+	} {
+		f := &GoFile{
+			FileInfo: &FileInfo{Arch: test.arch, goversion: &GoVersion{Name: test.version}},
+			fh:       &peFile{},
+			BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+				{Key: "GOEXPERIMENT", Value: test.experiment},
+			}}},
+		}
+		regabi, err := f.UsesRegABI()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, regabi, "%s %s %s", test.version, test.arch, test.experiment)
+	}
+}
+
+func TestGetRDataMergedSection(t *testing.T) {
+	code := make([]byte, 0x20)
+	for i := range code {
+		code[i] = byte(i)
+	}
+	newFile := func(rdata []byte) *GoFile {
+		f := &GoFile{FileInfo: &FileInfo{goversion: goversions["go1.20"]}, fh: &mockFileHandler{
+			mGetRData: func() ([]byte, error) {
+				if rdata == nil {
+					return nil, ErrSectionDoesNotExist
+				}
+				return rdata, nil
+			},
+			mGetCodeSection: func() (uint64, []byte, error) {
+				return 0x1000, code, nil
+			},
+		}}
+		f.initModuleDataOnce.Do(func() {})
+		f.moduledata = moduledata{TextAddr: 0x1000, TextLen: 0x18}
+		return f
+	}
+
+	t.Run("merged", func(t *testing.T) {
+		data, err := newFile(nil).getRData()
+		assert.NoError(t, err)
+		assert.Equal(t, code[0x18:], data)
+	})
+
+	t.Run("separate section", func(t *testing.T) {
+		data, err := newFile([]byte{0xff}).getRData()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0xff}, data)
+	})
+}
+
+func TestSplitMergedSection(t *testing.T) {
+	data := []byte{1, 2, 3, 4}
+	code, rodata := splitMergedSection(0x1000, data, 0x1003)
+	assert.Equal(t, []byte{1, 2, 3}, code)
+	assert.Equal(t, []byte{4}, rodata)
+
+	code, rodata = splitMergedSection(0x1000, data, 0x2000)
+	assert.Equal(t, data, code)
+	assert.Empty(t, rodata)
+
+	code, rodata = splitMergedSection(0x1000, data, 0x800)
+	assert.Empty(t, code)
+	assert.Equal(t, data, rodata)
+}
+
+func TestFilterTypesByKind(t *testing.T) {
+	ch := &GoType{Kind: reflect.Chan, Name: "chan int"}
+	m := &GoType{Kind: reflect.Map, Name: "map[string]int"}
+	sl := &GoType{Kind: reflect.Slice, Name: "[]int"}
+	types := []*GoType{ch, m, sl, {Kind: reflect.Int, Name: "int"}}
+
+	assert.Equal(t, []*GoType{ch}, filterTypesByKind(types, reflect.Chan))
+	assert.Equal(t, []*GoType{m}, filterTypesByKind(types, reflect.Map))
+	assert.Equal(t, []*GoType{sl}, filterTypesByKind(types, reflect.Slice))
+	assert.Empty(t, filterTypesByKind(types, reflect.Struct))
+}
+
+func TestOpenReaderInMemory(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+
+	f, err := OpenReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.NotEmpty(t, f.BuildID)
+	assert.NotNil(t, f.GetReader())
+	assert.NoError(t, f.Close())
+}
+
+type closeCountingReader struct {
+	*bytes.Reader
+	closed int
+}
+
+func (c *closeCountingReader) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestKeepReaderOpen(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+
+	r := &closeCountingReader{Reader: bytes.NewReader(data)}
+	f, err := OpenReaderWithOptions(r, Options{KeepReaderOpen: true})
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	assert.Equal(t, 0, r.closed)
+
+	r = &closeCountingReader{Reader: bytes.NewReader(data)}
+	f, err = OpenReader(r)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	assert.Equal(t, 1, r.closed)
+}
+
+func TestMaxSectionBytes(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	f, err := OpenWithOptions(exe, Options{MaxSectionBytes: 1})
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.PCLNTab()
+	assert.ErrorIs(t, err, ErrSectionTooLarge)
+
+	if _, ok := f.fh.(*elfFile); ok {
+		err = f.WriteWithSymbols(io.Discard, nil)
+		assert.ErrorIs(t, err, ErrSectionTooLarge, "symbol table rewrite")
+	}
+}
+
+func TestCheckDwarfSectionSize(t *testing.T) {
+	zlib := []byte("ZLIB\x00\x00\x00\x00\x00\x00\x10\x00compressed")
+	r := bytes.NewReader(zlib)
+
+	assert.NoError(t, checkDwarfSectionSize(".zdebug_info", uint64(len(zlib)), r, 0, 0), "no limit")
+	assert.NoError(t, checkDwarfSectionSize(".zdebug_info", uint64(len(zlib)), r, 0, 0x1000))
+	assert.ErrorIs(t, checkDwarfSectionSize(".zdebug_info", uint64(len(zlib)), r, 0, 0x100), ErrSectionTooLarge, "uncompressed size")
+	assert.ErrorIs(t, checkDwarfSectionSize(".debug_info", 0x200, r, 4, 0x100), ErrSectionTooLarge)
+	assert.NoError(t, checkDwarfSectionSize(".debug_info", uint64(len(zlib)-4), r, 4, 0x100), "not compressed")
+}
+
+func TestPCLNTabIsCached(t *testing.T) {
+	f := &GoFile{}
+	// The raw table is already set.
+	f.pclntabOnce.Do(func() {})
+
+	tab, err := f.PCLNTab()
+	require.NoError(t, err)
+	again, err := f.PCLNTab()
+	require.NoError(t, err)
+	assert.Same(t, tab, again)
+
+	f = &GoFile{pclntabError: ErrNoPCLNTab}
+	f.pclntabOnce.Do(func() {})
+	for i := 0; i < 2; i++ {
+		_, err = f.PCLNTab()
+		assert.ErrorIs(t, err, ErrNoPCLNTab)
+	}
+}
+
+func TestEnumPackagesCanceled(t *testing.T) {
+	f := &GoFile{pclntab: &gosym.Table{Funcs: []gosym.Func{
+		{Entry: 0x1000, End: 0x1010, Sym: &gosym.Sym{Name: "main.main"}},
+	}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := f.enumPackages(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, f.pkgs)
+}
+
+func TestCheckSectionSize(t *testing.T) {
+	assert.NoError(t, checkSectionSize(".text", 1<<40, 0))
+	assert.NoError(t, checkSectionSize(".text", 10, 10))
+	assert.ErrorIs(t, checkSectionSize(".text", 11, 10), ErrSectionTooLarge)
+}
+
+func TestArchitecture(t *testing.T) {
+	for _, test := range []struct {
+		arch     string
+		expected Architecture
+	}{
+		{ArchAMD64, ArchitectureAMD64},
+		{Arch386, Architecture386},
+		{ArchARM, ArchitectureARM},
+		{ArchARM64, ArchitectureARM64},
+		{ArchMIPS, ArchitectureMIPS},
+		{ArchRISCV64, ArchitectureRISCV64},
+		{ArchPPC64, ArchitecturePPC64},
+		{ArchPPC64LE, ArchitecturePPC64LE},
+		{ArchS390X, ArchitectureS390X},
+		{ArchLoong64, ArchitectureLoong64},
+		{ArchWasm, ArchitectureWasm},
+		{"sparc64", ArchitectureUnknown},
+	} {
+		f := &GoFile{FileInfo: &FileInfo{Arch: test.arch}}
+		a := f.Architecture()
+		assert.Equal(t, test.expected, a, test.arch)
+		if a != ArchitectureUnknown {
+			assert.Equal(t, test.arch, a.String())
+		}
+	}
+	assert.Equal(t, "unknown", ArchitectureUnknown.String())
+}
+
+func TestELFFileInfoArch(t *testing.T) {
+	for _, test := range []struct {
+		machine elf.Machine
+		data    elf.Data
+		arch    string
+	}{
+		{elf.EM_RISCV, elf.ELFDATA2LSB, ArchRISCV64},
+		{elf.EM_PPC64, elf.ELFDATA2MSB, ArchPPC64},
+		{elf.EM_PPC64, elf.ELFDATA2LSB, ArchPPC64LE},
+		{elf.EM_S390, elf.ELFDATA2MSB, ArchS390X},
+		{elf.EM_LOONGARCH, elf.ELFDATA2LSB, ArchLoong64},
+	} {
+		e := &elfFile{file: &elf.File{FileHeader: elf.FileHeader{
+			Class:   elf.ELFCLASS64,
+			Data:    test.data,
+			Machine: test.machine,
+		}}}
+		fi := e.getFileInfo()
+		assert.Equal(t, test.arch, fi.Arch)
+		assert.Equal(t, intSize64, fi.WordSize)
+	}
+}
+
+func TestIsStatic(t *testing.T) {
+	f := &GoFile{fh: &elfFile{file: &elf.File{Progs: []*elf.Prog{
+		{ProgHeader: elf.ProgHeader{Type: elf.PT_LOAD}},
+	}}}}
+	static, err := f.IsStatic()
+	assert.NoError(t, err)
+	assert.True(t, static)
+
+	f = &GoFile{fh: &elfFile{file: &elf.File{Progs: []*elf.Prog{
+		{ProgHeader: elf.ProgHeader{Type: elf.PT_INTERP}},
+		{ProgHeader: elf.ProgHeader{Type: elf.PT_LOAD}},
+	}}}}
+	static, err = f.IsStatic()
+	assert.NoError(t, err)
+	assert.False(t, static)
+
+	f = &GoFile{fh: &mockFileHandler{}}
+	_, err = f.IsStatic()
+	assert.ErrorIs(t, err, ErrUnsupportedFile)
+}
+
+func TestImportedLibrariesWithoutDynamicSection(t *testing.T) {
+	f := &GoFile{fh: &elfFile{file: &elf.File{}}}
+	libs, err := f.ImportedLibraries()
+	assert.NoError(t, err)
+	assert.Empty(t, libs)
+}
+
+func TestSymbols(t *testing.T) {
+	symm := map[string]Symbol{
+		"main.main":         {Name: "main.main", Value: 0x401020, Size: 0x20},
+		"runtime.text":      {Name: "runtime.text", Value: 0x401000},
+		"internal/abi.text": {Name: "internal/abi.text", Value: 0x401000},
+	}
+	f := &GoFile{fh: &mockFileHandler{
+		mGetSymbolNames: func() ([]string, error) {
+			return symbolNames(symm), nil
+		},
+		mGetSymbol: func(name string) (Symbol, error) {
+			return symm[name], nil
+		},
+	}}
+	syms, err := f.Symbols()
+	assert.NoError(t, err)
+	assert.Equal(t, []Symbol{symm["internal/abi.text"], symm["runtime.text"], symm["main.main"]}, syms)
+}
+
+func TestEntryPoint(t *testing.T) {
+	f := &GoFile{fh: &elfFile{file: &elf.File{FileHeader: elf.FileHeader{Entry: 0x463960}}}}
+	entry, err := f.EntryPoint()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x463960), entry)
+
+	f = &GoFile{fh: &wasmFile{}}
+	_, err = f.EntryPoint()
+	assert.ErrorIs(t, err, ErrUnsupportedFile)
+}
+
+func TestThreadStatePC(t *testing.T) {
+	data := make([]byte, 68*4)
+	binary.LittleEndian.PutUint64(data[128:], 0x1000644c0)
+	pc, ok := threadStatePC(types.ThreadState{Flavor: 4, Count: 42, Data: data}, binary.LittleEndian)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0x1000644c0), pc)
+
+	_, ok = threadStatePC(types.ThreadState{Flavor: 4, Data: data[:100]}, binary.LittleEndian)
+	assert.False(t, ok, "truncated thread state")
+
+	_, ok = threadStatePC(types.ThreadState{Flavor: 99, Data: data}, binary.LittleEndian)
+	assert.False(t, ok, "unknown flavor")
+}
+
+func TestPEExports(t *testing.T) {
+	r := require.New(t)
+
+	// A section at 0x1000 with the export directory followed by the
+	// address, name and ordinal tables and the names. The second export is
+	// forwarded to another DLL.
+	sec := make([]byte, 0x100)
+	le := binary.LittleEndian
+	le.PutUint32(sec[20:], 2)      // NumberOfFunctions
+	le.PutUint32(sec[24:], 2)      // NumberOfNames
+	le.PutUint32(sec[28:], 0x1040) // AddressOfFunctions
+	le.PutUint32(sec[32:], 0x1050) // AddressOfNames
+	le.PutUint32(sec[36:], 0x1060) // AddressOfNameOrdinals
+	le.PutUint32(sec[0x40:], 0x2000)
+	le.PutUint32(sec[0x44:], 0x1080)
+	le.PutUint32(sec[0x50:], 0x1070)
+	le.PutUint32(sec[0x54:], 0x1078)
+	le.PutUint16(sec[0x60:], 0)
+	le.PutUint16(sec[0x62:], 1)
+	copy(sec[0x70:], "Add\x00")
+	copy(sec[0x78:], "Fwd\x00")
+	copy(sec[0x80:], "other.Fn\x00")
+
+	dir := pe.DataDirectory{VirtualAddress: 0x1000, Size: 0x100}
+	exports, err := peExports(0x400000, dir, func(rva uint32) ([]byte, error) {
+		if rva < 0x1000 || rva >= 0x1100 {
+			return nil, ErrSectionDoesNotExist
+		}
+		return sec[rva-0x1000:], nil
+	})
+	r.NoError(err)
+	r.Equal([]Export{{Name: "Add", Address: 0x402000}}, exports)
+
+	_, err = peExports(0x400000, pe.DataDirectory{VirtualAddress: 0x3000, Size: 0x28}, func(uint32) ([]byte, error) {
+		return nil, ErrSectionDoesNotExist
+	})
+	r.ErrorIs(err, ErrSectionDoesNotExist)
+}
+
+func TestExportScriptUnsupportedFormat(t *testing.T) {
+	f := new(GoFile)
+	_, err := f.ExportScript("radare2")
+	assert.Error(t, err)
+}
+
 type mockFileHandler struct {
 	mGetSectionDataFromAddress func(uint64) (uint64, []byte, error)
+	mClose                     func() error
+	mGetRData                  func() ([]byte, error)
+	mGetSymbolNames            func() ([]string, error)
+	mGetCodeSection            func() (uint64, []byte, error)
+	mGetSymbol                 func(string) (Symbol, error)
+	mGetSectionData            func(string) (uint64, []byte, error)
+	mModuledataSections        func() []string
+	mGetImportedLibraries      func() ([]string, error)
+	mGetSections               func() ([]Section, error)
+	mGetEntryPoint             func() (uint64, error)
+	mGetExports                func() ([]Export, error)
+	mGetDynamicImports         func() ([]DynamicImport, error)
 }
 
 func (m *mockFileHandler) getReader() io.ReaderAt {
@@ -227,6 +671,16 @@ func (m *mockFileHandler) getReader() io.ReaderAt {
 }
 
 func (m *mockFileHandler) getSymbol(name string) (Symbol, error) {
+	if m.mGetSymbol != nil {
+		return m.mGetSymbol(name)
+	}
+	panic("not implemented")
+}
+
+func (m *mockFileHandler) getSymbolNames() ([]string, error) {
+	if m.mGetSymbolNames != nil {
+		return m.mGetSymbolNames()
+	}
 	panic("not implemented")
 }
 
@@ -235,14 +689,27 @@ func (m *mockFileHandler) getParsedFile() any {
 }
 
 func (m *mockFileHandler) Close() error {
+	if m.mClose != nil {
+		return m.mClose()
+	}
 	panic("not implemented")
 }
 
 func (m *mockFileHandler) getRData() ([]byte, error) {
+	if m.mGetRData != nil {
+		return m.mGetRData()
+	}
 	panic("not implemented")
 }
 
 func (m *mockFileHandler) getCodeSection() (uint64, []byte, error) {
+	if m.mGetCodeSection != nil {
+		return m.mGetCodeSection()
+	}
+	panic("not implemented")
+}
+
+func (m *mockFileHandler) getCodeSectionReader() (uint64, io.ReaderAt, int64, error) {
 	panic("not implemented")
 }
 
@@ -250,7 +717,10 @@ func (m *mockFileHandler) getSectionDataFromAddress(a uint64) (uint64, []byte, e
 	return m.mGetSectionDataFromAddress(a)
 }
 
-func (m *mockFileHandler) getSectionData(string) (uint64, []byte, error) {
+func (m *mockFileHandler) getSectionData(name string) (uint64, []byte, error) {
+	if m.mGetSectionData != nil {
+		return m.mGetSectionData(name)
+	}
 	panic("not implemented")
 }
 
@@ -262,7 +732,10 @@ func (m *mockFileHandler) getPCLNTABData() (uint64, []byte, error) {
 	panic("not implemented")
 }
 
-func (m *mockFileHandler) moduledataSection() string {
+func (m *mockFileHandler) moduledataSections() []string {
+	if m.mModuledataSections != nil {
+		return m.mModuledataSections()
+	}
 	panic("not implemented")
 }
 
@@ -274,6 +747,41 @@ func (m *mockFileHandler) getDwarf() (*dwarf.Data, error) {
 	panic("not implemented")
 }
 
+func (m *mockFileHandler) getEntryPoint() (uint64, error) {
+	if m.mGetEntryPoint != nil {
+		return m.mGetEntryPoint()
+	}
+	panic("not implemented")
+}
+
+func (m *mockFileHandler) getExports() ([]Export, error) {
+	if m.mGetExports != nil {
+		return m.mGetExports()
+	}
+	panic("not implemented")
+}
+
+func (m *mockFileHandler) getDynamicImports() ([]DynamicImport, error) {
+	if m.mGetDynamicImports != nil {
+		return m.mGetDynamicImports()
+	}
+	panic("not implemented")
+}
+
+func (m *mockFileHandler) getSections() ([]Section, error) {
+	if m.mGetSections != nil {
+		return m.mGetSections()
+	}
+	panic("not implemented")
+}
+
+func (m *mockFileHandler) getImportedLibraries() ([]string, error) {
+	if m.mGetImportedLibraries != nil {
+		return m.mGetImportedLibraries()
+	}
+	panic("not implemented")
+}
+
 func TestBytes(t *testing.T) {
 	assert := assert.New(t)
 	expectedBase := uint64(0x40000)
@@ -343,3 +851,24 @@ func main() {
 	fmt.Println(data)
 }
 `
+
+func BenchmarkEnumPackages(b *testing.B) {
+	// The test binary itself is used as a large fixture.
+	exe, err := os.Executable()
+	require.NoError(b, err)
+	f, err := Open(exe)
+	require.NoError(b, err)
+	defer f.Close()
+	_, err = f.GetPackages()
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.stdPkgs, f.generated, f.pkgs, f.vendors, f.unknown = nil, nil, nil, nil, nil
+		f.warnings = nil
+		if err := f.enumPackages(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
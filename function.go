@@ -18,8 +18,11 @@
 package gore
 
 import (
+	"cmp"
 	"debug/gosym"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -34,6 +37,16 @@ type Function struct {
 	End uint64 `json:"end"`
 	// PackageName is the name of the Go package the function belongs to.
 	PackageName string `json:"packageName"`
+
+	// lines resolves the source file of methods, which shows if the method
+	// is a wrapper generated by the compiler.
+	lines lineTable
+}
+
+// lineTable maps program counters to source lines. It's implemented by
+// gosym.Table.
+type lineTable interface {
+	PCToLine(pc uint64) (file string, line int, fn *gosym.Func)
 }
 
 // String returns a string representation of the function.
@@ -41,6 +54,28 @@ func (f *Function) String() string {
 	return f.Name
 }
 
+// IsMethodWrapper returns true if the function is a wrapper generated by the
+// compiler for a method value or a method expression. A method value wrapper
+// is generated when t.Method is assigned to a variable or passed as an
+// argument. It binds the receiver and calls the method, and has the name of
+// the method with the suffix "-fm", or "·fm" for binaries compiled with old
+// Go versions. Method expressions, pointer receivers and promoted methods
+// use wrappers that the compiler attributes to the "<autogenerated>" source
+// file. The source file is only looked up for the methods returned by the
+// GoFile. The wrappers don't correspond to any function written by the user,
+// so they can be filtered out when listing the functions, see
+// Package.WithoutMethodWrappers.
+func (f *Function) IsMethodWrapper() bool {
+	if strings.HasSuffix(f.Name, "-fm") || strings.HasSuffix(f.Name, "·fm") {
+		return true
+	}
+	if f.lines == nil {
+		return false
+	}
+	file, _, _ := f.lines.PCToLine(f.Offset)
+	return file == "<autogenerated>"
+}
+
 // Method is a representation of a Go method.
 type Method struct {
 	// Receiver is the name of the method receiver.
@@ -53,15 +88,275 @@ func (m *Method) String() string {
 	return fmt.Sprintf("%s%s", m.Receiver, m.Name)
 }
 
+// FunctionForAddress returns the function or method containing the address.
+// This can be used to resolve the program counters in a stack trace. The
+// returned Function is the same as the one held by the function's package.
+// If the address is not within any function, ErrFunctionNotFound is returned.
+func (f *GoFile) FunctionForAddress(addr uint64) (*Function, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+	fn := f.pclntab.PCToFunc(addr)
+	if fn == nil {
+		return nil, fmt.Errorf("no function found for address 0x%x: %w", addr, ErrFunctionNotFound)
+	}
+
+	pkgName := fn.PackageName()
+	for _, pkgs := range [][]*Package{f.stdPkgs, f.generated, f.pkgs, f.vendors, f.unknown} {
+		for _, p := range pkgs {
+			if p.Name != pkgName {
+				continue
+			}
+			for _, pf := range p.Functions {
+				if pf.Offset == fn.Entry {
+					return pf, nil
+				}
+			}
+			for _, m := range p.Methods {
+				if m.Offset == fn.Entry {
+					return m.Function, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("no function found for address 0x%x: %w", addr, ErrFunctionNotFound)
+}
+
+// FunctionData returns the machine code of the function. An error is
+// returned if the function doesn't have a valid address range, which is the
+// case for some assembly functions that don't have their end recorded.
+func (f *GoFile) FunctionData(fn *Function) ([]byte, error) {
+	if fn.End <= fn.Offset {
+		return nil, fmt.Errorf("function %s has an invalid address range", fn.Name)
+	}
+	buf, err := f.Bytes(fn.Offset, fn.End-fn.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the code for function %s: %w", fn.Name, err)
+	}
+	return buf, nil
+}
+
+// MaxFrameSize returns the maximum size of the stack frame used by the
+// function. The size is the largest stack pointer delta recorded in the
+// function's pcsp table and doesn't include the arguments or the return
+// address. Functions with a large frame, or recursive functions with a
+// non-trivial frame, can be used to exhaust the stack.
+// The pcsp table is only parsed for binaries compiled with Go 1.16 or later.
+// For older binaries, ErrUnsupportedPCLNTabVersion is returned.
+func (f *GoFile) MaxFrameSize(fn *Function) (int, error) {
+	err := f.initPclnTable()
+	if err != nil {
+		return 0, err
+	}
+	tab := f.pclnTable
+
+	idx, ok := tab.findFunc(fn.Offset)
+	if !ok {
+		return 0, fmt.Errorf("no function found for address 0x%x: %w", fn.Offset, ErrFunctionNotFound)
+	}
+	fi, err := tab.funcInfo(idx, 0)
+	if err != nil {
+		return 0, err
+	}
+	if fi.entry != fn.Offset {
+		return 0, fmt.Errorf("no function found for address 0x%x: %w", fn.Offset, ErrFunctionNotFound)
+	}
+	return tab.maxFrameSize(fi), nil
+}
+
+// Frame is a logical stack frame. When functions have been inlined by the
+// compiler, multiple logical frames map to the same physical stack frame.
+type Frame struct {
+	// Function is the name of the function, including the package.
+	Function string `json:"function"`
+	// File is the source code file.
+	File string `json:"file"`
+	// Line is the source code line.
+	Line int `json:"line"`
+	// Inlined is true if the function has been inlined into its caller.
+	Inlined bool `json:"inlined"`
+}
+
+// PCToInlineStack returns the logical stack frames for the pc. The first frame
+// is the innermost inlined function and the last frame is the function that
+// contains the pc. If no functions have been inlined at the pc, only the
+// containing function is returned.
+// The inline information is only available in binaries compiled with Go 1.16
+// or later. For older binaries, ErrUnsupportedPCLNTabVersion is returned.
+func (f *GoFile) PCToInlineStack(pc uint64) ([]Frame, error) {
+	err := f.initPclnTable()
+	if err != nil {
+		return nil, err
+	}
+	tab := f.pclnTable
+
+	idx, ok := tab.findFunc(pc)
+	if !ok {
+		return nil, fmt.Errorf("no function found for pc 0x%x", pc)
+	}
+	fi, err := tab.funcInfo(idx, f.goFuncValue())
+	if err != nil {
+		return nil, err
+	}
+
+	var inlTree uint64
+	if len(fi.pcdata) > pcdataInlTreeIndex && len(fi.funcdata) > funcdataInlTree {
+		inlTree = fi.funcdata[funcdataInlTree]
+	}
+
+	var frames []Frame
+	if inlTree != 0 {
+		size := tab.inlinedCallSize()
+		for {
+			ix, ok := tab.pcvalue(fi.pcdata[pcdataInlTreeIndex], fi.entry, pc)
+			if !ok || ix < 0 {
+				break
+			}
+			buf, err := f.Bytes(inlTree+uint64(ix)*size, size)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read inline tree entry %d for pc 0x%x: %w", ix, pc, err)
+			}
+			call := tab.parseInlinedCall(buf)
+
+			file, line := tab.fileLine(fi, pc)
+			frames = append(frames, Frame{
+				Function: tab.funcName(call.nameOff),
+				File:     file,
+				Line:     line,
+				Inlined:  true,
+			})
+
+			// The parent pc is an instruction in the caller that has the
+			// source position of the call site.
+			next := fi.entry + uint64(call.parentPc)
+			if next == pc {
+				// Avoid looping forever on a corrupt inline tree.
+				break
+			}
+			pc = next
+		}
+	}
+
+	file, line := tab.fileLine(fi, pc)
+	frames = append(frames, Frame{
+		Function: tab.funcName(fi.nameOff),
+		File:     file,
+		Line:     line,
+	})
+	return frames, nil
+}
+
+// AllSourceLines returns the source code lines that have code in the binary.
+// The result maps each source file to its line numbers, sorted in ascending
+// order. The lines are collected from the line tables of all the functions in
+// the pclntab, so lines of inlined functions are included.
+// The line tables are only parsed for binaries compiled with Go 1.16 or later.
+// For older binaries, ErrUnsupportedPCLNTabVersion is returned.
+func (f *GoFile) AllSourceLines() (map[string][]int, error) {
+	err := f.initPclnTable()
+	if err != nil {
+		return nil, err
+	}
+	tab := f.pclnTable
+
+	seen := make(map[string]map[int]struct{})
+	for i := 0; i < tab.nfunc; i++ {
+		fi, err := tab.funcInfo(i, 0)
+		if err != nil {
+			return nil, err
+		}
+		tab.fileLines(fi, func(file string, line int) {
+			lines, ok := seen[file]
+			if !ok {
+				lines = make(map[int]struct{})
+				seen[file] = lines
+			}
+			lines[line] = struct{}{}
+		})
+	}
+
+	result := make(map[string][]int, len(seen))
+	for file, lines := range seen {
+		sorted := make([]int, 0, len(lines))
+		for line := range lines {
+			sorted = append(sorted, line)
+		}
+		slices.Sort(sorted)
+		result[file] = sorted
+	}
+	return result, nil
+}
+
+// funcFlagAsm is the runtime's abi.FuncFlagAsm. It's set for functions
+// written in assembly by Go 1.21 and later.
+const funcFlagAsm = 1 << 2
+
+// GetAssemblyFunctions returns the functions that are written in assembly,
+// sorted by their offset. A function is considered to be written in assembly
+// if the ASM bit is set in its function flags or if its source position is
+// not a Go source file. The flag is only set by Go 1.21 and later, so older
+// binaries only rely on the source position.
+func (f *GoFile) GetAssemblyFunctions() ([]*Function, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	// The function flags are only available for Go 1.16 and later.
+	var tab *pclnTable
+	if f.initPclnTable() == nil {
+		tab = f.pclnTable
+	}
+
+	var fcns []*Function
+	for entry, fn := range f.functionsByEntry() {
+		if hasAsmFlag(tab, entry) || isAsmSource(f.pclntab, entry) {
+			fcns = append(fcns, fn)
+		}
+	}
+	slices.SortFunc(fcns, func(a, b *Function) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+	return fcns, nil
+}
+
+// hasAsmFlag returns true if the function at the entry has the ASM flag set.
+func hasAsmFlag(tab *pclnTable, entry uint64) bool {
+	if tab == nil {
+		return false
+	}
+	idx, ok := tab.findFunc(entry)
+	if !ok {
+		return false
+	}
+	fi, err := tab.funcInfo(idx, 0)
+	if err != nil || fi.entry != entry {
+		return false
+	}
+	return fi.flag&funcFlagAsm != 0
+}
+
+// isAsmSource returns true if the function at the entry doesn't have a
+// position in a Go source file. Functions written in assembly either have
+// their position in the assembly source file or no source line at all.
+func isAsmSource(tab *gosym.Table, entry uint64) bool {
+	file, line, _ := tab.PCToLine(entry)
+	if strings.HasSuffix(file, ".s") {
+		return true
+	}
+	return file == "" && line == 0
+}
+
 // FileEntry is a representation of an entry in a source code file. This can for example be
 // a function or a method.
 type FileEntry struct {
 	// Name of the function or method.
-	Name string
+	Name string `json:"name"`
 	// Start is the source line where the code starts.
-	Start int
+	Start int `json:"start"`
 	// End is the source line where the code ends.
-	End int
+	End int `json:"end"`
 }
 
 // String returns a string representation of the entry.
@@ -72,14 +367,30 @@ func (f FileEntry) String() string {
 // SourceFile is a representation of a source code file.
 type SourceFile struct {
 	// Name of the file.
-	Name string
+	Name string `json:"name"`
+	// FullPath is the path of the file as recorded in the binary when it was compiled.
+	FullPath string `json:"fullPath"`
 	// Prefix that should be added to each line.
-	Prefix string
+	Prefix string `json:"-"`
 	// Postfix that should be added to each line.
-	Postfix string
+	Postfix string `json:"-"`
 	entries []FileEntry
 }
 
+// MarshalJSON implements the json.Marshaler interface. The entries are
+// included, sorted by their start line.
+func (s *SourceFile) MarshalJSON() ([]byte, error) {
+	entries := slices.Clone(s.entries)
+	slices.SortStableFunc(entries, func(a, b FileEntry) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+	type sourceFile SourceFile
+	return json.Marshal(&struct {
+		*sourceFile
+		Entries []FileEntry `json:"entries"`
+	}{(*sourceFile)(s), entries})
+}
+
 // String produces a string representation of a source code file.
 // The multi-line string has this format:
 //		File: simple.go
@@ -140,3 +451,116 @@ func findSourceLines(entry, end uint64, tab *gosym.Table) (int, int) {
 
 	return srcStart, srcEnd
 }
+
+// GetGlobalInitializers returns the functions that initialize the
+// package-level variables, sorted by their offset. The compiler generates a
+// function named "init" in each package with variables that are initialized
+// at run time. Function literals assigned to package-level variables are
+// compiled into closures named "init.funcN", or "glob..funcN" by older
+// compilers, and large map literals are moved into "map.init.N" functions.
+// The init functions written by the user, named "init.N", and their closures
+// are not included.
+func (f *GoFile) GetGlobalInitializers() ([]*Function, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	// The closures are split into a receiver and a name by the symbol
+	// parsing, so the names are checked in the symbol table.
+	fcnsByEntry := f.functionsByEntry()
+	var fcns []*Function
+	for _, sym := range f.pclntab.Funcs {
+		name := strings.TrimPrefix(sym.Name, sym.PackageName()+".")
+		if !isGlobalInitializer(name) {
+			continue
+		}
+		if fn, ok := fcnsByEntry[sym.Entry]; ok {
+			fcns = append(fcns, fn)
+		}
+	}
+	slices.SortFunc(fcns, func(a, b *Function) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+	return fcns, nil
+}
+
+// isGlobalInitializer returns true if the symbol name, without the package,
+// is one of the names used by the compiler for the initialization
+// of package-level variables.
+func isGlobalInitializer(name string) bool {
+	if name == "init" {
+		return true
+	}
+	for _, prefix := range []string{"init.func", "glob..func", "map.init."} {
+		suffix, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		// Nested closures have the index of the inner closure appended.
+		n, _, _ := strings.Cut(suffix, ".")
+		if isDigits(n) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// startupFuncs are the functions executed after the entry point stubs on
+// the way to the program's main function.
+var startupFuncs = []string{"runtime.rt0_go", "runtime.main", "main.main"}
+
+// StartupChain returns the functions of the startup sequence that are found
+// in the binary, in the order they are executed. The sequence starts with
+// the entry point stubs, for example "_rt0_amd64_linux" followed by
+// "_rt0_amd64", and continues with runtime.rt0_go, runtime.main and
+// main.main. Not all the stubs exist on all platforms, so the missing
+// functions are skipped. The stubs used by binaries built as libraries are
+// not included.
+func (f *GoFile) StartupChain() ([]*Function, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, fn := range f.pclntab.Funcs {
+		if strings.HasPrefix(fn.Name, "_rt0_") && !strings.HasSuffix(fn.Name, "_lib") {
+			names = append(names, fn.Name)
+		}
+	}
+	// The OS specific stub, like "_rt0_amd64_linux", jumps to the
+	// architecture specific stub, like "_rt0_amd64".
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(strings.Count(b, "_"), strings.Count(a, "_")); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	names = append(names, startupFuncs...)
+
+	fcns := f.functionsByEntry()
+	var chain []*Function
+	for _, name := range names {
+		fn := f.pclntab.LookupFunc(name)
+		if fn == nil {
+			continue
+		}
+		if fcn, ok := fcns[fn.Entry]; ok {
+			chain = append(chain, fcn)
+		}
+	}
+	return chain, nil
+}
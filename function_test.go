@@ -18,6 +18,7 @@
 package gore
 
 import (
+	"debug/gosym"
 	"fmt"
 	"testing"
 
@@ -45,3 +46,95 @@ func TestStringer(t *testing.T) {
 	}
 
 }
+
+func TestIsGlobalInitializer(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"init":            true,
+		"init.func1":      true,
+		"init.func1.2":    true,
+		"glob..func3":     true,
+		"map.init.0":      true,
+		"init.0":          false,
+		"init.0.func1":    false,
+		"init.funcs":      false,
+		"map.init.":       false,
+		"initialize":      false,
+		"main":            false,
+		"(*T).init":       false,
+		"(*T).init.func1": false,
+		"glob..funcfoo1":  false,
+	} {
+		assert.Equal(t, expected, isGlobalInitializer(name), name)
+	}
+}
+
+func TestFunctionForAddress(t *testing.T) {
+	main := &Function{Name: "main", Offset: 0x1000, End: 0x1040, PackageName: "main"}
+	write := &Method{Receiver: "(*T)", Function: &Function{Name: "Write", Offset: 0x1040, End: 0x1080, PackageName: "main"}}
+	f := &GoFile{
+		pclntab: &gosym.Table{Funcs: []gosym.Func{
+			{Entry: 0x1000, End: 0x1040, Sym: &gosym.Sym{Name: "main.main"}},
+			{Entry: 0x1040, End: 0x1080, Sym: &gosym.Sym{Name: "main.(*T).Write"}},
+		}},
+		pkgs: []*Package{{Name: "main", Functions: []*Function{main}, Methods: []*Method{write}}},
+	}
+	// The packages are already set.
+	f.initPackagesDone = true
+
+	fn, err := f.FunctionForAddress(0x1020)
+	assert.NoError(t, err)
+	assert.Same(t, main, fn)
+
+	fn, err = f.FunctionForAddress(0x1040)
+	assert.NoError(t, err)
+	assert.Same(t, write.Function, fn)
+
+	_, err = f.FunctionForAddress(0x1080)
+	assert.ErrorIs(t, err, ErrFunctionNotFound)
+}
+
+// fileLineTable is a lineTable returning the file for the pc.
+type fileLineTable map[uint64]string
+
+func (t fileLineTable) PCToLine(pc uint64) (string, int, *gosym.Func) {
+	return t[pc], 1, nil
+}
+
+func TestIsMethodWrapper(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"Load-fm":    true,
+		"String·fm":  true,
+		"Load":       false,
+		"main":       false,
+		"func1":      false,
+		"deferwrap1": false,
+	} {
+		fn := &Function{Name: name}
+		assert.Equal(t, expected, fn.IsMethodWrapper(), name)
+	}
+
+	// Wrappers are attributed to the autogenerated source file.
+	lines := fileLineTable{0x1000: "<autogenerated>", 0x2000: "/src/main.go"}
+	assert.True(t, (&Function{Name: "Value", Offset: 0x1000, lines: lines}).IsMethodWrapper())
+	assert.False(t, (&Function{Name: "Value", Offset: 0x2000, lines: lines}).IsMethodWrapper())
+}
+
+func TestFunctionData(t *testing.T) {
+	code := []byte{0x90, 0x90, 0xc3, 0xcc}
+	f := &GoFile{fh: &mockFileHandler{
+		mGetSectionDataFromAddress: func(uint64) (uint64, []byte, error) {
+			return 0x1000, code, nil
+		},
+	}}
+
+	buf, err := f.FunctionData(&Function{Name: "main", Offset: 0x1000, End: 0x1003})
+	assert.NoError(t, err)
+	assert.Equal(t, code[:3], buf)
+
+	_, err = f.FunctionData(&Function{Name: "stub", Offset: 0x1000})
+	assert.Error(t, err, "no end")
+	_, err = f.FunctionData(&Function{Name: "stub", Offset: 0x1003, End: 0x1000})
+	assert.Error(t, err, "end before offset")
+	_, err = f.FunctionData(&Function{Name: "main", Offset: 0x1000, End: 0x1010})
+	assert.Error(t, err, "outside the section")
+}
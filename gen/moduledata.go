@@ -326,11 +326,25 @@ func (g *moduleDataGenerator) writeVersionedModuleData(versionCode int, code str
 			g.writeln("NoPtrBssLen: %s,", g.wrapValue("md.Enoptrbss - md.Noptrbss", bits))
 		}
 
+		if exist("gcdata", "gcbss") {
+			g.writeln("GCDataAddr: %s,", g.wrapValue("md.Gcdata", bits))
+			g.writeln("GCBssAddr: %s,", g.wrapValue("md.Gcbss", bits))
+		}
+
 		if exist("types", "etypes") {
 			g.writeln("TypesAddr: %s,", g.wrapValue("md.Types", bits))
 			g.writeln("TypesLen: %s,", g.wrapValue("md.Etypes - md.Types", bits))
 		}
 
+		if exist("rodata") {
+			g.writeln("RodataAddr: %s,", g.wrapValue("md.Rodata", bits))
+		}
+
+		if exist("textsectmap") {
+			g.writeln("TextSectMapAddr: %s,", g.wrapValue("md.Textsectmap", bits))
+			g.writeln("TextSectMapLen: %s,", g.wrapValue("md.Textsectmaplen", bits))
+		}
+
 		if exist("typelinks") {
 			g.writeln("TypelinkAddr: %s,", g.wrapValue("md.Typelinks", bits))
 			g.writeln("TypelinkLen: %s,", g.wrapValue("md.Typelinkslen", bits))
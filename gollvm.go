@@ -0,0 +1,105 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"debug/elf"
+	"errors"
+	"strings"
+)
+
+// gofrontendSymbols are the symbols of the runtime used by the compilers
+// built on the gofrontend, gccgo and gollvm. The runtime is provided by
+// libgo instead of the runtime package compiled by the gc toolchain.
+var gofrontendSymbols = []string{
+	"__go_init_main",
+	"__go_go",
+	"__go_runtime_error",
+}
+
+// IsGollvm returns true if the binary was compiled with gollvm, the Go
+// compiler using LLVM as its backend. Binaries compiled with gollvm don't
+// have the metadata emitted by the gc toolchain, for example the moduledata
+// and the pclntab, so most of the analysis is not supported. The binary is
+// detected by the symbols of the gofrontend's runtime together with a
+// reference to the LLVM build of the runtime library or an LLVM compiler
+// identification in the .comment section. Since gollvm only targets Linux,
+// false is returned for all other file formats.
+func (f *GoFile) IsGollvm() (bool, error) {
+	e, ok := f.fh.(*elfFile)
+	if !ok {
+		return false, nil
+	}
+
+	symbols := make(map[string]bool)
+	symm, err := e.getsymtab()
+	if err != nil && !errors.Is(err, ErrSymbolNotFound) {
+		return false, err
+	}
+	for name := range symm {
+		symbols[name] = true
+	}
+	// Stripped binaries still import the runtime from libgo.
+	dyn, err := e.file.DynamicSymbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return false, err
+	}
+	for _, s := range dyn {
+		symbols[s.Name] = true
+	}
+
+	libs, err := e.file.ImportedLibraries()
+	if err != nil {
+		return false, err
+	}
+	var comment []byte
+	if s := e.file.Section(".comment"); s != nil {
+		comment, _ = e.sectionData(s)
+	}
+	return isGollvm(symbols, libs, comment), nil
+}
+
+func isGollvm(symbols map[string]bool, libs []string, comment []byte) bool {
+	frontend := false
+	for _, name := range gofrontendSymbols {
+		if symbols[name] {
+			frontend = true
+			break
+		}
+	}
+	if !frontend {
+		return false
+	}
+
+	// The runtime library is named libgo-llvm.so by gollvm, while gccgo uses
+	// libgo.so.
+	for _, lib := range libs {
+		if strings.HasPrefix(lib, "libgo-llvm.") {
+			return true
+		}
+	}
+	// The runtime may be linked statically, in which case the compiler is
+	// identified by the .comment section.
+	for _, ident := range bytes.Split(comment, []byte{0}) {
+		if bytes.Contains(ident, []byte("clang version")) || bytes.Contains(ident, []byte("gollvm")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGollvm(t *testing.T) {
+	libgo := map[string]bool{"__go_init_main": true, "main.main": true}
+	gc := map[string]bool{"runtime.main": true, "main.main": true}
+
+	for _, test := range []struct {
+		name     string
+		symbols  map[string]bool
+		libs     []string
+		comment  string
+		expected bool
+	}{
+		{"dynamic runtime", libgo, []string{"libgo-llvm.so.16", "libc.so.6"}, "GCC: (GNU) 12.2.0\x00", true},
+		{"static runtime", libgo, []string{"libc.so.6"}, "GCC: (GNU) 12.2.0\x00clang version 17.0.0\x00", true},
+		{"gccgo", libgo, []string{"libgo.so.21", "libc.so.6"}, "GCC: (GNU) 12.2.0\x00", false},
+		{"gc", gc, []string{"libc.so.6"}, "GCC: (GNU) 12.2.0\x00clang version 17.0.0\x00", false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isGollvm(test.symbols, test.libs, []byte(test.comment)))
+		})
+	}
+}
+
+func TestIsGollvmNotELF(t *testing.T) {
+	f := &GoFile{fh: &mockFileHandler{}}
+	gollvm, err := f.IsGollvm()
+	assert.NoError(t, err)
+	assert.False(t, gollvm)
+}
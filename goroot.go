@@ -371,3 +371,51 @@ func findGoRootPath(f *GoFile) (string, error) {
 
 	return "", ErrNoGoRootFound
 }
+
+func findGoPath(f *GoFile) (string, error) {
+	var pkgs []*Package
+	pkgs = append(pkgs, f.pkgs...)
+	pkgs = append(pkgs, f.vendors...)
+	pkgs = append(pkgs, f.unknown...)
+	return goPathFromPackages(pkgs)
+}
+
+// goPathFromPackages determines the GOPATH from the file paths of the given
+// packages. In GOPATH mode, a package's source is located at
+// "$GOPATH/src/<import path>" so the GOPATH can be recovered by removing this
+// suffix. If the packages point to different roots, the most common is returned.
+func goPathFromPackages(pkgs []*Package) (string, error) {
+	candidates := make(map[string]int)
+	for _, p := range pkgs {
+		if p.Name == "" || p.Name == "main" {
+			continue
+		}
+		subpath := fmt.Sprintf("/src/%s", p.Name)
+		if strings.HasSuffix(p.Filepath, subpath) {
+			candidates[strings.TrimSuffix(p.Filepath, subpath)]++
+			continue
+		}
+		subpathWin := fmt.Sprintf("\\src\\%s", strings.ReplaceAll(p.Name, "/", "\\"))
+		if strings.HasSuffix(p.Filepath, subpathWin) {
+			candidates[strings.TrimSuffix(p.Filepath, subpathWin)]++
+		}
+	}
+
+	var gopath string
+	var count int
+	for k, v := range candidates {
+		if k == "" {
+			continue
+		}
+		// Pick the root with the most packages. On a tie, the shortest path is
+		// used to keep the result deterministic.
+		if v > count || (v == count && (len(k) < len(gopath) || (len(k) == len(gopath) && k < gopath))) {
+			gopath = k
+			count = v
+		}
+	}
+	if gopath == "" {
+		return "", ErrNoGoPathFound
+	}
+	return gopath, nil
+}
@@ -62,3 +62,58 @@ func TestExtractGoRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestGoPathFromPackages(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkgs     []*Package
+		expected string
+		err      error
+	}{
+		{
+			"gopath mode",
+			[]*Package{
+				{Name: "main", Filepath: "/home/user/go/src/github.com/goretk/app"},
+				{Name: "github.com/goretk/app/lib", Filepath: "/home/user/go/src/github.com/goretk/app/lib"},
+				{Name: "github.com/pkg/errors", Filepath: "/home/user/go/src/github.com/pkg/errors"},
+			},
+			"/home/user/go",
+			nil,
+		},
+		{
+			"windows paths",
+			[]*Package{
+				{Name: "github.com/pkg/errors", Filepath: "C:/Users/user/go/src/github.com/pkg/errors"},
+			},
+			"C:/Users/user/go",
+			nil,
+		},
+		{
+			"most common root",
+			[]*Package{
+				{Name: "github.com/a/b", Filepath: "/opt/other/src/github.com/a/b"},
+				{Name: "github.com/c/d", Filepath: "/home/user/go/src/github.com/c/d"},
+				{Name: "github.com/e/f", Filepath: "/home/user/go/src/github.com/e/f"},
+			},
+			"/home/user/go",
+			nil,
+		},
+		{
+			"module mode",
+			[]*Package{
+				{Name: "github.com/pkg/errors", Filepath: "/home/user/go/pkg/mod/github.com/pkg/errors@v0.9.1"},
+			},
+			"",
+			ErrNoGoPathFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+			gopath, err := goPathFromPackages(test.pkgs)
+			r.Equal(test.err, err)
+			r.Equal(test.expected, gopath)
+		})
+	}
+}
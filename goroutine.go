@@ -0,0 +1,158 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// GoroutineSite is a location in the code where a new goroutine is launched.
+type GoroutineSite struct {
+	// Caller is the function with the go statement.
+	Caller *Function
+	// PC is the address of the call to runtime.newproc.
+	PC uint64
+	// Target is the function launched as a goroutine. It is nil if the
+	// function could not be resolved statically.
+	Target *Function
+}
+
+// GetGoroutineLaunchSites returns all the locations where a goroutine is
+// launched. A go statement is compiled to a call to runtime.newproc with a
+// pointer to the function value that should be executed by the goroutine.
+// The function value is resolved by looking at the addresses referenced by
+// the code leading up to the call. For go statements with arguments, newer
+// compilers launch a wrapper function that calls the actual function.
+// Only x86 (i386 and amd64) and arm64 binaries are supported. For other
+// architectures, ErrArchNotSupported is returned.
+func (f *GoFile) GetGoroutineLaunchSites() ([]GoroutineSite, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	newproc := f.pclntab.LookupFunc("runtime.newproc")
+	if newproc == nil {
+		return nil, fmt.Errorf("runtime.newproc not found")
+	}
+
+	fcns := f.functionsByEntry()
+
+	var sites []GoroutineSite
+	for _, fn := range fcns {
+		refs, err := f.functionRefs(fn)
+		if errors.Is(err, ErrArchNotSupported) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+
+		// The references made since the last call. The function value passed
+		// to runtime.newproc is among these.
+		var pending []codeRef
+		for _, ref := range refs {
+			if ref.kind != refCall {
+				pending = append(pending, ref)
+				continue
+			}
+			if ref.target != newproc.Entry {
+				pending = pending[:0]
+				continue
+			}
+
+			site := GoroutineSite{Caller: fn, PC: ref.pc}
+			for i := len(pending) - 1; i >= 0; i-- {
+				if target := f.resolveFuncValue(pending[i].target, fcns); target != nil {
+					site.Target = target
+					break
+				}
+			}
+			if site.Target == nil {
+				// The compiler may have reordered the blocks so the function
+				// value is not set up directly before the call. Fall back to
+				// the references made by the code for the same go statement.
+				site.Target = f.resolveFuncValueFromLine(ref.pc, refs, fcns)
+			}
+			sites = append(sites, site)
+			pending = pending[:0]
+		}
+	}
+
+	sortGoroutineSites(sites)
+	return sites, nil
+}
+
+// resolveFuncValue returns the function referenced by the address. The address
+// can either be the entry of the function or a function value that points to
+// the function's entry.
+func (f *GoFile) resolveFuncValue(addr uint64, fcns map[uint64]*Function) *Function {
+	if fn, ok := fcns[addr]; ok {
+		return fn
+	}
+	buf, err := f.Bytes(addr, uint64(f.FileInfo.WordSize))
+	if err != nil {
+		return nil
+	}
+	ptr, err := readUIntTo64(bytes.NewReader(buf), f.FileInfo.ByteOrder, f.FileInfo.WordSize == intSize32)
+	if err != nil {
+		return nil
+	}
+	return fcns[ptr]
+}
+
+// resolveFuncValueFromLine resolves the function value from the references
+// made by instructions with the same source line as the call at the pc. The
+// references closest to the call are tried first.
+func (f *GoFile) resolveFuncValueFromLine(pc uint64, refs []codeRef, fcns map[uint64]*Function) *Function {
+	file, line, _ := f.pclntab.PCToLine(pc)
+	var candidates []codeRef
+	for _, ref := range refs {
+		if ref.kind == refCall {
+			continue
+		}
+		if fl, l, _ := f.pclntab.PCToLine(ref.pc); fl == file && l == line {
+			candidates = append(candidates, ref)
+		}
+	}
+	distance := func(r codeRef) uint64 {
+		if r.pc > pc {
+			return r.pc - pc
+		}
+		return pc - r.pc
+	}
+	slices.SortStableFunc(candidates, func(a, b codeRef) int {
+		return cmp.Compare(distance(a), distance(b))
+	})
+	for _, ref := range candidates {
+		if target := f.resolveFuncValue(ref.target, fcns); target != nil {
+			return target
+		}
+	}
+	return nil
+}
+
+func sortGoroutineSites(sites []GoroutineSite) {
+	slices.SortFunc(sites, func(a, b GoroutineSite) int {
+		return cmp.Compare(a.PC, b.PC)
+	})
+}
@@ -0,0 +1,252 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GoString is a string found in the binary.
+type GoString struct {
+	// Value is the content of the string.
+	Value string
+	// Address is the virtual address of the string's data.
+	Address uint64
+	// Length is the length of the string in bytes.
+	Length uint64
+}
+
+// readOnlyRegion is a loaded read-only part of the binary.
+type readOnlyRegion struct {
+	addr uint64
+	data []byte
+}
+
+// GetStrings returns the strings that are referenced by the string headers in
+// the data sections of the binary. The Go compiler stores the data of string
+// literals in the read-only data and refers to it with a pointer and a length.
+// The data and noptrdata sections from the moduledata and the read-only data
+// are searched for pairs of pointer and length that point to printable UTF-8
+// text in the read-only data. This finds the strings held by package-level
+// variables and static composite literals. Strings that are only referenced
+// by the code are not found since the pointer and length are encoded in the
+// instructions. The strings are returned sorted by their address.
+func (f *GoFile) GetStrings() ([]GoString, error) {
+	err := f.initModuleData()
+	if err != nil {
+		return nil, err
+	}
+	rodata, err := f.readOnlyRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	var regions [][]byte
+	for _, s := range []ModuleDataSection{f.moduledata.NoPtrData(), f.moduledata.Data()} {
+		if s.Length == 0 {
+			continue
+		}
+		// The end of the section may not be stored in the file if it's
+		// zeroed, for example in WebAssembly modules, so only the stored
+		// part is searched.
+		base, data, err := f.fh.getSectionDataFromAddress(s.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the data at 0x%x: %w", s.Address, err)
+		}
+		start := s.Address - base
+		end := min(start+s.Length, uint64(len(data)))
+		regions = append(regions, data[start:end])
+	}
+	for _, r := range rodata {
+		regions = append(regions, r.data)
+	}
+
+	wordSize := f.FileInfo.WordSize
+	order := f.FileInfo.ByteOrder
+	readWord := func(b []byte) uint64 {
+		if wordSize == intSize32 {
+			return uint64(order.Uint32(b))
+		}
+		return order.Uint64(b)
+	}
+
+	type strKey struct {
+		addr, length uint64
+	}
+	seen := make(map[strKey]bool)
+	var strs []GoString
+	for _, data := range regions {
+		for off := 0; off+2*wordSize <= len(data); off += wordSize {
+			ptr := readWord(data[off:])
+			length := readWord(data[off+wordSize:])
+			if ptr == 0 || length == 0 || seen[strKey{ptr, length}] {
+				continue
+			}
+			b, ok := regionBytes(rodata, ptr, length)
+			if !ok || !isPrintableString(b) {
+				continue
+			}
+			seen[strKey{ptr, length}] = true
+			strs = append(strs, GoString{Value: string(b), Address: ptr, Length: length})
+		}
+	}
+	slices.SortFunc(strs, func(a, b GoString) int {
+		if c := cmp.Compare(a.Address, b.Address); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Length, b.Length)
+	})
+	return strs, nil
+}
+
+// readOnlyRegions returns the read-only data of the binary. The types range
+// and the section holding the start of the read-only data are taken from the
+// moduledata. If the moduledata doesn't have them, the loaded sections that
+// are neither writable nor executable are used. For WebAssembly modules, the
+// linear memory before the data sections is returned since the module
+// doesn't have any loaded sections.
+func (f *GoFile) readOnlyRegions() ([]readOnlyRegion, error) {
+	if _, ok := f.fh.(*wasmFile); ok {
+		_, mem, err := f.fh.getSectionData(wasmMemorySection)
+		if err != nil {
+			return nil, err
+		}
+		end := min(f.moduledata.NoPtrData().Address, f.moduledata.Data().Address, uint64(len(mem)))
+		return []readOnlyRegion{{addr: 0, data: mem[:end]}}, nil
+	}
+
+	var regions []readOnlyRegion
+	add := func(addr, length uint64) {
+		base, data, err := f.fh.getSectionDataFromAddress(addr)
+		if err != nil || addr-base >= uint64(len(data)) {
+			// The data is not stored in the file.
+			return
+		}
+		data = data[addr-base:]
+		if length != 0 {
+			data = data[:min(length, uint64(len(data)))]
+		}
+		for _, r := range regions {
+			if r.addr == addr {
+				return
+			}
+		}
+		regions = append(regions, readOnlyRegion{addr: addr, data: data})
+	}
+	if types := f.moduledata.Types(); types.Length != 0 {
+		add(types.Address, types.Length)
+	}
+	if f.moduledata.RodataAddr != 0 {
+		// The end of the read-only data is not in the moduledata, so the
+		// rest of the section is used.
+		add(f.moduledata.RodataAddr, 0)
+	}
+	if len(regions) != 0 {
+		return regions, nil
+	}
+
+	sects, err := f.Sections()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sects {
+		if s.VirtualAddress == 0 || s.Size == 0 || s.Permissions&SectionRead == 0 ||
+			s.Permissions&(SectionWrite|SectionExecute) != 0 {
+			continue
+		}
+		base, data, err := f.fh.getSectionDataFromAddress(s.VirtualAddress)
+		if err != nil || base != s.VirtualAddress {
+			// The section is not stored in the file.
+			continue
+		}
+		regions = append(regions, readOnlyRegion{addr: base, data: data})
+	}
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("no read-only data found: %w", ErrSectionDoesNotExist)
+	}
+	return regions, nil
+}
+
+// regionBytes returns the bytes in the region holding the address range.
+func regionBytes(regions []readOnlyRegion, addr, length uint64) ([]byte, bool) {
+	for _, r := range regions {
+		if addr < r.addr || addr-r.addr >= uint64(len(r.data)) {
+			continue
+		}
+		off := addr - r.addr
+		if length > uint64(len(r.data))-off {
+			return nil, false
+		}
+		return r.data[off : off+length], true
+	}
+	return nil, false
+}
+
+// isPrintableString returns true if the data is valid UTF-8 text that only
+// has printable characters and white space.
+func isPrintableString(b []byte) bool {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size <= 1 {
+			return false
+		}
+		if !unicode.IsPrint(r) && r != '\n' && r != '\t' && r != '\r' {
+			return false
+		}
+		b = b[size:]
+	}
+	return true
+}
+
+// GetGlobalString returns the value of the package-level string variable
+// with the given symbol name, for example "main.version". The string header
+// of the variable is read from the data sections and the bytes it points to
+// are returned. Only the value the variable is initialized with at compile
+// time can be read. Variables without a compile-time value are stored in
+// the bss sections and are returned as an empty string. The binary must have
+// a symbol table.
+func (f *GoFile) GetGlobalString(symbolName string) (string, error) {
+	err := f.initModuleData()
+	if err != nil {
+		return "", err
+	}
+	sym, err := f.GetSymbol(symbolName)
+	if err != nil {
+		return "", fmt.Errorf("error when looking up %s: %w", symbolName, err)
+	}
+	ptr, err := f.readVarPointer(sym.Value)
+	if err != nil {
+		return "", fmt.Errorf("error when reading the data pointer of %s: %w", symbolName, err)
+	}
+	length, err := f.readVarPointer(sym.Value + uint64(f.FileInfo.WordSize))
+	if err != nil {
+		return "", fmt.Errorf("error when reading the length of %s: %w", symbolName, err)
+	}
+	if ptr == 0 || length == 0 {
+		return "", nil
+	}
+	b, err := f.Bytes(ptr, length)
+	if err != nil {
+		return "", fmt.Errorf("error when reading the data of %s: %w", symbolName, err)
+	}
+	return string(b), nil
+}
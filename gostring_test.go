@@ -0,0 +1,176 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStrings(t *testing.T) {
+	rodata := []byte("hello\x00\x01\x02gopher")
+	data := make([]byte, 0x40)
+	// A string header for "hello" and one for "gopher".
+	binary.LittleEndian.PutUint64(data[0x00:], 0x402000)
+	binary.LittleEndian.PutUint64(data[0x08:], 5)
+	binary.LittleEndian.PutUint64(data[0x10:], 0x402008)
+	binary.LittleEndian.PutUint64(data[0x18:], 6)
+	// The same string is only returned once.
+	binary.LittleEndian.PutUint64(data[0x20:], 0x402000)
+	binary.LittleEndian.PutUint64(data[0x28:], 5)
+	// The data is not printable.
+	binary.LittleEndian.PutUint64(data[0x30:], 0x402005)
+	binary.LittleEndian.PutUint64(data[0x38:], 3)
+
+	fh := &mockFileHandler{
+		mGetSectionDataFromAddress: func(addr uint64) (uint64, []byte, error) {
+			if addr >= 0x403000 {
+				return 0x403000, data, nil
+			}
+			return 0x402000, rodata, nil
+		},
+		mGetSections: func() ([]Section, error) {
+			return []Section{
+				{Name: ".rodata", VirtualAddress: 0x402000, Size: uint64(len(rodata)), Permissions: SectionRead},
+				{Name: ".noptrdata", VirtualAddress: 0x403000, Size: uint64(len(data)), Permissions: SectionRead | SectionWrite},
+			}, nil
+		},
+	}
+	f := &GoFile{
+		fh:         fh,
+		FileInfo:   &FileInfo{WordSize: intSize64, ByteOrder: binary.LittleEndian},
+		moduledata: moduledata{NoPtrDataAddr: 0x403000, NoPtrDataLen: uint64(len(data)), fh: fh},
+	}
+	// The moduledata is already set.
+	f.initModuleDataOnce.Do(func() {})
+
+	strs, err := f.GetStrings()
+	require.NoError(t, err)
+	assert.Equal(t, []GoString{
+		{Value: "hello", Address: 0x402000, Length: 5},
+		{Value: "gopher", Address: 0x402008, Length: 6},
+	}, strs)
+}
+
+func TestGetStringsSkipsText(t *testing.T) {
+	text := []byte("codecode")
+	rodata := []byte("typesgopher")
+	data := make([]byte, 0x20)
+	// A string header pointing to the text and one to the read-only data.
+	binary.LittleEndian.PutUint64(data[0x00:], 0x401000)
+	binary.LittleEndian.PutUint64(data[0x08:], 4)
+	binary.LittleEndian.PutUint64(data[0x10:], 0x402005)
+	binary.LittleEndian.PutUint64(data[0x18:], 6)
+
+	sects := []Section{
+		{Name: ".text", VirtualAddress: 0x401000, Size: uint64(len(text)), Permissions: SectionRead | SectionExecute},
+		{Name: ".rodata", VirtualAddress: 0x402000, Size: uint64(len(rodata)), Permissions: SectionRead},
+		{Name: ".noptrdata", VirtualAddress: 0x403000, Size: uint64(len(data)), Permissions: SectionRead | SectionWrite},
+	}
+	fh := &mockFileHandler{
+		mGetSectionDataFromAddress: func(addr uint64) (uint64, []byte, error) {
+			switch {
+			case addr >= 0x403000:
+				return 0x403000, data, nil
+			case addr >= 0x402000:
+				return 0x402000, rodata, nil
+			}
+			return 0x401000, text, nil
+		},
+		mGetSections: func() ([]Section, error) { return sects, nil },
+	}
+
+	tests := []struct {
+		name string
+		md   moduledata
+	}{
+		{"moduledata", moduledata{TypesAddr: 0x402000, TypesLen: 5, RodataAddr: 0x402005}},
+		{"sections", moduledata{}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			md := test.md
+			md.NoPtrDataAddr, md.NoPtrDataLen, md.fh = 0x403000, uint64(len(data)), fh
+			f := &GoFile{
+				fh:         fh,
+				FileInfo:   &FileInfo{WordSize: intSize64, ByteOrder: binary.LittleEndian},
+				moduledata: md,
+			}
+			// The moduledata is already set.
+			f.initModuleDataOnce.Do(func() {})
+
+			strs, err := f.GetStrings()
+			require.NoError(t, err)
+			assert.Equal(t, []GoString{{Value: "gopher", Address: 0x402005, Length: 6}}, strs)
+		})
+	}
+}
+
+func TestGetGlobalString(t *testing.T) {
+	rodata := []byte("v1.2.3")
+	data := make([]byte, 0x10)
+	binary.BigEndian.PutUint32(data[0x00:], 0x2000)
+	binary.BigEndian.PutUint32(data[0x04:], 6)
+
+	fh := &mockFileHandler{
+		mGetSectionDataFromAddress: func(addr uint64) (uint64, []byte, error) {
+			if addr >= 0x3000 {
+				return 0x3000, data, nil
+			}
+			return 0x2000, rodata, nil
+		},
+		mGetSymbol: func(name string) (Symbol, error) {
+			switch name {
+			case "main.version":
+				return Symbol{Name: name, Value: 0x3000, Size: 8}, nil
+			case "main.commit":
+				return Symbol{Name: name, Value: 0x4000, Size: 8}, nil
+			}
+			return Symbol{}, ErrSymbolNotFound
+		},
+	}
+	f := &GoFile{
+		fh:         fh,
+		FileInfo:   &FileInfo{WordSize: intSize32, ByteOrder: binary.BigEndian},
+		moduledata: moduledata{BssAddr: 0x4000, BssLen: 0x100, fh: fh},
+	}
+	// The moduledata is already set.
+	f.initModuleDataOnce.Do(func() {})
+
+	s, err := f.GetGlobalString("main.version")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", s)
+
+	s, err = f.GetGlobalString("main.commit")
+	require.NoError(t, err, "variable in bss")
+	assert.Empty(t, s)
+
+	_, err = f.GetGlobalString("main.missing")
+	assert.ErrorIs(t, err, ErrSymbolNotFound)
+}
+
+func TestIsPrintableString(t *testing.T) {
+	assert.True(t, isPrintableString([]byte("hello, world\n")))
+	assert.True(t, isPrintableString([]byte("héllo 世界")))
+	assert.False(t, isPrintableString([]byte("abc\x00")))
+	assert.False(t, isPrintableString([]byte{0xff, 0xfe}))
+}
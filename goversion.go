@@ -21,8 +21,8 @@ package gore
 
 import (
 	"bytes"
-	"errors"
 	"regexp"
+	"sort"
 
 	"golang.org/x/arch/x86/x86asm"
 
@@ -35,11 +35,11 @@ var goVersionMatcher = regexp.MustCompile(`(go[\d+.]*(beta|rc)?[\d*])`)
 // GoVersion holds information about the compiler version.
 type GoVersion struct {
 	// Name is a string representation of the version.
-	Name string
+	Name string `json:"name"`
 	// SHA is a digest of the git commit for the release.
-	SHA string
+	SHA string `json:"sha"`
 	// Timestamp is a string of the timestamp when the commit was created.
-	Timestamp string
+	Timestamp string `json:"timestamp"`
 }
 
 // ResolveGoVersion tries to return the GoVersion for the given tag.
@@ -82,11 +82,7 @@ func findGoCompilerVersion(f *GoFile) (*GoVersion, error) {
 	// If no version was found, search the sections for the
 	// version string.
 
-	data, err := f.fh.getRData()
-	// If a read-only data section does not exist, try text.
-	if errors.Is(err, ErrSectionDoesNotExist) {
-		_, data, err = f.fh.getCodeSection()
-	}
+	data, err := f.getRData()
 	if err != nil {
 		return nil, err
 	}
@@ -257,6 +253,48 @@ disasm:
 	return nil
 }
 
+// DetectVersionConflicts returns all the distinct Go versions found in the
+// binary. Normally, a binary only has one version. More than one version can
+// be found if code compiled by different compilers has been linked together,
+// for example when combining c-archives, or if the binary has been tampered
+// with. The read-only data section is scanned for version strings and the
+// version in the build information is included. Only known versions are
+// taken from the data section to avoid false positives. The versions are sorted from oldest
+// to newest.
+func (f *GoFile) DetectVersionConflicts() ([]string, error) {
+	data, err := f.getRData()
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]struct{})
+	for _, m := range goVersionMatcher.FindAll(data, -1) {
+		ver := ResolveGoVersion(string(m))
+		// Go before 1.4 does not have the version string, so these are false positives.
+		if ver == nil || GoVersionCompare(ver.Name, "go1.4beta1") < 0 {
+			continue
+		}
+		found[ver.Name] = struct{}{}
+	}
+	if f.BuildInfo != nil {
+		if f.BuildInfo.Compiler != nil {
+			found[f.BuildInfo.Compiler.Name] = struct{}{}
+		} else if f.BuildInfo.ModInfo != nil && f.BuildInfo.ModInfo.GoVersion != "" {
+			// A version newer than the versions known by the library.
+			found[f.BuildInfo.ModInfo.GoVersion] = struct{}{}
+		}
+	}
+
+	versions := make([]string, 0, len(found))
+	for v := range found {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return GoVersionCompare(versions[i], versions[j]) < 0
+	})
+	return versions, nil
+}
+
 func matchGoVersionString(data []byte) string {
 	return string(goVersionMatcher.Find(data))
 }
@@ -180,3 +180,16 @@ func TestExtractVersionFromInitSched(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectVersionConflicts(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []byte("\x00go1.16.5runtime error\x00go1.20.3\x00go1.2\x00go1.16.5")
+	f := &GoFile{fh: &mockFileHandler{mGetRData: func() ([]byte, error) {
+		return data, nil
+	}}}
+
+	versions, err := f.DetectVersionConflicts()
+	assert.NoError(err)
+	assert.Equal([]string{"go1.16.5", "go1.20.3"}, versions)
+}
@@ -0,0 +1,63 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"cmp"
+	"slices"
+)
+
+// DynamicImport is a symbol resolved by the dynamic loader when the binary
+// is loaded. The Go linker records these imports for the symbols declared
+// with the "//go:cgo_import_dynamic" directive, for example by the runtime
+// and the syscall package on Windows and macOS, and for the symbols used by
+// cgo code. WebAssembly modules import the functions declared with
+// "//go:wasmimport" from the host.
+type DynamicImport struct {
+	// Name is the name of the symbol. On macOS, the C symbols have a leading
+	// underscore.
+	Name string `json:"name"`
+	// Library is the shared library, DLL or WebAssembly module the symbol is
+	// imported from. It's empty if the library is not recorded, for example
+	// for unversioned symbols in ELF files and for symbols looked up in the
+	// flat namespace in Mach-O files.
+	Library string `json:"library,omitempty"`
+	// Version is the symbol version required from the library. It's only
+	// recorded for ELF files.
+	Version string `json:"version,omitempty"`
+}
+
+// GetDynamicImports returns the symbols the binary imports from shared
+// libraries, sorted by the library and the name. The imports are read from
+// the import directory for PE files, the undefined dynamic symbols for ELF
+// files, the undefined symbols for Mach-O files and the import section for
+// WebAssembly modules. This shows the native functions the binary resolves
+// at load time. Statically linked binaries don't have any imports.
+func (f *GoFile) GetDynamicImports() ([]DynamicImport, error) {
+	imports, err := f.fh.getDynamicImports()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(imports, func(a, b DynamicImport) int {
+		if c := cmp.Compare(a.Library, b.Library); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return imports, nil
+}
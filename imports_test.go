@@ -0,0 +1,46 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDynamicImports(t *testing.T) {
+	f := &GoFile{fh: &mockFileHandler{
+		mGetDynamicImports: func() ([]DynamicImport, error) {
+			return []DynamicImport{
+				{Name: "write", Library: "libc.so.6", Version: "GLIBC_2.2.5"},
+				{Name: "WriteFile", Library: "kernel32.dll"},
+				{Name: "abort", Library: "libc.so.6", Version: "GLIBC_2.2.5"},
+				{Name: "getpid"},
+			}, nil
+		},
+	}}
+
+	imports, err := f.GetDynamicImports()
+	assert.NoError(t, err)
+	assert.Equal(t, []DynamicImport{
+		{Name: "getpid"},
+		{Name: "WriteFile", Library: "kernel32.dll"},
+		{Name: "abort", Library: "libc.so.6", Version: "GLIBC_2.2.5"},
+		{Name: "write", Library: "libc.so.6", Version: "GLIBC_2.2.5"},
+	}, imports)
+}
@@ -0,0 +1,86 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import "strings"
+
+const (
+	// InstrumentationCoverage is reported for binaries built with coverage
+	// instrumentation, for example with "go build -cover".
+	InstrumentationCoverage = "coverage"
+	// InstrumentationFuzzing is reported for binaries with fuzz targets or
+	// built with the libFuzzer instrumentation.
+	InstrumentationFuzzing = "fuzzing"
+	// InstrumentationRace is reported for binaries built with the race
+	// detector.
+	InstrumentationRace = "race"
+)
+
+// instrumentationMarkers are the function name prefixes added to the binary
+// by each instrumentation.
+var instrumentationMarkers = []struct {
+	kind     string
+	prefixes []string
+}{
+	{InstrumentationCoverage, []string{"runtime/coverage.", "internal/coverage/cfile."}},
+	{InstrumentationFuzzing, []string{"testing.(*F).Fuzz", "runtime.libfuzzer"}},
+	{InstrumentationRace, []string{"runtime.racecall", "runtime.raceinit"}},
+}
+
+// DetectInstrumentation returns the instrumentation compiled into the
+// binary. The result can include InstrumentationCoverage,
+// InstrumentationFuzzing and InstrumentationRace. Instrumented binaries are
+// usually test or debug artifacts rather than production builds. The race
+// detector is detected from the build settings if available. Otherwise, the
+// functions added by the instrumentation are used.
+func (f *GoFile) DetectInstrumentation() ([]string, error) {
+	found := make(map[string]bool)
+	if f.BuildInfo != nil && f.BuildInfo.ModInfo != nil {
+		for _, s := range f.BuildInfo.ModInfo.Settings {
+			if s.Key == "-race" && s.Value == "true" {
+				found[InstrumentationRace] = true
+			}
+		}
+	}
+
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+	for _, fn := range f.pclntab.Funcs {
+		for _, m := range instrumentationMarkers {
+			if found[m.kind] {
+				continue
+			}
+			for _, p := range m.prefixes {
+				if strings.HasPrefix(fn.Name, p) {
+					found[m.kind] = true
+					break
+				}
+			}
+		}
+	}
+
+	var kinds []string
+	for _, m := range instrumentationMarkers {
+		if found[m.kind] {
+			kinds = append(kinds, m.kind)
+		}
+	}
+	return kinds, nil
+}
@@ -0,0 +1,57 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"debug/gosym"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectInstrumentation(t *testing.T) {
+	newFile := func(settings []debug.BuildSetting, funcs ...string) *GoFile {
+		tab := &gosym.Table{}
+		for _, name := range funcs {
+			tab.Funcs = append(tab.Funcs, gosym.Func{Sym: &gosym.Sym{Name: name}})
+		}
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: settings}}, pclntab: tab}
+		f.initPackagesDone = true
+		return f
+	}
+
+	tests := []struct {
+		name     string
+		file     *GoFile
+		expected []string
+	}{
+		{"none", newFile(nil, "main.main", "runtime.main"), nil},
+		{"coverage", newFile(nil, "main.main", "runtime/coverage.emitCounterData"), []string{InstrumentationCoverage}},
+		{"fuzzing", newFile(nil, "testing.(*F).Fuzz"), []string{InstrumentationFuzzing}},
+		{"race from settings", newFile([]debug.BuildSetting{{Key: "-race", Value: "true"}}), []string{InstrumentationRace}},
+		{"race and coverage", newFile(nil, "runtime.racecall", "internal/coverage/cfile.emitCounterData"), []string{InstrumentationCoverage, InstrumentationRace}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kinds, err := test.file.DetectInstrumentation()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, kinds)
+		})
+	}
+}
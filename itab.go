@@ -0,0 +1,206 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Itab is an interface table. It holds the methods of a concrete type that
+// implement an interface.
+type Itab struct {
+	// Address is the virtual address of the itab.
+	Address uint64
+	// Interface is the interface type.
+	Interface *GoType
+	// Type is the concrete type implementing the interface.
+	Type *GoType
+	// Methods holds the addresses of the concrete type's functions that
+	// implement the interface's methods. The functions are in the same
+	// order as the methods of the interface.
+	Methods []uint64
+}
+
+// GetItabs returns the interface tables in the binary. The types are
+// resolved directly from the pointers stored in the itabs instead of via the
+// list of types used by GetTypes. This means the itabs can be extracted even
+// if the linker has removed the metadata for types not used via reflection.
+// The itabs are returned sorted by their address.
+func (f *GoFile) GetItabs() ([]*Itab, error) {
+	err := f.initModuleData()
+	if err != nil {
+		return nil, err
+	}
+	if GoVersionCompare(f.FileInfo.goversion.Name, "go1.7beta1") < 0 {
+		return nil, fmt.Errorf("itabs are not supported for %s", f.FileInfo.goversion.Name)
+	}
+
+	md := f.moduledata
+	links, err := md.ITabLinks().Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the itablinks data: %w", err)
+	}
+	types, err := md.Types().Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get types data section: %w", err)
+	}
+	parser := newTypeParser(types, md.Types().Address, f.FileInfo)
+
+	wordSize := f.FileInfo.WordSize
+	is32 := wordSize == intSize32
+
+	funOff := itabFunOffset(f.FileInfo.goversion.Name, wordSize)
+
+	r := bytes.NewReader(links)
+	itabs := make([]*Itab, 0, len(links)/wordSize)
+	for i := 0; i < len(links)/wordSize; i++ {
+		addr, err := readUIntTo64(r, f.FileInfo.ByteOrder, is32)
+		if err != nil {
+			return nil, err
+		}
+
+		hdr, err := f.Bytes(addr, funOff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read itab at 0x%x: %w", addr, err)
+		}
+		hr := bytes.NewReader(hdr)
+		interAddr, err := readUIntTo64(hr, f.FileInfo.ByteOrder, is32)
+		if err != nil {
+			return nil, err
+		}
+		typAddr, err := readUIntTo64(hr, f.FileInfo.ByteOrder, is32)
+		if err != nil {
+			return nil, err
+		}
+
+		inter, err := parser.parseType(interAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the interface type for itab at 0x%x: %w", addr, err)
+		}
+		typ, err := parser.parseType(typAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the concrete type for itab at 0x%x: %w", addr, err)
+		}
+
+		itab := &Itab{Address: addr, Interface: inter, Type: typ}
+		if n := uint64(len(inter.Methods)); n > 0 {
+			fun, err := f.Bytes(addr+funOff, n*uint64(wordSize))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the methods of itab at 0x%x: %w", addr, err)
+			}
+			fr := bytes.NewReader(fun)
+			itab.Methods = make([]uint64, n)
+			for j := range itab.Methods {
+				itab.Methods[j], err = readUIntTo64(fr, f.FileInfo.ByteOrder, is32)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		itabs = append(itabs, itab)
+	}
+
+	slices.SortFunc(itabs, func(a, b *Itab) int {
+		return cmp.Compare(a.Address, b.Address)
+	})
+	return itabs, nil
+}
+
+// itabFunOffset returns the offset of the functions in an itab. The
+// functions are located after the interface and type pointers and the type
+// hash. Before Go 1.10, the itab also had a link to the next itab. Up to Go
+// 1.22, the hash was followed by 4 bytes of padding. Since Go 1.23, the itab
+// is an internal/abi.ITab without the padding, so the functions follow the
+// hash at the next word boundary.
+func itabFunOffset(version string, wordSize int) uint64 {
+	switch {
+	case GoVersionCompare(version, "go1.10beta1") < 0:
+		return uint64(3*wordSize + 8)
+	case GoVersionCompare(version, "go1.23rc1") < 0:
+		return uint64(2*wordSize + 8)
+	}
+	return uint64((2*wordSize + 4 + wordSize - 1) / wordSize * wordSize)
+}
+
+// itabKey identifies an itab by the addresses of its interface and concrete
+// type.
+type itabKey struct {
+	inter, typ uint64
+}
+
+// FindItab returns the itab for the interface and concrete type pair. The
+// runtime's itab hash table is only filled at run time so the lookup uses an
+// index built from the itabs returned by GetItabs the first time it's called.
+// The types are matched by their address. ErrItabNotFound is returned if the
+// binary doesn't have an itab for the pair, which is the case if the compiler
+// never converts the type to the interface statically.
+func (f *GoFile) FindItab(ifaceType, concreteType *GoType) (*Itab, error) {
+	if err := f.initItabIndex(); err != nil {
+		return nil, err
+	}
+	if ifaceType == nil || concreteType == nil {
+		return nil, ErrItabNotFound
+	}
+	itab, ok := f.itabIndex[itabKey{ifaceType.Addr, concreteType.Addr}]
+	if !ok {
+		return nil, ErrItabNotFound
+	}
+	return itab, nil
+}
+
+// initItabIndex builds the index of the itabs by their interface and
+// concrete type.
+func (f *GoFile) initItabIndex() error {
+	f.itabIndexOnce.Do(func() {
+		itabs, err := f.GetItabs()
+		if err != nil {
+			f.itabIndexError = err
+			return
+		}
+		f.itabIndex = make(map[itabKey]*Itab, len(itabs))
+		for _, itab := range itabs {
+			f.itabIndex[itabKey{itab.Interface.Addr, itab.Type.Addr}] = itab
+		}
+	})
+	return f.itabIndexError
+}
+
+// resolveImplements sets the interfaces implemented by the concrete types
+// from the itabs. The interfaces are taken from the types if present so the
+// type graph is shared.
+func (f *GoFile) resolveImplements(types map[uint64]*GoType) {
+	for key, itab := range f.itabIndex {
+		typ, ok := types[key.typ]
+		if !ok {
+			continue
+		}
+		inter, ok := types[key.inter]
+		if !ok {
+			inter = itab.Interface
+		}
+		typ.Implements = append(typ.Implements, inter)
+	}
+	for _, typ := range types {
+		slices.SortFunc(typ.Implements, func(a, b *GoType) int {
+			return cmp.Compare(a.Addr, b.Addr)
+		})
+	}
+}
@@ -19,26 +19,28 @@ package gore
 
 import (
 	"bytes"
-	"cmp"
 	"compress/zlib"
 	"debug/dwarf"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
-	"slices"
+	"math"
+	"path"
 	"strings"
 	"sync"
 
 	"github.com/blacktop/go-macho"
+	"github.com/blacktop/go-macho/pkg/trie"
 	"github.com/blacktop/go-macho/types"
 )
 
-func openMachO(r io.ReaderAt) (*machoFile, error) {
+func openMachO(r io.ReaderAt, opts Options) (*machoFile, error) {
 	f, err := macho.NewFile(r)
 	if err != nil {
 		return nil, fmt.Errorf("error when parsing the Mach-O file: %w", err)
 	}
-	ret := &machoFile{file: f, reader: r}
+	ret := &machoFile{file: f, reader: r, maxSectionBytes: opts.MaxSectionBytes, ownsReader: !opts.KeepReaderOpen}
 	ret.getsymtab = sync.OnceValue(ret.initSymtab)
 	return ret, nil
 }
@@ -46,9 +48,21 @@ func openMachO(r io.ReaderAt) (*machoFile, error) {
 var _ fileHandler = (*machoFile)(nil)
 
 type machoFile struct {
-	file      *macho.File
-	reader    io.ReaderAt
-	getsymtab func() map[string]Symbol
+	file            *macho.File
+	reader          io.ReaderAt
+	getsymtab       func() map[string]Symbol
+	maxSectionBytes uint64
+	// ownsReader is true if the reader should be closed together with the
+	// file.
+	ownsReader bool
+}
+
+// sectionData reads the section's data if it's within the size limit.
+func (m *machoFile) sectionData(s *types.Section) ([]byte, error) {
+	if err := checkSectionSize(s.Name, s.Size, m.maxSectionBytes); err != nil {
+		return nil, err
+	}
+	return s.Data()
 }
 
 func (m *machoFile) initSymtab() map[string]Symbol {
@@ -69,16 +83,8 @@ func (m *machoFile) initSymtab() map[string]Symbol {
 		syms = append(syms, Symbol{Name: s.Name, Value: s.Value})
 	}
 
-	slices.SortStableFunc(syms, func(a, b Symbol) int {
-		return cmp.Compare(a.Value, b.Value)
-	})
-
-	for i := 0; i < len(syms)-1; i++ {
-		syms[i].Size = syms[i+1].Value - syms[i].Value
-	}
-
 	symm := make(map[string]Symbol)
-	for _, sym := range syms {
+	for _, sym := range ComputeSymbolSizes(syms) {
 		symm[sym.Name] = sym
 	}
 
@@ -93,6 +99,10 @@ func (m *machoFile) getSymbol(name string) (Symbol, error) {
 	return sym, nil
 }
 
+func (m *machoFile) getSymbolNames() ([]string, error) {
+	return symbolNames(m.getsymtab()), nil
+}
+
 func (m *machoFile) getParsedFile() any {
 	return m.file
 }
@@ -102,9 +112,14 @@ func (m *machoFile) getReader() io.ReaderAt {
 }
 
 func (m *machoFile) Close() error {
-	err := m.file.Close()
-	if err != nil {
-		return err
+	if m.file != nil {
+		err := m.file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if !m.ownsReader {
+		return nil
 	}
 	return tryClose(m.reader)
 }
@@ -118,15 +133,30 @@ func (m *machoFile) getCodeSection() (uint64, []byte, error) {
 	return m.getSectionData("__text")
 }
 
+func (m *machoFile) getCodeSectionReader() (uint64, io.ReaderAt, int64, error) {
+	for _, section := range m.file.Sections {
+		if section.Name == "__text" {
+			// The reader embedded in the section is not relative to the
+			// section so the section is read from the file instead.
+			r := io.NewSectionReader(m.reader, int64(section.Offset), int64(section.Size))
+			return section.Addr, r, int64(section.Size), nil
+		}
+	}
+	return 0, nil, 0, ErrSectionDoesNotExist
+}
+
 func (m *machoFile) getSectionDataFromAddress(address uint64) (uint64, []byte, error) {
 	for _, section := range m.file.Sections {
 		if section.Offset == 0 {
 			// Only exist in memory
 			continue
 		}
+		if m.inCodeSignature(section) {
+			continue
+		}
 
 		if section.Addr <= address && address < (section.Addr+section.Size) {
-			data, err := section.Data()
+			data, err := m.sectionData(section)
 			return section.Addr, data, err
 		}
 	}
@@ -141,10 +171,10 @@ func (m *machoFile) getSectionData(s string) (uint64, []byte, error) {
 			break
 		}
 	}
-	if section == nil {
+	if section == nil || m.inCodeSignature(section) {
 		return 0, nil, ErrSectionDoesNotExist
 	}
-	data, err := section.Data()
+	data, err := m.sectionData(section)
 	return section.Addr, data, err
 }
 
@@ -173,8 +203,8 @@ func (m *machoFile) getPCLNTABData() (uint64, []byte, error) {
 	return m.getSectionData("__gopclntab")
 }
 
-func (m *machoFile) moduledataSection() string {
-	return "__noptrdata"
+func (m *machoFile) moduledataSections() []string {
+	return []string{"__noptrdata", "__data"}
 }
 
 func (m *machoFile) getBuildID() (string, error) {
@@ -199,21 +229,32 @@ func (m *machoFile) getDwarf() (*dwarf.Data, error) {
 		}
 	}
 	sectionData := func(s *types.Section) ([]byte, error) {
-		b, err := s.Data()
+		b, err := m.sectionData(s)
 		if err != nil && uint64(len(b)) < s.Size {
 			return nil, err
 		}
 
 		if len(b) >= 12 && string(b[:4]) == "ZLIB" {
 			dlen := binary.BigEndian.Uint64(b[4:12])
-			dbuf := make([]byte, dlen)
+			if err := checkSectionSize(s.Name, dlen, m.maxSectionBytes); err != nil {
+				return nil, err
+			}
+			if dlen > math.MaxInt64 {
+				return nil, fmt.Errorf("invalid uncompressed size of %s", s.Name)
+			}
 			r, err := zlib.NewReader(bytes.NewBuffer(b[12:]))
 			if err != nil {
 				return nil, err
 			}
-			if _, err := io.ReadFull(r, dbuf); err != nil {
+			// The buffer grows with the decompressed data instead of
+			// trusting the size in the header.
+			dbuf, err := io.ReadAll(io.LimitReader(r, int64(dlen)))
+			if err != nil {
 				return nil, err
 			}
+			if uint64(len(dbuf)) != dlen {
+				return nil, io.ErrUnexpectedEOF
+			}
 			if err := r.Close(); err != nil {
 				return nil, err
 			}
@@ -274,3 +315,257 @@ func (m *machoFile) getDwarf() (*dwarf.Data, error) {
 
 	return d, nil
 }
+
+// GetMinimumOSVersion returns the minimum OS version the binary was built for,
+// for example "macOS 11.0". The version is extracted from the LC_BUILD_VERSION
+// load command or from the older LC_VERSION_MIN_* load commands. This is only
+// supported for Mach-O files. For other file types, ErrUnsupportedFile is
+// returned.
+func (f *GoFile) GetMinimumOSVersion() (string, error) {
+	m, ok := f.fh.(*machoFile)
+	if !ok {
+		return "", ErrUnsupportedFile
+	}
+	return m.getMinimumOSVersion()
+}
+
+func (m *machoFile) getMinimumOSVersion() (string, error) {
+	if builds := m.file.BuildVersions(); len(builds) > 0 {
+		return fmt.Sprintf("%s %s", builds[0].Platform, builds[0].Minos), nil
+	}
+	for _, l := range m.file.Loads {
+		switch v := l.(type) {
+		case *macho.VersionMinMacOSX:
+			return fmt.Sprintf("%s %s", types.Platform_macOS, v.Version), nil
+		case *macho.VersionMiniPhoneOS:
+			return fmt.Sprintf("%s %s", types.Platform_iOS, v.Version), nil
+		case *macho.VersionMinTvOS:
+			return fmt.Sprintf("%s %s", types.Platform_tvOS, v.Version), nil
+		case *macho.VersionMinWatchOS:
+			return fmt.Sprintf("%s %s", types.Platform_watchOS, v.Version), nil
+		}
+	}
+	return "", ErrNoMinimumOSVersion
+}
+
+func (m *machoFile) getSections() ([]Section, error) {
+	sections := make([]Section, 0, len(m.file.Sections))
+	for _, s := range m.file.Sections {
+		sec := Section{
+			Name:           s.Name,
+			VirtualAddress: s.Addr,
+			Size:           s.Size,
+			FileOffset:     uint64(s.Offset),
+		}
+		if seg := m.file.Segment(s.Seg); seg != nil {
+			if seg.Prot.Read() {
+				sec.Permissions |= SectionRead
+			}
+			if seg.Prot.Write() {
+				sec.Permissions |= SectionWrite
+			}
+			if seg.Prot.Execute() {
+				sec.Permissions |= SectionExecute
+			}
+		}
+		sections = append(sections, sec)
+	}
+	return sections, nil
+}
+
+// getEntryPoint returns the entry point from LC_MAIN. The entry is stored as
+// an offset in the __TEXT segment. Files without LC_MAIN, for example the ones
+// not using dyld, have the initial register state in LC_UNIXTHREAD instead.
+func (m *machoFile) getEntryPoint() (uint64, error) {
+	for _, l := range m.file.Loads {
+		switch v := l.(type) {
+		case *macho.EntryPoint:
+			text := m.file.Segment("__TEXT")
+			if text == nil {
+				return 0, fmt.Errorf("no __TEXT segment for the entry point: %w", ErrSectionDoesNotExist)
+			}
+			return text.Addr + v.EntryOffset - text.Offset, nil
+		case *macho.UnixThread:
+			for _, state := range v.Threads {
+				if pc, ok := threadStatePC(state, m.file.ByteOrder); ok {
+					return pc, nil
+				}
+			}
+			return 0, errors.New("unsupported thread state in LC_UNIXTHREAD")
+		}
+	}
+	return 0, errors.New("no entry point load command found")
+}
+
+// threadStatePC returns the program counter from the thread state.
+func threadStatePC(state types.ThreadState, order binary.ByteOrder) (uint64, bool) {
+	const (
+		x86ThreadState32   = 1
+		x86ThreadState64   = 4
+		armThreadState64   = 6
+		x86ThreadState32PC = 10 * 4 // eip after 10 registers.
+		x86ThreadState64PC = 16 * 8 // rip after 16 registers.
+		armThreadState64PC = 32 * 8 // pc after x0-x28, fp, lr and sp.
+	)
+	switch state.Flavor {
+	case x86ThreadState32:
+		if len(state.Data) >= x86ThreadState32PC+4 {
+			return uint64(order.Uint32(state.Data[x86ThreadState32PC:])), true
+		}
+	case x86ThreadState64:
+		if len(state.Data) >= x86ThreadState64PC+8 {
+			return order.Uint64(state.Data[x86ThreadState64PC:]), true
+		}
+	case armThreadState64:
+		if len(state.Data) >= armThreadState64PC+8 {
+			return order.Uint64(state.Data[armThreadState64PC:]), true
+		}
+	}
+	return 0, false
+}
+
+func (m *machoFile) getImportedLibraries() ([]string, error) {
+	return m.file.ImportedLibraries(), nil
+}
+
+// getDynamicImports returns the undefined symbols in the symbol table. The
+// library is taken from the dyld bind information. The Go linker doesn't
+// set the library ordinal in the symbols' description, so it's only used
+// when the bind information is not available.
+func (m *machoFile) getDynamicImports() ([]DynamicImport, error) {
+	if m.file.Symtab == nil || m.file.Dysymtab == nil {
+		return nil, nil
+	}
+	syms, err := m.file.ImportedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the imported symbols: %w", err)
+	}
+	libs := m.file.ImportedLibraries()
+
+	// The bind information only holds the base name of the library.
+	bound := make(map[string]string)
+	if binds, err := m.file.GetBindInfo(); err == nil {
+		for _, b := range binds {
+			lib := b.Dylib
+			for _, l := range libs {
+				if path.Base(l) == lib {
+					lib = l
+					break
+				}
+			}
+			bound[b.Name] = lib
+		}
+	}
+
+	imports := make([]DynamicImport, 0, len(syms))
+	for _, s := range syms {
+		imp := DynamicImport{Name: s.Name, Library: bound[s.Name]}
+		// Ordinal 0 refers to the binary itself and the ordinals at the end
+		// of the range are used for special lookups.
+		if ord := int(s.Desc.GetLibraryOrdinal()); imp.Library == "" && ord > 0 && ord <= len(libs) {
+			imp.Library = libs[ord-1]
+		}
+		imports = append(imports, imp)
+	}
+	return imports, nil
+}
+
+// getExports returns the symbols in the dyld export information. The exports
+// are stored in the LC_DYLD_EXPORTS_TRIE load command by newer linkers and
+// in the LC_DYLD_INFO(_ONLY) load command by older ones.
+func (m *machoFile) getExports() ([]Export, error) {
+	var exps []trie.TrieExport
+	var err error
+	if m.file.DyldExportsTrie() != nil {
+		exps, err = m.file.DyldExports()
+	} else {
+		exps, err = m.file.GetExports()
+		if errors.Is(err, macho.ErrMachODyldInfoNotFound) {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the exports: %w", err)
+	}
+	var exports []Export
+	for _, e := range exps {
+		if e.Flags.ReExport() {
+			continue
+		}
+		exports = append(exports, Export{Name: e.Name, Address: e.Address})
+	}
+	return exports, nil
+}
+
+// isStatic returns true if the file doesn't load a dynamic linker.
+func (m *machoFile) isStatic() bool {
+	for _, l := range m.file.Loads {
+		if _, ok := l.(*macho.LoadDylinker); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// HasCodeSignature returns true if the binary has a code signature. Recent
+// versions of the Go linker ad-hoc sign the binaries, so a signature doesn't
+// mean the binary has been signed by a developer. This is only
+// supported for Mach-O files. For other file types, ErrUnsupportedFile is
+// returned.
+func (f *GoFile) HasCodeSignature() (bool, error) {
+	m, ok := f.fh.(*machoFile)
+	if !ok {
+		return false, ErrUnsupportedFile
+	}
+	_, size := m.codeSignatureRange()
+	return size != 0, nil
+}
+
+// codeSignatureRange returns the file offset and the size of the code
+// signature blob. The size is zero if the file is not signed.
+func (m *machoFile) codeSignatureRange() (uint64, uint64) {
+	cs := m.file.CodeSignature()
+	if cs == nil {
+		return 0, 0
+	}
+	return uint64(cs.Offset), uint64(cs.Size)
+}
+
+// inCodeSignature returns true if the section's file data overlaps with the
+// code signature blob. The signature is placed at the end of the file by
+// the linker, so a section overlapping with it is bogus and should not be
+// scanned.
+func (m *machoFile) inCodeSignature(section *types.Section) bool {
+	off, size := m.codeSignatureRange()
+	if size == 0 || section.Offset == 0 {
+		return false
+	}
+	start := uint64(section.Offset)
+	return start < off+size && off < start+section.Size
+}
+
+// uuidRange returns the file offset and the size of the UUID in the LC_UUID
+// load command. The linker can derive the UUID from the Go build ID so it's
+// written after the build ID. If the file doesn't have a UUID, a size of
+// zero is returned.
+func (m *machoFile) uuidRange() (uint64, uint64) {
+	off := uint64(types.FileHeaderSize32)
+	if m.file.Magic == types.Magic64 {
+		off = types.FileHeaderSize64
+	}
+	cmd := make([]byte, 8)
+	for i := uint32(0); i < m.file.NCommands; i++ {
+		if _, err := m.reader.ReadAt(cmd, int64(off)); err != nil {
+			return 0, 0
+		}
+		size := uint64(m.file.ByteOrder.Uint32(cmd[4:]))
+		if size < 8 {
+			return 0, 0
+		}
+		if types.LoadCmd(m.file.ByteOrder.Uint32(cmd)) == types.LC_UUID {
+			return off + 8, size - 8
+		}
+		off += size
+	}
+	return 0, 0
+}
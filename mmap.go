@@ -0,0 +1,92 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// OpenMmap opens a file by memory mapping it and returns a handler to the
+// file. The file's data is accessed through the mapping instead of read
+// calls, which is faster when large binaries are accessed repeatedly. The
+// mapping is released when the GoFile is closed. Memory mapping is
+// supported on unix systems and Windows. On other platforms, the whole file
+// is read into memory instead.
+func OpenMmap(filePath string) (*GoFile, error) {
+	r, err := mmapFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := OpenReader(r)
+	if err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// mmapReader is an io.ReaderAt reading from a memory mapped file. The lock
+// keeps Close from releasing the mapping while a read is copying from it.
+type mmapReader struct {
+	mu     sync.RWMutex
+	data   []byte
+	unmap  func([]byte) error
+	closed bool
+
+	closeError error
+}
+
+func (m *mmapReader) ReadAt(p []byte, off int64) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return 0, os.ErrClosed
+	}
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close releases the mapping. The data returned by ReadAt are copies so they
+// stay valid after the mapping is released. Reads after Close return
+// os.ErrClosed.
+func (m *mmapReader) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return m.closeError
+	}
+	m.closed = true
+	data := m.data
+	m.data = nil
+	if m.unmap != nil && data != nil {
+		m.closeError = m.unmap(data)
+	}
+	return m.closeError
+}
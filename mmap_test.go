@@ -0,0 +1,96 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenMmap(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	f, err := OpenMmap(exe)
+	require.NoError(t, err)
+	expected, err := Open(exe)
+	require.NoError(t, err)
+	defer expected.Close()
+
+	assert.Equal(t, expected.BuildID, f.BuildID)
+	assert.Equal(t, expected.FileInfo, f.FileInfo)
+
+	r, ok := f.GetReader().(*mmapReader)
+	require.True(t, ok)
+	assert.NoError(t, f.Close())
+	assert.Nil(t, r.data)
+}
+
+func TestMmapReaderReadAt(t *testing.T) {
+	r := &mmapReader{data: []byte("0123456789")}
+
+	buf := make([]byte, 4)
+	n, err := r.ReadAt(buf, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, []byte("2345"), buf)
+
+	n, err = r.ReadAt(buf, 8)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []byte("89"), buf[:n])
+
+	_, err = r.ReadAt(buf, 10)
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = r.ReadAt(buf, -1)
+	assert.Error(t, err)
+
+	assert.NoError(t, r.Close())
+	assert.NoError(t, r.Close())
+
+	_, err = r.ReadAt(buf, 0)
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestMmapReaderConcurrentClose(t *testing.T) {
+	r := &mmapReader{data: make([]byte, 1<<16)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, 512)
+			for {
+				_, err := r.ReadAt(buf, 1024)
+				if err != nil {
+					assert.ErrorIs(t, err, os.ErrClosed)
+					return
+				}
+			}
+		}()
+	}
+	assert.NoError(t, r.Close())
+	wg.Wait()
+}
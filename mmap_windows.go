@@ -0,0 +1,72 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build windows
+
+package gore
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func mmapFile(filePath string) (*mmapReader, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	// The view stays valid after the file and the mapping handles are closed.
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if size == 0 {
+		// Empty files can't be mapped.
+		return &mmapReader{}, nil
+	}
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("file is too large to be mapped: %d bytes", size)
+	}
+	h, err := syscall.CreateFileMapping(syscall.Handle(f.Fd()), nil, syscall.PAGE_READONLY, 0, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map the file: %w", os.NewSyscallError("CreateFileMapping", err))
+	}
+	defer syscall.CloseHandle(h)
+
+	addr, err := syscall.MapViewOfFile(h, syscall.FILE_MAP_READ, 0, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map the file: %w", os.NewSyscallError("MapViewOfFile", err))
+	}
+	// Build the slice through its header so the uintptr returned by the
+	// system call is never converted to an unsafe.Pointer directly.
+	var data []byte
+	hdr := (*struct {
+		data     uintptr
+		len, cap int
+	})(unsafe.Pointer(&data))
+	hdr.data, hdr.len, hdr.cap = addr, int(size), int(size)
+	return &mmapReader{data: data, unmap: unmapView}, nil
+}
+
+func unmapView(data []byte) error {
+	return syscall.UnmapViewOfFile(uintptr(unsafe.Pointer(&data[0])))
+}
@@ -18,10 +18,18 @@
 package gore
 
 import (
+	"crypto/sha256"
 	"debug/buildinfo"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"path"
 	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
@@ -32,14 +40,26 @@ var (
 // BuildInfo that was extracted from the file.
 type BuildInfo struct {
 	// Compiler version. Can be nil.
-	Compiler *GoVersion
+	Compiler *GoVersion `json:"compiler"`
 	// ModInfo holds information about the Go modules in this file.
 	// Can be nil.
-	ModInfo *debug.BuildInfo
+	ModInfo *debug.BuildInfo `json:"modInfo"`
 }
 
 func (f *GoFile) extractBuildInfo() (*BuildInfo, error) {
-	info, err := buildinfo.Read(f.fh.getReader())
+	var info *debug.BuildInfo
+	var err error
+	if w, ok := f.fh.(*wasmFile); ok {
+		info, err = w.readBuildInfo()
+		if err == nil {
+			// The Go version is not stored with the module information.
+			if v, verr := findGoCompilerVersion(f); verr == nil {
+				info.GoVersion = v.Name
+			}
+		}
+	} else {
+		info, err = buildinfo.Read(f.fh.getReader())
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error when extracting build information: %w", err)
 	}
@@ -51,3 +71,159 @@ func (f *GoFile) extractBuildInfo() (*BuildInfo, error) {
 
 	return result, nil
 }
+
+// GetGCFlags returns the flags passed to the compiler with -gcflags when the
+// binary was built. The flags are extracted from the build settings stored in
+// the binary's build information. If the binary was built without -gcflags,
+// an empty string is returned. ErrNoBuildInfo is returned if the binary has
+// no build information.
+func (f *GoFile) GetGCFlags() (string, error) {
+	if f.BuildInfo == nil || f.BuildInfo.ModInfo == nil {
+		return "", ErrNoBuildInfo
+	}
+	flags, _ := f.buildSetting("-gcflags")
+	return flags, nil
+}
+
+// LDFlags returns the flags passed to the linker with -ldflags when the
+// binary was built. The second return value is false if the flags are not
+// recorded in the build settings, for example because the binary was built
+// without -ldflags or has no build information.
+func (f *GoFile) LDFlags() (string, bool) {
+	return f.buildSetting("-ldflags")
+}
+
+// VCSRevision returns the revision of the version control checkout the
+// binary was built from, for example the git commit hash. The second return
+// value is false if the revision is not recorded. The version control
+// information is only recorded by Go 1.18 and later.
+func (f *GoFile) VCSRevision() (string, bool) {
+	return f.buildSetting("vcs.revision")
+}
+
+// VCSTime returns the modification time of the revision the binary was built
+// from. The second return value is false if the time is not recorded or
+// can't be parsed. The version control information is only recorded by Go
+// 1.18 and later.
+func (f *GoFile) VCSTime() (time.Time, bool) {
+	v, ok := f.buildSetting("vcs.time")
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// VCSModified returns true if the working tree had uncommitted changes when
+// the binary was built. The second return value is false if this is not
+// recorded. The version control information is only recorded by Go 1.18 and
+// later.
+func (f *GoFile) VCSModified() (bool, bool) {
+	v, ok := f.buildSetting("vcs.modified")
+	if !ok {
+		return false, false
+	}
+	modified, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, false
+	}
+	return modified, true
+}
+
+// buildSetting returns the value of the build setting with the key. The
+// second return value is false if the setting doesn't exist.
+func (f *GoFile) buildSetting(key string) (string, bool) {
+	if f.BuildInfo == nil || f.BuildInfo.ModInfo == nil {
+		return "", false
+	}
+	for _, s := range f.BuildInfo.ModInfo.Settings {
+		if s.Key == key {
+			return s.Value, true
+		}
+	}
+	return "", false
+}
+
+// HasVCSStamping returns true if the binary was stamped with version control
+// information, for example the revision and the commit time. The stamping is
+// only done when the binary is built from within a repository and it's
+// suppressed with -buildvcs=false, so its absence on a binary built as a
+// module can be a sign that the provenance has been hidden. ErrNoBuildInfo is
+// returned if the binary has no build information.
+func (f *GoFile) HasVCSStamping() (bool, error) {
+	if f.BuildInfo == nil || f.BuildInfo.ModInfo == nil {
+		return false, ErrNoBuildInfo
+	}
+	for _, s := range f.BuildInfo.ModInfo.Settings {
+		if s.Key == "vcs" || strings.HasPrefix(s.Key, "vcs.") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ResolveSourcePath resolves a source path that is relative to the main
+// module's root to its import path qualified form. For example, with the
+// main module "example.com/app", the path "cmd/app/main.go" is resolved to
+// "example.com/app/cmd/app/main.go". This is useful for binaries built with
+// -trimpath where the paths are module relative. Paths that are absolute or
+// already qualified with the main module path are returned unchanged.
+// ErrNoBuildInfo is returned if the main module path is not available.
+func (f *GoFile) ResolveSourcePath(p string) (string, error) {
+	if f.BuildInfo == nil || f.BuildInfo.ModInfo == nil || f.BuildInfo.ModInfo.Main.Path == "" {
+		return "", ErrNoBuildInfo
+	}
+	if p == "" {
+		return "", fmt.Errorf("empty source path")
+	}
+
+	p = strings.ReplaceAll(p, "\\", "/")
+	if path.IsAbs(p) || (len(p) > 1 && p[1] == ':') {
+		// Unix or Windows absolute path.
+		return p, nil
+	}
+
+	p = path.Clean(p)
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("source path %q is outside of the module root", p)
+	}
+
+	mod := f.BuildInfo.ModInfo.Main.Path
+	if p == mod || strings.HasPrefix(p, mod+"/") {
+		return p, nil
+	}
+	return path.Join(mod, p), nil
+}
+
+// ModuleGraphHash returns a hash identifying the set of dependencies the
+// binary was built with. It's the hex encoded SHA-256 hash over the sorted
+// list of the dependencies in the path@version form, one per line. Replaced
+// dependencies are followed by " => " and the replacement in the same form.
+// The main module and the Go version are not included, so binaries built from
+// different code or with different compilers but with the same dependencies
+// have the same hash. ErrNoBuildInfo is returned if the binary has no build
+// information.
+func (f *GoFile) ModuleGraphHash() (string, error) {
+	if f.BuildInfo == nil || f.BuildInfo.ModInfo == nil {
+		return "", ErrNoBuildInfo
+	}
+
+	mods := make([]string, 0, len(f.BuildInfo.ModInfo.Deps))
+	for _, dep := range f.BuildInfo.ModInfo.Deps {
+		mod := dep.Path + "@" + dep.Version
+		if dep.Replace != nil {
+			mod += " => " + dep.Replace.Path + "@" + dep.Replace.Version
+		}
+		mods = append(mods, mod)
+	}
+	slices.Sort(mods)
+
+	h := sha256.New()
+	for _, mod := range mods {
+		io.WriteString(h, mod+"\n")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
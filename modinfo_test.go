@@ -19,8 +19,10 @@ package gore
 
 import (
 	"os"
+	"runtime/debug"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -86,3 +88,183 @@ func TestBuildInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGCFlags(t *testing.T) {
+	t.Run("no build info", func(t *testing.T) {
+		f := new(GoFile)
+		_, err := f.GetGCFlags()
+		require.ErrorIs(t, err, ErrNoBuildInfo)
+	})
+
+	t.Run("flags set", func(t *testing.T) {
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "-compiler", Value: "gc"},
+			{Key: "-gcflags", Value: "all=-N -l"},
+		}}}}
+		flags, err := f.GetGCFlags()
+		require.NoError(t, err)
+		require.Equal(t, "all=-N -l", flags)
+	})
+
+	t.Run("flags not set", func(t *testing.T) {
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "-compiler", Value: "gc"},
+		}}}}
+		flags, err := f.GetGCFlags()
+		require.NoError(t, err)
+		require.Empty(t, flags)
+	})
+}
+
+func TestHasVCSStamping(t *testing.T) {
+	t.Run("no build info", func(t *testing.T) {
+		f := new(GoFile)
+		_, err := f.HasVCSStamping()
+		require.ErrorIs(t, err, ErrNoBuildInfo)
+	})
+
+	t.Run("stamped", func(t *testing.T) {
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "-compiler", Value: "gc"},
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "aeccd613c896d39f582036aa52917c85ecf0b0c0"},
+		}}}}
+		stamped, err := f.HasVCSStamping()
+		require.NoError(t, err)
+		require.True(t, stamped)
+	})
+
+	t.Run("not stamped", func(t *testing.T) {
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "-compiler", Value: "gc"},
+		}}}}
+		stamped, err := f.HasVCSStamping()
+		require.NoError(t, err)
+		require.False(t, stamped)
+	})
+}
+
+func TestResolveSourcePath(t *testing.T) {
+	t.Run("no build info", func(t *testing.T) {
+		f := new(GoFile)
+		_, err := f.ResolveSourcePath("main.go")
+		require.ErrorIs(t, err, ErrNoBuildInfo)
+	})
+
+	f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Main: debug.Module{Path: "example.com/app"}}}}
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"main.go", "example.com/app/main.go"},
+		{"./cmd/app/main.go", "example.com/app/cmd/app/main.go"},
+		{"cmd\\app\\main.go", "example.com/app/cmd/app/main.go"},
+		{"example.com/app/internal/x.go", "example.com/app/internal/x.go"},
+		{"/home/user/app/main.go", "/home/user/app/main.go"},
+		{"C:/Users/user/app/main.go", "C:/Users/user/app/main.go"},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			p, err := f.ResolveSourcePath(test.path)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, p)
+		})
+	}
+
+	_, err := f.ResolveSourcePath("../other/main.go")
+	require.Error(t, err)
+	_, err = f.ResolveSourcePath("")
+	require.Error(t, err)
+}
+
+func TestModuleGraphHash(t *testing.T) {
+	t.Run("no build info", func(t *testing.T) {
+		f := new(GoFile)
+		_, err := f.ModuleGraphHash()
+		require.ErrorIs(t, err, ErrNoBuildInfo)
+	})
+
+	newFile := func(main string, deps ...*debug.Module) *GoFile {
+		return &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Main: debug.Module{Path: main}, Deps: deps}}}
+	}
+	arch := &debug.Module{Path: "golang.org/x/arch", Version: "v0.8.0"}
+	mod := &debug.Module{Path: "golang.org/x/mod", Version: "v0.17.0", Sum: "h1:zY54UmvipHiNd+pm+m0x9KhZ9hl1/7QNMyxXbc6ICqA="}
+
+	hash, err := newFile("example.com/a", mod, arch).ModuleGraphHash()
+	require.NoError(t, err)
+	require.Equal(t, "510948528cefe1162b16274cfcda39f22e8279d529e85052dfa152a1cb98b117", hash)
+
+	other, err := newFile("example.com/b", arch, mod).ModuleGraphHash()
+	require.NoError(t, err)
+	require.Equal(t, hash, other, "the order and the main module don't matter")
+
+	replaced := &debug.Module{Path: "golang.org/x/mod", Version: "v0.17.0", Replace: &debug.Module{Path: "../mod"}}
+	other, err = newFile("example.com/a", arch, replaced).ModuleGraphHash()
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other, "replacements are included")
+}
+
+func TestBuildSettingAccessors(t *testing.T) {
+	t.Run("no build info", func(t *testing.T) {
+		f := new(GoFile)
+		_, ok := f.LDFlags()
+		require.False(t, ok)
+		_, ok = f.VCSRevision()
+		require.False(t, ok)
+		_, ok = f.VCSTime()
+		require.False(t, ok)
+		_, ok = f.VCSModified()
+		require.False(t, ok)
+	})
+
+	t.Run("stamped", func(t *testing.T) {
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "-ldflags", Value: "-s -w -X main.version=1.0.0"},
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "aeccd613c896d39f582036aa52917c85ecf0b0c0"},
+			{Key: "vcs.time", Value: "2024-03-05T10:11:12Z"},
+			{Key: "vcs.modified", Value: "true"},
+		}}}}
+		flags, ok := f.LDFlags()
+		require.True(t, ok)
+		require.Equal(t, "-s -w -X main.version=1.0.0", flags)
+
+		rev, ok := f.VCSRevision()
+		require.True(t, ok)
+		require.Equal(t, "aeccd613c896d39f582036aa52917c85ecf0b0c0", rev)
+
+		ts, ok := f.VCSTime()
+		require.True(t, ok)
+		require.Equal(t, time.Date(2024, 3, 5, 10, 11, 12, 0, time.UTC), ts)
+
+		modified, ok := f.VCSModified()
+		require.True(t, ok)
+		require.True(t, modified)
+	})
+
+	t.Run("not stamped", func(t *testing.T) {
+		// Binaries built before Go 1.18 or with -buildvcs=false.
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "-compiler", Value: "gc"},
+		}}}}
+		_, ok := f.LDFlags()
+		require.False(t, ok)
+		_, ok = f.VCSRevision()
+		require.False(t, ok)
+		_, ok = f.VCSTime()
+		require.False(t, ok)
+		_, ok = f.VCSModified()
+		require.False(t, ok)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		f := &GoFile{BuildInfo: &BuildInfo{ModInfo: &debug.BuildInfo{Settings: []debug.BuildSetting{
+			{Key: "vcs.time", Value: "yesterday"},
+			{Key: "vcs.modified", Value: "maybe"},
+		}}}}
+		_, ok := f.VCSTime()
+		require.False(t, ok)
+		_, ok = f.VCSModified()
+		require.False(t, ok)
+	})
+}
@@ -66,8 +66,17 @@ type moduledata struct {
 	BssAddr, BssLen             uint64
 	NoPtrBssAddr, NoPtrBssLen   uint64
 
+	GCDataAddr, GCBssAddr uint64
+
 	TypesAddr, TypesLen       uint64
 	TypelinkAddr, TypelinkLen uint64
+
+	// RodataAddr is the start of the read-only data. It is only set for
+	// Go 1.18 and later.
+	RodataAddr uint64
+
+	TextSectMapAddr, TextSectMapLen uint64
+
 	ITabLinkAddr, ITabLinkLen uint64
 	FuncTabAddr, FuncTabLen   uint64
 	PCLNTabAddr, PCLNTabLen   uint64
@@ -196,6 +205,130 @@ func (m moduledata) GoFuncValue() uint64 {
 	return m.GoFuncVal
 }
 
+// TextSection is a section of the binary holding code. Large binaries can
+// have the code split across multiple sections.
+type TextSection struct {
+	// Offset is the offset of the section from the start of the text, as
+	// used by the function table.
+	Offset uint64
+	// Length is the length of the section.
+	Length uint64
+	// Address is the virtual address where the section starts.
+	Address uint64
+}
+
+// textSections reads the text section map from the moduledata. If the
+// moduledata has no map, the whole text is returned as one section.
+func (m moduledata) textSections() ([]TextSection, error) {
+	if m.TextSectMapLen == 0 {
+		return []TextSection{{Length: m.TextLen, Address: m.TextAddr}}, nil
+	}
+
+	fi := m.fh.getFileInfo()
+	is32 := fi.WordSize == intSize32
+	base, data, err := m.fh.getSectionDataFromAddress(m.TextSectMapAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the text section map: %w", err)
+	}
+	size := m.TextSectMapLen * 3 * uint64(fi.WordSize)
+	off := m.TextSectMapAddr - base
+	if off+size > uint64(len(data)) {
+		return nil, fmt.Errorf("the text section map at 0x%x is out of bounds", m.TextSectMapAddr)
+	}
+
+	r := bytes.NewReader(data[off : off+size])
+	sects := make([]TextSection, m.TextSectMapLen)
+	for i := range sects {
+		var vals [3]uint64
+		for j := range vals {
+			vals[j], err = readUIntTo64(r, fi.ByteOrder, is32)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read text section %d: %w", i, err)
+			}
+		}
+		sects[i] = TextSection{Offset: vals[0], Length: vals[1] - vals[0], Address: vals[2]}
+	}
+	return sects, nil
+}
+
+// DataBitmaps holds the location of the GC pointer bitmaps for the data and
+// bss sections. The bitmaps are stored as GC programs that the runtime expands
+// to pointer masks with one bit per pointer sized word of the section. A set
+// bit means the word holds a pointer.
+type DataBitmaps struct {
+	// Data is the GC program for the data section.
+	Data ModuleDataSection
+	// Bss is the GC program for the bss section.
+	Bss ModuleDataSection
+}
+
+// dataBitmaps returns the GC programs for the data and bss sections. The
+// length of the programs is not stored in the moduledata so it's determined
+// by decoding them.
+func (m moduledata) dataBitmaps() (*DataBitmaps, error) {
+	if m.GCDataAddr == 0 || m.GCBssAddr == 0 {
+		return nil, fmt.Errorf("the moduledata has no GC bitmaps")
+	}
+	section := func(addr uint64) (ModuleDataSection, error) {
+		base, data, err := m.fh.getSectionDataFromAddress(addr)
+		if err != nil {
+			return ModuleDataSection{}, fmt.Errorf("failed to get the GC program at 0x%x: %w", addr, err)
+		}
+		n, err := gcProgLen(data[addr-base:])
+		if err != nil {
+			return ModuleDataSection{}, fmt.Errorf("failed to decode the GC program at 0x%x: %w", addr, err)
+		}
+		return ModuleDataSection{Address: addr, Length: uint64(n), fh: m.fh}, nil
+	}
+
+	var err error
+	bitmaps := &DataBitmaps{}
+	if bitmaps.Data, err = section(m.GCDataAddr); err != nil {
+		return nil, err
+	}
+	if bitmaps.Bss, err = section(m.GCBssAddr); err != nil {
+		return nil, err
+	}
+	return bitmaps, nil
+}
+
+// gcProgLen returns the length of the GC program, including the terminating
+// zero byte. Keep in sync with runGCProg in runtime/mbitmap.go.
+func gcProgLen(p []byte) (int, error) {
+	i := 0
+	varint := func() error {
+		_, n := binary.Uvarint(p[i:])
+		if n <= 0 {
+			return fmt.Errorf("invalid varint at offset %d", i)
+		}
+		i += n
+		return nil
+	}
+	for i < len(p) {
+		x := p[i]
+		i++
+		if x == 0 {
+			return i, nil
+		}
+		if x&0x80 == 0 {
+			// Literal bits.
+			i += (int(x) + 7) / 8
+			continue
+		}
+		// Repeat of the previous bits. If the count is zero, the bit
+		// count follows as a varint.
+		if x&0x7f == 0 {
+			if err := varint(); err != nil {
+				return 0, err
+			}
+		}
+		if err := varint(); err != nil {
+			return 0, err
+		}
+	}
+	return 0, fmt.Errorf("the GC program is truncated")
+}
+
 // ModuleDataSection is a section defined in the Moduledata structure.
 type ModuleDataSection struct {
 	// Address is the virtual address where the section starts.
@@ -270,6 +403,23 @@ func extractModuledata(f *GoFile) (moduledata, error) {
 		return moduledata{}, err
 	}
 
+	// The moduledata is normally stored in the first section but some
+	// binaries place it elsewhere, so all the candidates are searched.
+	var firstErr error
+	for _, section := range f.fh.moduledataSections() {
+		md, err := extractModuledataFromSection(f, vmd, section)
+		if err == nil {
+			return md, nil
+		}
+		// Report the error from the first section that exists.
+		if firstErr == nil || errors.Is(firstErr, ErrSectionDoesNotExist) {
+			firstErr = err
+		}
+	}
+	return moduledata{}, firstErr
+}
+
+func extractModuledataFromSection(f *GoFile, vmd modulable, section string) (moduledata, error) {
 	vmdSize := binary.Size(vmd)
 
 	// pre define these variables to follow the goto requirements
@@ -277,7 +427,7 @@ func extractModuledata(f *GoFile) (moduledata, error) {
 	var magic []byte
 	var tabAddr uint64
 
-	secAddr, secData, err := f.fh.getSectionData(f.fh.moduledataSection())
+	secAddr, secData, err := f.fh.getSectionData(section)
 	if err != nil {
 		return moduledata{}, err
 	}
@@ -303,7 +453,7 @@ load:
 	if off == -1 {
 		return moduledata{}, errors.New("could not find moduledata")
 	}
-	if len(secData) < off+vmdSize {
+	if off < 0 || len(secData) < off+vmdSize {
 		return moduledata{}, fmt.Errorf("offset %d is out of bounds %d", off, len(secData))
 	}
 
@@ -56,6 +56,8 @@ func (md moduledata_1_5_32) toModuledata() moduledata {
 		BssLen:        uint64(md.Ebss - md.Bss),
 		NoPtrBssAddr:  uint64(md.Noptrbss),
 		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:    uint64(md.Gcdata),
+		GCBssAddr:     uint64(md.Gcbss),
 		TypelinkAddr:  uint64(md.Typelinks),
 		TypelinkLen:   uint64(md.Typelinkslen),
 		FuncTabAddr:   uint64(md.Ftab),
@@ -100,6 +102,8 @@ func (md moduledata_1_5_64) toModuledata() moduledata {
 		BssLen:        md.Ebss - md.Bss,
 		NoPtrBssAddr:  md.Noptrbss,
 		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:    md.Gcdata,
+		GCBssAddr:     md.Gcbss,
 		TypelinkAddr:  md.Typelinks,
 		TypelinkLen:   md.Typelinkslen,
 		FuncTabAddr:   md.Ftab,
@@ -144,6 +148,8 @@ func (md moduledata_1_6_32) toModuledata() moduledata {
 		BssLen:        uint64(md.Ebss - md.Bss),
 		NoPtrBssAddr:  uint64(md.Noptrbss),
 		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:    uint64(md.Gcdata),
+		GCBssAddr:     uint64(md.Gcbss),
 		TypelinkAddr:  uint64(md.Typelinks),
 		TypelinkLen:   uint64(md.Typelinkslen),
 		FuncTabAddr:   uint64(md.Ftab),
@@ -188,6 +194,8 @@ func (md moduledata_1_6_64) toModuledata() moduledata {
 		BssLen:        md.Ebss - md.Bss,
 		NoPtrBssAddr:  md.Noptrbss,
 		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:    md.Gcdata,
+		GCBssAddr:     md.Gcbss,
 		TypelinkAddr:  md.Typelinks,
 		TypelinkLen:   md.Typelinkslen,
 		FuncTabAddr:   md.Ftab,
@@ -235,6 +243,8 @@ func (md moduledata_1_7_32) toModuledata() moduledata {
 		BssLen:        uint64(md.Ebss - md.Bss),
 		NoPtrBssAddr:  uint64(md.Noptrbss),
 		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:    uint64(md.Gcdata),
+		GCBssAddr:     uint64(md.Gcbss),
 		TypesAddr:     uint64(md.Types),
 		TypesLen:      uint64(md.Etypes - md.Types),
 		TypelinkAddr:  uint64(md.Typelinks),
@@ -286,6 +296,8 @@ func (md moduledata_1_7_64) toModuledata() moduledata {
 		BssLen:        md.Ebss - md.Bss,
 		NoPtrBssAddr:  md.Noptrbss,
 		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:    md.Gcdata,
+		GCBssAddr:     md.Gcbss,
 		TypesAddr:     md.Types,
 		TypesLen:      md.Etypes - md.Types,
 		TypelinkAddr:  md.Typelinks,
@@ -331,26 +343,30 @@ type moduledata_1_8_32 struct {
 
 func (md moduledata_1_8_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -386,26 +402,30 @@ type moduledata_1_8_64 struct {
 
 func (md moduledata_1_8_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -441,26 +461,30 @@ type moduledata_1_9_32 struct {
 
 func (md moduledata_1_9_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -496,26 +520,30 @@ type moduledata_1_9_64 struct {
 
 func (md moduledata_1_9_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -551,26 +579,30 @@ type moduledata_1_10_32 struct {
 
 func (md moduledata_1_10_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -606,26 +638,30 @@ type moduledata_1_10_64 struct {
 
 func (md moduledata_1_10_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -661,26 +697,30 @@ type moduledata_1_11_32 struct {
 
 func (md moduledata_1_11_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -716,26 +756,30 @@ type moduledata_1_11_64 struct {
 
 func (md moduledata_1_11_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -771,26 +815,30 @@ type moduledata_1_12_32 struct {
 
 func (md moduledata_1_12_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -826,26 +874,30 @@ type moduledata_1_12_64 struct {
 
 func (md moduledata_1_12_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -881,26 +933,30 @@ type moduledata_1_13_32 struct {
 
 func (md moduledata_1_13_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -936,26 +992,30 @@ type moduledata_1_13_64 struct {
 
 func (md moduledata_1_13_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -991,26 +1051,30 @@ type moduledata_1_14_32 struct {
 
 func (md moduledata_1_14_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -1046,26 +1110,30 @@ type moduledata_1_14_64 struct {
 
 func (md moduledata_1_14_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -1101,26 +1169,30 @@ type moduledata_1_15_32 struct {
 
 func (md moduledata_1_15_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -1156,26 +1228,30 @@ type moduledata_1_15_64 struct {
 
 func (md moduledata_1_15_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -1215,26 +1291,30 @@ type moduledata_1_16_32 struct {
 
 func (md moduledata_1_16_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -1274,26 +1354,30 @@ type moduledata_1_16_64 struct {
 
 func (md moduledata_1_16_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -1333,26 +1417,30 @@ type moduledata_1_17_32 struct {
 
 func (md moduledata_1_17_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
 	}
 }
 
@@ -1376,42 +1464,46 @@ type moduledata_1_17_64 struct {
 	Bss                                         uint64
 	Ebss                                        uint64
 	Noptrbss                                    uint64
-	Enoptrbss                                   uint64
-	End                                         uint64
-	Gcdata                                      uint64
-	Gcbss                                       uint64
-	Types                                       uint64
-	Etypes                                      uint64
-	Textsectmap, Textsectmaplen, Textsectmapcap uint64
-	Typelinks, Typelinkslen, Typelinkscap       uint64
-	Itablinks, Itablinkslen, Itablinkscap       uint64
-	Ptab, Ptablen, Ptabcap                      uint64
-	Pluginpath, Pluginpathlen                   uint64
-	Pkghashes, Pkghasheslen, Pkghashescap       uint64
-}
-
-func (md moduledata_1_17_64) toModuledata() moduledata {
-	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
+	Enoptrbss                                   uint64
+	End                                         uint64
+	Gcdata                                      uint64
+	Gcbss                                       uint64
+	Types                                       uint64
+	Etypes                                      uint64
+	Textsectmap, Textsectmaplen, Textsectmapcap uint64
+	Typelinks, Typelinkslen, Typelinkscap       uint64
+	Itablinks, Itablinkslen, Itablinkscap       uint64
+	Ptab, Ptablen, Ptabcap                      uint64
+	Pluginpath, Pluginpathlen                   uint64
+	Pkghashes, Pkghasheslen, Pkghashescap       uint64
+}
+
+func (md moduledata_1_17_64) toModuledata() moduledata {
+	return moduledata{
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
 	}
 }
 
@@ -1453,27 +1545,32 @@ type moduledata_1_18_32 struct {
 
 func (md moduledata_1_18_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
-		GoFuncVal:     uint64(md.Gofunc),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		RodataAddr:      uint64(md.Rodata),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
+		GoFuncVal:       uint64(md.Gofunc),
 	}
 }
 
@@ -1515,27 +1612,32 @@ type moduledata_1_18_64 struct {
 
 func (md moduledata_1_18_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
-		GoFuncVal:     md.Gofunc,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		RodataAddr:      md.Rodata,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
+		GoFuncVal:       md.Gofunc,
 	}
 }
 
@@ -1577,27 +1679,32 @@ type moduledata_1_19_32 struct {
 
 func (md moduledata_1_19_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
-		GoFuncVal:     uint64(md.Gofunc),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		RodataAddr:      uint64(md.Rodata),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
+		GoFuncVal:       uint64(md.Gofunc),
 	}
 }
 
@@ -1639,27 +1746,32 @@ type moduledata_1_19_64 struct {
 
 func (md moduledata_1_19_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
-		GoFuncVal:     md.Gofunc,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		RodataAddr:      md.Rodata,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
+		GoFuncVal:       md.Gofunc,
 	}
 }
 
@@ -1703,27 +1815,32 @@ type moduledata_1_20_32 struct {
 
 func (md moduledata_1_20_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
-		GoFuncVal:     uint64(md.Gofunc),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		RodataAddr:      uint64(md.Rodata),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
+		GoFuncVal:       uint64(md.Gofunc),
 	}
 }
 
@@ -1767,27 +1884,32 @@ type moduledata_1_20_64 struct {
 
 func (md moduledata_1_20_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
-		GoFuncVal:     md.Gofunc,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		RodataAddr:      md.Rodata,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
+		GoFuncVal:       md.Gofunc,
 	}
 }
 
@@ -1832,27 +1954,32 @@ type moduledata_1_21_32 struct {
 
 func (md moduledata_1_21_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
-		GoFuncVal:     uint64(md.Gofunc),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		RodataAddr:      uint64(md.Rodata),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
+		GoFuncVal:       uint64(md.Gofunc),
 	}
 }
 
@@ -1897,27 +2024,32 @@ type moduledata_1_21_64 struct {
 
 func (md moduledata_1_21_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
-		GoFuncVal:     md.Gofunc,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		RodataAddr:      md.Rodata,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
+		GoFuncVal:       md.Gofunc,
 	}
 }
 
@@ -1962,27 +2094,32 @@ type moduledata_1_22_32 struct {
 
 func (md moduledata_1_22_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
-		GoFuncVal:     uint64(md.Gofunc),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		RodataAddr:      uint64(md.Rodata),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
+		GoFuncVal:       uint64(md.Gofunc),
 	}
 }
 
@@ -2027,27 +2164,32 @@ type moduledata_1_22_64 struct {
 
 func (md moduledata_1_22_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
-		GoFuncVal:     md.Gofunc,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		RodataAddr:      md.Rodata,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
+		GoFuncVal:       md.Gofunc,
 	}
 }
 
@@ -2092,27 +2234,32 @@ type moduledata_1_23_32 struct {
 
 func (md moduledata_1_23_32) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      uint64(md.Text),
-		TextLen:       uint64(md.Etext - md.Text),
-		NoPtrDataAddr: uint64(md.Noptrdata),
-		NoPtrDataLen:  uint64(md.Enoptrdata - md.Noptrdata),
-		DataAddr:      uint64(md.Data),
-		DataLen:       uint64(md.Edata - md.Data),
-		BssAddr:       uint64(md.Bss),
-		BssLen:        uint64(md.Ebss - md.Bss),
-		NoPtrBssAddr:  uint64(md.Noptrbss),
-		NoPtrBssLen:   uint64(md.Enoptrbss - md.Noptrbss),
-		TypesAddr:     uint64(md.Types),
-		TypesLen:      uint64(md.Etypes - md.Types),
-		TypelinkAddr:  uint64(md.Typelinks),
-		TypelinkLen:   uint64(md.Typelinkslen),
-		ITabLinkAddr:  uint64(md.Itablinks),
-		ITabLinkLen:   uint64(md.Itablinkslen),
-		FuncTabAddr:   uint64(md.Ftab),
-		FuncTabLen:    uint64(md.Ftablen),
-		PCLNTabAddr:   uint64(md.Pclntable),
-		PCLNTabLen:    uint64(md.Pclntablelen),
-		GoFuncVal:     uint64(md.Gofunc),
+		TextAddr:        uint64(md.Text),
+		TextLen:         uint64(md.Etext - md.Text),
+		NoPtrDataAddr:   uint64(md.Noptrdata),
+		NoPtrDataLen:    uint64(md.Enoptrdata - md.Noptrdata),
+		DataAddr:        uint64(md.Data),
+		DataLen:         uint64(md.Edata - md.Data),
+		BssAddr:         uint64(md.Bss),
+		BssLen:          uint64(md.Ebss - md.Bss),
+		NoPtrBssAddr:    uint64(md.Noptrbss),
+		NoPtrBssLen:     uint64(md.Enoptrbss - md.Noptrbss),
+		GCDataAddr:      uint64(md.Gcdata),
+		GCBssAddr:       uint64(md.Gcbss),
+		TypesAddr:       uint64(md.Types),
+		TypesLen:        uint64(md.Etypes - md.Types),
+		RodataAddr:      uint64(md.Rodata),
+		TextSectMapAddr: uint64(md.Textsectmap),
+		TextSectMapLen:  uint64(md.Textsectmaplen),
+		TypelinkAddr:    uint64(md.Typelinks),
+		TypelinkLen:     uint64(md.Typelinkslen),
+		ITabLinkAddr:    uint64(md.Itablinks),
+		ITabLinkLen:     uint64(md.Itablinkslen),
+		FuncTabAddr:     uint64(md.Ftab),
+		FuncTabLen:      uint64(md.Ftablen),
+		PCLNTabAddr:     uint64(md.Pclntable),
+		PCLNTabLen:      uint64(md.Pclntablelen),
+		GoFuncVal:       uint64(md.Gofunc),
 	}
 }
 
@@ -2157,27 +2304,32 @@ type moduledata_1_23_64 struct {
 
 func (md moduledata_1_23_64) toModuledata() moduledata {
 	return moduledata{
-		TextAddr:      md.Text,
-		TextLen:       md.Etext - md.Text,
-		NoPtrDataAddr: md.Noptrdata,
-		NoPtrDataLen:  md.Enoptrdata - md.Noptrdata,
-		DataAddr:      md.Data,
-		DataLen:       md.Edata - md.Data,
-		BssAddr:       md.Bss,
-		BssLen:        md.Ebss - md.Bss,
-		NoPtrBssAddr:  md.Noptrbss,
-		NoPtrBssLen:   md.Enoptrbss - md.Noptrbss,
-		TypesAddr:     md.Types,
-		TypesLen:      md.Etypes - md.Types,
-		TypelinkAddr:  md.Typelinks,
-		TypelinkLen:   md.Typelinkslen,
-		ITabLinkAddr:  md.Itablinks,
-		ITabLinkLen:   md.Itablinkslen,
-		FuncTabAddr:   md.Ftab,
-		FuncTabLen:    md.Ftablen,
-		PCLNTabAddr:   md.Pclntable,
-		PCLNTabLen:    md.Pclntablelen,
-		GoFuncVal:     md.Gofunc,
+		TextAddr:        md.Text,
+		TextLen:         md.Etext - md.Text,
+		NoPtrDataAddr:   md.Noptrdata,
+		NoPtrDataLen:    md.Enoptrdata - md.Noptrdata,
+		DataAddr:        md.Data,
+		DataLen:         md.Edata - md.Data,
+		BssAddr:         md.Bss,
+		BssLen:          md.Ebss - md.Bss,
+		NoPtrBssAddr:    md.Noptrbss,
+		NoPtrBssLen:     md.Enoptrbss - md.Noptrbss,
+		GCDataAddr:      md.Gcdata,
+		GCBssAddr:       md.Gcbss,
+		TypesAddr:       md.Types,
+		TypesLen:        md.Etypes - md.Types,
+		RodataAddr:      md.Rodata,
+		TextSectMapAddr: md.Textsectmap,
+		TextSectMapLen:  md.Textsectmaplen,
+		TypelinkAddr:    md.Typelinks,
+		TypelinkLen:     md.Typelinkslen,
+		ITabLinkAddr:    md.Itablinks,
+		ITabLinkLen:     md.Itablinkslen,
+		FuncTabAddr:     md.Ftab,
+		FuncTabLen:      md.Ftablen,
+		PCLNTabAddr:     md.Pclntable,
+		PCLNTabLen:      md.Pclntablelen,
+		GoFuncVal:       md.Gofunc,
 	}
 }
 
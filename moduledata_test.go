@@ -18,6 +18,10 @@
 package gore
 
 import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -91,3 +95,78 @@ func TestModuledata(t *testing.T) {
 		})
 	}
 }
+
+func TestGCProgLen(t *testing.T) {
+	r := require.New(t)
+
+	// 10 literal bits, a repeat of 1 bit 5 times, a repeat of 300 bits
+	// 2 times and the terminating zero.
+	prog := []byte{0x0a, 0xff, 0x03, 0x81, 0x05, 0x80, 0xac, 0x02, 0x02, 0x00, 0xaa}
+	n, err := gcProgLen(prog)
+	r.NoError(err)
+	r.Equal(10, n)
+
+	_, err = gcProgLen(prog[:9])
+	r.Error(err)
+	_, err = gcProgLen([]byte{0x81})
+	r.Error(err)
+}
+
+func TestExtractModuledataSearchesAllSections(t *testing.T) {
+	r := require.New(t)
+
+	const pclntabAddr = 0x5000
+	buf := &bytes.Buffer{}
+	buf.Write([]byte{0xcc, 0xcc, 0xcc, 0xcc})
+	r.NoError(binary.Write(buf, binary.LittleEndian, &moduledata_1_20_64{
+		PcHeader: pclntabAddr,
+		Text:     0x1000,
+		Etext:    0x1080,
+	}))
+
+	f := &GoFile{
+		FileInfo: &FileInfo{WordSize: intSize64, ByteOrder: binary.LittleEndian, goversion: goversions["go1.20"]},
+		fh: &mockFileHandler{
+			mModuledataSections: func() []string {
+				return []string{".noptrdata", ".data"}
+			},
+			mGetSectionData: func(name string) (uint64, []byte, error) {
+				if name == ".data" {
+					return 0x3000, buf.Bytes(), nil
+				}
+				return 0x2000, make([]byte, 0x100), nil
+			},
+			mGetSymbol: func(string) (Symbol, error) {
+				return Symbol{}, ErrSymbolNotFound
+			},
+			mGetCodeSection: func() (uint64, []byte, error) {
+				return 0x1000, make([]byte, 0x100), nil
+			},
+		},
+	}
+	f.pclntabOnce.Do(func() {})
+	f.pclntabAddr = pclntabAddr
+
+	md, err := extractModuledata(f)
+	r.NoError(err)
+	r.Equal(uint64(0x1000), md.TextAddr)
+	r.Equal(uint64(0x80), md.TextLen)
+}
+
+func TestELFModuledataSectionsAreStored(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	f, err := Open(exe)
+	require.NoError(t, err)
+	defer f.Close()
+
+	e, ok := f.fh.(*elfFile)
+	if !ok {
+		t.Skip("the test binary is not an ELF file")
+	}
+	for _, name := range e.moduledataSections() {
+		s := e.file.Section(name)
+		require.NotNil(t, s, name)
+		require.NotEqual(t, elf.SHT_NOBITS, s.Type, name)
+	}
+}
@@ -0,0 +1,133 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// nmSymbol is a symbol written by WriteNM.
+type nmSymbol struct {
+	name string
+	addr uint64
+	code byte
+}
+
+// WriteNM writes the symbols of the binary to w in the format used by
+// "go tool nm". Each line has the address, the symbol type and the name of
+// the symbol. The symbols are sorted by name. The symbols are taken from the
+// symbol table and extended with the functions from the pclntab, so stripped
+// binaries still list their functions. The type is based on the section the
+// symbol is located in: T for code, R for read-only data, D for data, B for
+// the bss sections and U for undefined symbols. Since the symbol binding is
+// not known, local symbols are also written with upper case types.
+func (f *GoFile) WriteNM(w io.Writer) error {
+	syms, err := f.Symbols()
+	if err != nil {
+		return err
+	}
+	sects, err := f.Sections()
+	if err != nil {
+		return err
+	}
+	// The permissions of the Mach-O sections are the ones of the segment, which
+	// makes the read-only data in the __TEXT segment executable. The section
+	// attributes are used instead to find the code.
+	if m, ok := f.fh.(*machoFile); ok {
+		for i, s := range m.file.Sections {
+			if !s.Flags.IsPureInstructions() && !s.Flags.IsSomeInstructions() {
+				sects[i].Permissions &^= SectionExecute
+			}
+		}
+	}
+	var bss []ModuleDataSection
+	if f.initModuleData() == nil {
+		bss = []ModuleDataSection{f.moduledata.Bss(), f.moduledata.NoPtrBss()}
+	}
+
+	symbolCode := func(addr uint64) byte {
+		if addr == 0 {
+			return 'U'
+		}
+		for _, s := range bss {
+			if addr >= s.Address && addr < s.Address+s.Length {
+				return 'B'
+			}
+		}
+		for _, s := range sects {
+			if addr < s.VirtualAddress || addr >= s.VirtualAddress+s.Size {
+				continue
+			}
+			switch {
+			case s.Permissions&SectionExecute != 0:
+				return 'T'
+			case s.Permissions&SectionWrite != 0:
+				return 'D'
+			case s.Permissions&SectionRead != 0:
+				return 'R'
+			}
+		}
+		return '?'
+	}
+
+	// The functions from the pclntab are only added if there isn't already a
+	// symbol at the address. Assembly functions can for example have an
+	// ".abi0" suffix in the symbol table.
+	list := make([]nmSymbol, 0, len(syms))
+	seen := make(map[string]bool, len(syms))
+	code := make(map[uint64]bool)
+	for _, s := range syms {
+		if s.Name == "" {
+			continue
+		}
+		c := symbolCode(s.Value)
+		list = append(list, nmSymbol{name: s.Name, addr: s.Value, code: c})
+		seen[s.Name] = true
+		if c == 'T' {
+			code[s.Value] = true
+		}
+	}
+	if f.initPackages() == nil {
+		for _, fn := range f.pclntab.Funcs {
+			if !seen[fn.Name] && !code[fn.Entry] {
+				list = append(list, nmSymbol{name: fn.Name, addr: fn.Entry, code: 'T'})
+				seen[fn.Name] = true
+			}
+		}
+	}
+	slices.SortFunc(list, func(a, b nmSymbol) int {
+		if c := cmp.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.addr, b.addr)
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, s := range list {
+		if s.code == 'U' {
+			fmt.Fprintf(bw, "%8s %c %s\n", "", s.code, s.name)
+		} else {
+			fmt.Fprintf(bw, "%8x %c %s\n", s.addr, s.code, s.name)
+		}
+	}
+	return bw.Flush()
+}
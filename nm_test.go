@@ -0,0 +1,72 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"debug/gosym"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteNM(t *testing.T) {
+	symm := map[string]Symbol{
+		"main.main":      {Name: "main.main", Value: 0x401000, Size: 0x20},
+		"runtime.rodata": {Name: "runtime.rodata", Value: 0x402000},
+		"main.counter":   {Name: "main.counter", Value: 0x403000, Size: 8},
+		"main.buf":       {Name: "main.buf", Value: 0x404000, Size: 0x100},
+		"_write":         {Name: "_write"},
+	}
+	f := &GoFile{
+		fh: &mockFileHandler{
+			mGetSymbolNames: func() ([]string, error) {
+				return symbolNames(symm), nil
+			},
+			mGetSymbol: func(name string) (Symbol, error) {
+				return symm[name], nil
+			},
+			mGetSections: func() ([]Section, error) {
+				return []Section{
+					{Name: ".text", VirtualAddress: 0x401000, Size: 0x1000, Permissions: SectionRead | SectionExecute},
+					{Name: ".rodata", VirtualAddress: 0x402000, Size: 0x1000, Permissions: SectionRead},
+					{Name: ".data", VirtualAddress: 0x403000, Size: 0x1000, Permissions: SectionRead | SectionWrite},
+					{Name: ".bss", VirtualAddress: 0x404000, Size: 0x1000, Permissions: SectionRead | SectionWrite},
+				}, nil
+			},
+		},
+		moduledata: moduledata{BssAddr: 0x404000, BssLen: 0x1000},
+		pclntab: &gosym.Table{Funcs: []gosym.Func{
+			{Entry: 0x401000, End: 0x401020, Sym: &gosym.Sym{Name: "main.main"}},
+			{Entry: 0x401020, End: 0x401040, Sym: &gosym.Sym{Name: "main.helper"}},
+		}},
+	}
+	// The moduledata and the packages are already set.
+	f.initModuleDataOnce.Do(func() {})
+	f.initPackagesDone = true
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, f.WriteNM(buf))
+	assert.Equal(t, "         U _write\n"+
+		"  404000 B main.buf\n"+
+		"  403000 D main.counter\n"+
+		"  401020 T main.helper\n"+
+		"  401000 T main.main\n"+
+		"  402000 R runtime.rodata\n", buf.String())
+}
@@ -0,0 +1,124 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import "strings"
+
+// Weights of the signals used by ObfuscationScore.
+const (
+	obfuscationNoModInfoWeight = 0.2
+	obfuscationPackageWeight   = 0.4
+	obfuscationFunctionWeight  = 0.4
+)
+
+// ObfuscationScore returns a score between 0 and 1 for how likely it is that
+// the binary has been obfuscated with garble. Garble strips the module
+// information and replaces the names of the packages and functions with
+// short hashes. The score combines whether the module information is missing
+// with the fraction of hash-like names among the packages and the functions
+// in the packages not classified as part of the standard library. Unobfuscated
+// binaries score close to zero.
+func (f *GoFile) ObfuscationScore() (float64, error) {
+	err := f.initPackages()
+	if err != nil {
+		return 0, err
+	}
+
+	var score float64
+	if f.BuildInfo == nil || f.BuildInfo.ModInfo == nil || f.BuildInfo.ModInfo.Main.Path == "" {
+		score += obfuscationNoModInfoWeight
+	}
+
+	var pkgs, hashedPkgs, fcns, hashedFcns int
+	for _, class := range [][]*Package{f.pkgs, f.vendors, f.unknown} {
+		for _, p := range class {
+			// The main package keeps its name.
+			if p.Name != "main" {
+				pkgs++
+				if isHashLikeName(p.Name) {
+					hashedPkgs++
+				}
+			}
+			names := make([]string, 0, len(p.Functions)+len(p.Methods))
+			for _, fn := range p.Functions {
+				names = append(names, fn.Name)
+			}
+			for _, m := range p.Methods {
+				names = append(names, m.Name)
+			}
+			for _, name := range names {
+				// Use the name of the outermost function for closures.
+				name, _, _ = strings.Cut(name, ".")
+				if name == "" || name == "main" || name == "init" || name == "glob" {
+					continue
+				}
+				fcns++
+				if isHashLikeName(name) {
+					hashedFcns++
+				}
+			}
+		}
+	}
+	if pkgs > 0 {
+		score += obfuscationPackageWeight * float64(hashedPkgs) / float64(pkgs)
+	}
+	if fcns > 0 {
+		score += obfuscationFunctionWeight * float64(hashedFcns) / float64(fcns)
+	}
+	return score, nil
+}
+
+// IsObfuscated returns true if the binary is likely to have been obfuscated
+// with garble. It's a shorthand for an ObfuscationScore of at least 0.5.
+func (f *GoFile) IsObfuscated() (bool, error) {
+	score, err := f.ObfuscationScore()
+	if err != nil {
+		return false, err
+	}
+	return score >= 0.5, nil
+}
+
+// isHashLikeName returns true if the name looks like a hash generated by
+// garble. The hashes are base64 encoded, so the lowercase letters are broken
+// up by uppercase letters and digits into short runs. In the identifiers
+// written by people, the lowercase runs are the words of the name and
+// usually longer than a single letter.
+func isHashLikeName(name string) bool {
+	if len(name) < 6 || len(name) > 16 {
+		return false
+	}
+	var lower, runs int
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z':
+			lower++
+			if i == 0 || name[i-1] < 'a' || name[i-1] > 'z' {
+				runs++
+			}
+		case 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '_':
+		default:
+			return false
+		}
+	}
+	if lower == 0 || lower == len(name) {
+		return false
+	}
+	// The average length of the lowercase runs is below 2.
+	return lower < 2*runs
+}
@@ -0,0 +1,79 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsHashLikeName(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"Bm8jYgD4":        true,
+		"kX2a_pQ9":        true,
+		"rT4wE1zK":        true,
+		"q0jRz3":          true,
+		"ReadAt":          false,
+		"IsBoolFlag":      false,
+		"OnesCount64":     false,
+		"umul192Upper128": false,
+		"readV2Limit":     false,
+		"HTTP2Server":     false,
+		"sha256":          false,
+		"NEWLINE":         false,
+		"abc":             false,
+		"net/http":        false,
+		"(*T).Read":       false,
+	} {
+		assert.Equal(t, expected, isHashLikeName(name), name)
+	}
+}
+
+func TestObfuscationScore(t *testing.T) {
+	newFile := func(bi *BuildInfo, pkgs ...*Package) *GoFile {
+		f := &GoFile{BuildInfo: bi, unknown: pkgs}
+		// The packages are already set.
+		f.initPackagesDone = true
+		return f
+	}
+
+	plain := newFile(&BuildInfo{ModInfo: &debug.BuildInfo{Main: debug.Module{Path: "example.com/tool"}}},
+		&Package{Name: "main", Functions: []*Function{{Name: "main"}, {Name: "parseFlags"}}},
+		&Package{Name: "example.com/tool/config", Functions: []*Function{{Name: "Load"}, {Name: "Load.func1"}}},
+	)
+	score, err := plain.ObfuscationScore()
+	assert.NoError(t, err)
+	assert.Zero(t, score)
+	obfuscated, err := plain.IsObfuscated()
+	assert.NoError(t, err)
+	assert.False(t, obfuscated)
+
+	garbled := newFile(nil,
+		&Package{Name: "main", Functions: []*Function{{Name: "main"}, {Name: "Bm8jYgD4"}, {Name: "init.0"}}},
+		&Package{Name: "kX2a_pQ9", Functions: []*Function{{Name: "rT4wE1zK"}, {Name: "rT4wE1zK.func1"}},
+			Methods: []*Method{{Function: &Function{Name: "q0jRz3"}}, {Function: &Function{Name: "String"}}}},
+	)
+	score, err = garbled.ObfuscationScore()
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.2+0.4+0.4*4/5, score, 1e-9)
+	obfuscated, err = garbled.IsObfuscated()
+	assert.NoError(t, err)
+	assert.True(t, obfuscated)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"path"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -41,6 +42,201 @@ type Package struct {
 	Functions []*Function `json:"functions"`
 	// Methods a list of methods that are part of the package.
 	Methods []*Method `json:"methods"`
+	// ImportPath is the full import path of the package. It is only set
+	// after calling EnrichPackagePaths.
+	ImportPath string `json:"importPath,omitempty"`
+}
+
+// WithoutMethodWrappers returns a copy of the package without the method
+// wrappers generated by the compiler. The functions and methods are shared
+// with the package. See Function.IsMethodWrapper for the wrappers that are
+// removed.
+func (p *Package) WithoutMethodWrappers() *Package {
+	c := *p
+	c.Functions = make([]*Function, 0, len(p.Functions))
+	for _, fn := range p.Functions {
+		if !fn.IsMethodWrapper() {
+			c.Functions = append(c.Functions, fn)
+		}
+	}
+	c.Methods = make([]*Method, 0, len(p.Methods))
+	for _, m := range p.Methods {
+		if !m.IsMethodWrapper() {
+			c.Methods = append(c.Methods, m)
+		}
+	}
+	return &c
+}
+
+// EnrichPackagePaths sets the ImportPath of all the packages by cross-referencing
+// the package names with the package paths stored in the type metadata. If the
+// package's name is a suffix of exactly one type package path, that path is
+// used. Otherwise, the package's name is used as the import path.
+func (f *GoFile) EnrichPackagePaths() error {
+	err := f.initPackages()
+	if err != nil {
+		return err
+	}
+	types, err := f.GetTypes()
+	if err != nil {
+		return err
+	}
+
+	paths := make(map[string]struct{})
+	for _, t := range types {
+		if t.PackagePath != "" {
+			paths[t.PackagePath] = struct{}{}
+		}
+	}
+
+	for _, pkgs := range [][]*Package{f.stdPkgs, f.generated, f.pkgs, f.vendors, f.unknown} {
+		for _, p := range pkgs {
+			p.ImportPath = resolveImportPath(p.Name, paths)
+		}
+	}
+	return nil
+}
+
+// PackageSize is the total size of the code for a package.
+type PackageSize struct {
+	// Name is the name of the package.
+	Name string `json:"name"`
+	// Size is the number of code bytes of the package's functions and methods.
+	Size uint64 `json:"size"`
+}
+
+// SizeAttribution returns the total size of the code for each package, keyed
+// by the package name. The size is the sum of the sizes of the package's
+// functions and methods. This can be used to find out which packages
+// contribute the most to the size of the binary.
+func (f *GoFile) SizeAttribution() (map[string]uint64, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]uint64)
+	for _, pkgs := range [][]*Package{f.stdPkgs, f.generated, f.pkgs, f.vendors, f.unknown} {
+		for _, p := range pkgs {
+			sizes[p.Name] += packageCodeSize(p)
+		}
+	}
+	return sizes, nil
+}
+
+// SortedSizeAttribution returns the same result as SizeAttribution but as a
+// list sorted by size, with the largest package first. Packages with the same
+// size are sorted by name.
+func (f *GoFile) SortedSizeAttribution() ([]PackageSize, error) {
+	sizes, err := f.SizeAttribution()
+	if err != nil {
+		return nil, err
+	}
+	return sortPackageSizes(sizes), nil
+}
+
+// SourceDirsByClass returns the distinct source directories of the packages
+// for each package class. The directories are sorted. This gives an overview
+// of where the code in the binary comes from and can be used to spot
+// misclassified packages, for example a third-party path classified as part
+// of the main module.
+func (f *GoFile) SourceDirsByClass() (map[PackageClass][]string, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make(map[PackageClass][]string)
+	for class, pkgs := range map[PackageClass][]*Package{
+		ClassSTD:       f.stdPkgs,
+		ClassGenerated: f.generated,
+		ClassMain:      f.pkgs,
+		ClassVendor:    f.vendors,
+		ClassUnknown:   f.unknown,
+	} {
+		if d := packageDirs(pkgs); len(d) > 0 {
+			dirs[class] = d
+		}
+	}
+	return dirs, nil
+}
+
+// PackageCounts returns the number of packages in each package class. All
+// the classes are included, also the ones without any packages. The total
+// number of packages is the sum of the counts.
+func (f *GoFile) PackageCounts() (map[PackageClass]int, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+	return map[PackageClass]int{
+		ClassSTD:       len(f.stdPkgs),
+		ClassGenerated: len(f.generated),
+		ClassMain:      len(f.pkgs),
+		ClassVendor:    len(f.vendors),
+		ClassUnknown:   len(f.unknown),
+	}, nil
+}
+
+// packageDirs returns the sorted distinct non-empty file paths of the
+// packages.
+func packageDirs(pkgs []*Package) []string {
+	var dirs []string
+	for _, p := range pkgs {
+		if p.Filepath != "" {
+			dirs = append(dirs, p.Filepath)
+		}
+	}
+	sort.Strings(dirs)
+	return slices.Compact(dirs)
+}
+
+func packageCodeSize(p *Package) uint64 {
+	var size uint64
+	for _, fn := range p.Functions {
+		size += fn.End - fn.Offset
+	}
+	for _, m := range p.Methods {
+		size += m.End - m.Offset
+	}
+	return size
+}
+
+func sortPackageSizes(sizes map[string]uint64) []PackageSize {
+	list := make([]PackageSize, 0, len(sizes))
+	for name, size := range sizes {
+		list = append(list, PackageSize{Name: name, Size: size})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Size != list[j].Size {
+			return list[i].Size > list[j].Size
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// resolveImportPath returns the import path for the package name from the
+// set of known package paths.
+func resolveImportPath(name string, paths map[string]struct{}) string {
+	if _, ok := paths[name]; ok || name == "" {
+		return name
+	}
+	var match string
+	for p := range paths {
+		if !strings.HasSuffix(p, "/"+name) {
+			continue
+		}
+		if match != "" {
+			// Ambiguous, keep the name.
+			return name
+		}
+		match = p
+	}
+	if match == "" {
+		return name
+	}
+	return match
 }
 
 // GetSourceFiles returns a slice of source files within the package.
@@ -50,7 +246,7 @@ func (f *GoFile) GetSourceFiles(p *Package) []*SourceFile {
 	getSourceFile := func(fileName string) *SourceFile {
 		sf, ok := tmp[fileName]
 		if !ok {
-			return &SourceFile{Name: path.Base(fileName)}
+			return &SourceFile{Name: path.Base(fileName), FullPath: fileName}
 		}
 		return sf
 	}
@@ -87,6 +283,9 @@ func (f *GoFile) GetSourceFiles(p *Package) []*SourceFile {
 
 	// Sort the file list.
 	sort.Slice(files, func(i, j int) bool {
+		if files[i].Name == files[j].Name {
+			return files[i].FullPath < files[j].FullPath
+		}
 		return files[i].Name < files[j].Name
 	})
 	return files
@@ -108,6 +307,22 @@ const (
 	ClassGenerated
 )
 
+// String returns the name of the class.
+func (c PackageClass) String() string {
+	switch c {
+	case ClassSTD:
+		return "std"
+	case ClassMain:
+		return "main"
+	case ClassVendor:
+		return "vendor"
+	case ClassGenerated:
+		return "generated"
+	default:
+		return "unknown"
+	}
+}
+
 // PackageClassifier classifies a package to the correct class type.
 type PackageClassifier interface {
 	// Classify performs the classification.
@@ -132,32 +347,40 @@ type PathPackageClassifier struct {
 
 // Classify returns the package class for the package.
 func (c *PathPackageClassifier) Classify(pkg *Package) PackageClass {
+	class, _ := c.ClassifyWithReason(pkg)
+	return class
+}
+
+// ClassifyWithReason returns the package class for the package and a short
+// explanation of why the class was picked. The explanation is meant for
+// debugging misclassified packages.
+func (c *PathPackageClassifier) ClassifyWithReason(pkg *Package) (PackageClass, string) {
 	if pkg.Name == "type" || strings.HasPrefix(pkg.Name, "type..") {
-		return ClassGenerated
+		return ClassGenerated, "package name is a compiler generated type package"
 	}
 
 	if IsStandardLibrary(pkg.Name) {
-		return ClassSTD
+		return ClassSTD, "package name is in the standard library"
 	}
 
 	if isGeneratedPackage(pkg) {
-		return ClassGenerated
+		return ClassGenerated, "package has no source file path or an autogenerated one"
 	}
 
 	// Detect internal/golang.org/x/net/http2/hpack type/
 	tmp := strings.Split(pkg.Name, "/golang.org")[0]
 	if len(tmp) < len(pkg.Name) && IsStandardLibrary(tmp) {
-		return ClassSTD
+		return ClassSTD, "package is golang.org/x code vendored in the standard library package " + tmp
 	}
 
 	// cgo packages.
 	if strings.HasPrefix(pkg.Name, "_cgo_") || strings.HasPrefix(pkg.Name, "x_cgo_") {
-		return ClassSTD
+		return ClassSTD, "package name has a cgo prefix"
 	}
 
 	// If the file path contains "@v", it's a 3rd party package.
 	if strings.Contains(pkg.Filepath, "@v") {
-		return ClassVendor
+		return ClassVendor, "file path contains @v"
 	}
 
 	parentFolder := path.Dir(pkg.Filepath)
@@ -165,54 +388,54 @@ func (c *PathPackageClassifier) Classify(pkg *Package) PackageClass {
 	if strings.HasPrefix(pkg.Filepath, c.mainFilepath+"/vendor/") ||
 		strings.HasPrefix(pkg.Filepath, path.Dir(c.mainFilepath)+"/vendor/") ||
 		strings.HasPrefix(pkg.Filepath, path.Dir(path.Dir(c.mainFilepath))+"/vendor/") {
-		return ClassVendor
+		return ClassVendor, "file path is in a vendor folder of the main package"
 	}
 
 	for _, folder := range c.mainFolders {
 		if parentFolder == folder {
-			return ClassMain
+			return ClassMain, "file path is next to the main package"
 		}
 	}
 
 	// If the package name starts with "vendor/" assume it's a vendor package.
 	if strings.HasPrefix(pkg.Name, "vendor/") {
-		return ClassVendor
+		return ClassVendor, "package name starts with vendor/"
 	}
 
 	// Start with repo url.and has it in the path.
 	for _, url := range knownRepos {
 		if strings.HasPrefix(pkg.Name, url) && strings.Contains(pkg.Filepath, url) {
-			return ClassVendor
+			return ClassVendor, "package name and file path contain the known repository " + url
 		}
 	}
 
 	// If the path does not contain the "vendor" in a path but has the main package folder name, assume part of main.
 	if !strings.Contains(pkg.Filepath, "vendor/") &&
 		(path.Base(path.Dir(pkg.Filepath)) == path.Base(c.mainFilepath)) {
-		return ClassMain
+		return ClassMain, "parent folder has the same name as the main package folder"
 	}
 	// Special case for entry point package.
 	if pkg.Name == "" && path.Base(pkg.Filepath) == "runtime" {
-		return ClassSTD
+		return ClassSTD, "package is the runtime entry point"
 	}
 
 	// At this point, if it's a subpackage of the main assume main.
 	if strings.HasPrefix(pkg.Filepath, c.mainFilepath) {
-		return ClassMain
+		return ClassMain, "file path is below the main package"
 	}
 
 	// Check if it's the main parent package.
 	if pkg.Name != "" && (!strings.Contains(pkg.Name, "/") && strings.Contains(c.mainFilepath, pkg.Name)) {
-		return ClassMain
+		return ClassMain, "package name is part of the main package file path"
 	}
 
 	// At this point, if the main package has a file path of "command-line-arguments" and we haven't figured out
 	// what class it is. We assume it is part of the main package.
 	if c.mainFilepath == "command-line-arguments" {
-		return ClassMain
+		return ClassMain, "main package was built from command-line-arguments"
 	}
 
-	return ClassUnknown
+	return ClassUnknown, "no rule matched"
 }
 
 // IsStandardLibrary returns true if the package is from the standard library.
@@ -240,6 +463,40 @@ func isGeneratedPackage(pkg *Package) bool {
 	return false
 }
 
+// guessMainPackage returns the package most likely to be the main package
+// when the binary doesn't have one. The non-standard library package with the
+// most functions is picked. Ties are broken by the shortest file path. If no
+// candidate is found, nil is returned.
+func guessMainPackage(packages map[string]*Package) (string, *Package) {
+	var name string
+	var best *Package
+	for n, p := range packages {
+		if n == "" || p.Filepath == "" || IsStandardLibrary(n) ||
+			n == "type" || strings.HasPrefix(n, "type..") ||
+			strings.HasPrefix(n, "_cgo_") || strings.HasPrefix(n, "x_cgo_") {
+			continue
+		}
+		if p.Filepath == "<autogenerated>" || strings.Contains(p.Filepath, "@v") {
+			continue
+		}
+
+		size := len(p.Functions) + len(p.Methods)
+		if best != nil {
+			bestSize := len(best.Functions) + len(best.Methods)
+			switch {
+			case size < bestSize:
+				continue
+			case size == bestSize && len(p.Filepath) > len(best.Filepath):
+				continue
+			case size == bestSize && len(p.Filepath) == len(best.Filepath) && n > name:
+				continue
+			}
+		}
+		name, best = n, p
+	}
+	return name, best
+}
+
 // NewModPackageClassifier creates a new mod based package classifier.
 func NewModPackageClassifier(buildInfo *debug.BuildInfo) *ModPackageClassifier {
 	return &ModPackageClassifier{modInfo: buildInfo}
@@ -252,23 +509,31 @@ type ModPackageClassifier struct {
 
 // Classify performs the classification.
 func (c *ModPackageClassifier) Classify(pkg *Package) PackageClass {
+	class, _ := c.ClassifyWithReason(pkg)
+	return class
+}
+
+// ClassifyWithReason performs the classification and returns a short
+// explanation of why the class was picked. The explanation is meant for
+// debugging misclassified packages.
+func (c *ModPackageClassifier) ClassifyWithReason(pkg *Package) (PackageClass, string) {
 	if IsStandardLibrary(pkg.Name) {
-		return ClassSTD
+		return ClassSTD, "package name is in the standard library"
 	}
 
 	// Main package.
 	if pkg.Name == "main" {
-		return ClassMain
+		return ClassMain, "package name is main"
 	}
 
 	// If the build info path is not an empty string and the package has the path as a substring, it is part of the main module.
 	if c.modInfo.Path != "" && (strings.HasPrefix(pkg.Filepath, c.modInfo.Path) || strings.HasPrefix(pkg.Name, c.modInfo.Path)) {
-		return ClassMain
+		return ClassMain, "package starts with the main package path " + c.modInfo.Path
 	}
 
 	// If the main module path is not an empty string and the package has the path as a substring, it is part of the main module.
 	if c.modInfo.Main.Path != "" && (strings.HasPrefix(pkg.Filepath, c.modInfo.Main.Path) || strings.HasPrefix(pkg.Name, c.modInfo.Main.Path)) {
-		return ClassMain
+		return ClassMain, "package starts with the main module path " + c.modInfo.Main.Path
 	}
 
 	// Check if the package is a direct dependency.
@@ -277,21 +542,21 @@ func (c *ModPackageClassifier) Classify(pkg *Package) PackageClass {
 			// If the vendor it matched on has the version of "(devel)", it is treated as part of
 			// the main module.
 			if dep.Version == "(devel)" {
-				return ClassMain
+				return ClassMain, "package starts with the dependency " + dep.Path + " with version (devel)"
 			}
-			return ClassVendor
+			return ClassVendor, "package starts with the dependency " + dep.Path
 		}
 	}
 
 	if isGeneratedPackage(pkg) {
-		return ClassGenerated
+		return ClassGenerated, "package has no source file path or an autogenerated one"
 	}
 
 	// cgo packages.
 	if strings.HasPrefix(pkg.Name, "_cgo_") || strings.HasPrefix(pkg.Name, "x_cgo_") {
-		return ClassSTD
+		return ClassSTD, "package name has a cgo prefix"
 	}
 
 	// Only indirect dependencies should be left.
-	return ClassVendor
+	return ClassVendor, "package is not in the standard library or the main module, assumed to be an indirect dependency"
 }
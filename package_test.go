@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"runtime/debug"
 	"sort"
 	"testing"
 
@@ -359,6 +360,8 @@ func TestGetSourceFiles(t *testing.T) {
 	// Test
 
 	sf := f.GetSourceFiles(pkg)
+	r.Len(sf, 1)
+	r.Equal("/build/target.go", sf[0].FullPath)
 
 	buf := &bytes.Buffer{}
 	buf.WriteString(fmt.Sprintf("Package %s: %s\n", pkg.Name, pkg.Filepath))
@@ -370,6 +373,105 @@ func TestGetSourceFiles(t *testing.T) {
 	r.Equal(expected, buf.String())
 }
 
+func TestGuessMainPackage(t *testing.T) {
+	fns := func(n int) []*Function {
+		return make([]*Function, n)
+	}
+
+	t.Run("largest package is picked", func(t *testing.T) {
+		assert := assert.New(t)
+		pkgs := map[string]*Package{
+			"runtime":                        {Filepath: "/usr/local/go/src/runtime", Functions: fns(100)},
+			"github.com/a/lib":               {Filepath: "/build/lib", Functions: fns(5)},
+			"github.com/a/lib/internal/util": {Filepath: "/build/lib/internal/util", Functions: fns(2)},
+			"github.com/x/dep":               {Filepath: "/go/pkg/mod/github.com/x/dep@v1.0.0", Functions: fns(50)},
+			"type":                           {Filepath: "<autogenerated>", Functions: fns(10)},
+		}
+		name, p := guessMainPackage(pkgs)
+		assert.Equal("github.com/a/lib", name)
+		assert.Same(pkgs["github.com/a/lib"], p)
+	})
+
+	t.Run("ties are broken by the shortest path", func(t *testing.T) {
+		assert := assert.New(t)
+		pkgs := map[string]*Package{
+			"github.com/a/lib/sub": {Filepath: "/build/lib/sub", Functions: fns(3)},
+			"github.com/a/lib":     {Filepath: "/build/lib", Functions: fns(3)},
+		}
+		name, _ := guessMainPackage(pkgs)
+		assert.Equal("github.com/a/lib", name)
+	})
+
+	t.Run("no candidate", func(t *testing.T) {
+		pkgs := map[string]*Package{
+			"runtime": {Filepath: "/usr/local/go/src/runtime", Functions: fns(100)},
+		}
+		_, p := guessMainPackage(pkgs)
+		assert.Nil(t, p)
+	})
+}
+
+func TestResolveImportPath(t *testing.T) {
+	paths := map[string]struct{}{
+		"main":                      {},
+		"github.com/a/project/util": {},
+		"github.com/a/project/log":  {},
+		"github.com/b/other/log":    {},
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"main", "main"},
+		{"util", "github.com/a/project/util"},
+		{"project/util", "github.com/a/project/util"},
+		{"log", "log"},
+		{"unknown", "unknown"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, resolveImportPath(test.name, paths))
+		})
+	}
+}
+
+func TestSizeAttribution(t *testing.T) {
+	r := require.New(t)
+
+	f := &GoFile{
+		stdPkgs: []*Package{{
+			Name:      "runtime",
+			Functions: []*Function{{Offset: 0x1000, End: 0x1100}, {Offset: 0x1100, End: 0x1180}},
+		}},
+		pkgs: []*Package{{
+			Name:      "main",
+			Functions: []*Function{{Offset: 0x2000, End: 0x2010}},
+			Methods:   []*Method{{Function: &Function{Offset: 0x2010, End: 0x2030}}},
+		}},
+		vendors: []*Package{{
+			Name:      "github.com/foo/bar",
+			Functions: []*Function{{Offset: 0x3000, End: 0x3030}},
+		}},
+	}
+	// The packages are already set.
+	f.initPackagesDone = true
+
+	sizes, err := f.SizeAttribution()
+	r.NoError(err)
+	r.Equal(map[string]uint64{"runtime": 0x180, "main": 0x30, "github.com/foo/bar": 0x30}, sizes)
+
+	sorted, err := f.SortedSizeAttribution()
+	r.NoError(err)
+	r.Equal([]PackageSize{
+		{Name: "runtime", Size: 0x180},
+		{Name: "github.com/foo/bar", Size: 0x30},
+		{Name: "main", Size: 0x30},
+	}, sorted)
+}
+
 func TestAthenaCase(t *testing.T) {
 	tests := []struct {
 		pkgsName string
@@ -609,3 +711,165 @@ func TestModInfoPackageClassification(t *testing.T) {
 		a.Equal(expected, pkgs[i].Name, fmt.Sprintf("Index %d is incorrect.", i))
 	}
 }
+
+func TestClassifyWithReason(t *testing.T) {
+	pc := NewPathPackageClassifier("/home/user/app")
+	for _, test := range []struct {
+		pkg    *Package
+		class  PackageClass
+		reason string
+	}{
+		{&Package{Name: "fmt", Filepath: "/usr/local/go/src/fmt"}, ClassSTD, "standard library"},
+		{&Package{Name: "github.com/a/b", Filepath: "/go/pkg/mod/github.com/a/b@v1.0.0"}, ClassVendor, "@v"},
+		{&Package{Name: "main", Filepath: "/home/user/app"}, ClassMain, "next to the main package"},
+		{&Package{Name: "other", Filepath: "/opt/other/pkg"}, ClassUnknown, "no rule matched"},
+	} {
+		class, reason := pc.ClassifyWithReason(test.pkg)
+		assert.Equal(t, test.class, class, test.pkg.Name)
+		assert.Contains(t, reason, test.reason, test.pkg.Name)
+		assert.Equal(t, class, pc.Classify(test.pkg), test.pkg.Name)
+	}
+
+	mod := NewModPackageClassifier(&debug.BuildInfo{
+		Path: "example.com/app",
+		Main: debug.Module{Path: "example.com/app"},
+		Deps: []*debug.Module{
+			{Path: "github.com/a/b", Version: "v1.0.0"},
+			{Path: "github.com/a/local", Version: "(devel)"},
+		},
+	})
+	for _, test := range []struct {
+		pkg    *Package
+		class  PackageClass
+		reason string
+	}{
+		{&Package{Name: "fmt"}, ClassSTD, "standard library"},
+		{&Package{Name: "example.com/app/internal"}, ClassMain, "example.com/app"},
+		{&Package{Name: "github.com/a/b/c"}, ClassVendor, "dependency github.com/a/b"},
+		{&Package{Name: "github.com/a/local"}, ClassMain, "(devel)"},
+		{&Package{Name: "github.com/x/y", Filepath: "/go/src/github.com/x/y"}, ClassVendor, "indirect dependency"},
+	} {
+		class, reason := mod.ClassifyWithReason(test.pkg)
+		assert.Equal(t, test.class, class, test.pkg.Name)
+		assert.Contains(t, reason, test.reason, test.pkg.Name)
+		assert.Equal(t, class, mod.Classify(test.pkg), test.pkg.Name)
+	}
+}
+
+func TestSourceDirsByClass(t *testing.T) {
+	r := require.New(t)
+
+	f := &GoFile{
+		stdPkgs: []*Package{
+			{Name: "runtime", Filepath: "/usr/local/go/src/runtime"},
+			{Name: "fmt", Filepath: "/usr/local/go/src/fmt"},
+		},
+		pkgs: []*Package{
+			{Name: "main", Filepath: "/home/user/app"},
+			{Name: "main", Filepath: "/home/user/app"},
+			{Name: "github.com/user/app/sub", Filepath: "/home/user/app/sub"},
+		},
+		generated: []*Package{{Name: "type"}},
+	}
+	// The packages are already set.
+	f.initPackagesDone = true
+
+	dirs, err := f.SourceDirsByClass()
+	r.NoError(err)
+	r.Equal(map[PackageClass][]string{
+		ClassSTD:  {"/usr/local/go/src/fmt", "/usr/local/go/src/runtime"},
+		ClassMain: {"/home/user/app", "/home/user/app/sub"},
+	}, dirs)
+}
+
+func TestPackageCounts(t *testing.T) {
+	r := require.New(t)
+
+	f := &GoFile{
+		stdPkgs:   []*Package{{Name: "runtime"}, {Name: "fmt"}},
+		pkgs:      []*Package{{Name: "main"}},
+		generated: []*Package{{Name: "type"}},
+	}
+	// The packages are already set.
+	f.initPackagesDone = true
+
+	counts, err := f.PackageCounts()
+	r.NoError(err)
+	r.Equal(map[PackageClass]int{
+		ClassSTD:       2,
+		ClassGenerated: 1,
+		ClassMain:      1,
+		ClassVendor:    0,
+		ClassUnknown:   0,
+	}, counts)
+}
+
+func TestGoFileClassifyPackage(t *testing.T) {
+	r := require.New(t)
+
+	f := &GoFile{classifier: NewPathPackageClassifier("/home/user/app")}
+	// The packages are already set.
+	f.initPackagesDone = true
+
+	for _, test := range []struct {
+		name     string
+		filepath string
+		class    PackageClass
+	}{
+		{"fmt", "/usr/local/go/src/fmt", ClassSTD},
+		{"main", "/home/user/app", ClassMain},
+		{"github.com/user/app/sub", "/home/user/app/sub", ClassMain},
+		{"github.com/foo/bar", "/home/user/go/pkg/mod/github.com/foo/bar@v1.0.0", ClassVendor},
+		{"type", "", ClassGenerated},
+	} {
+		class, err := f.ClassifyPackage(test.name, test.filepath)
+		r.NoError(err)
+		r.Equal(test.class, class, test.name)
+	}
+}
+
+type constClassifier PackageClass
+
+func (c constClassifier) Classify(*Package) PackageClass {
+	return PackageClass(c)
+}
+
+func TestSetPackageClassifier(t *testing.T) {
+	pkgs := map[string]*Package{
+		"github.com/a/lib": {Filepath: "/build/lib"},
+	}
+
+	f := new(GoFile)
+	c, err := f.packageClassifier(pkgs)
+	require.NoError(t, err)
+	assert.IsType(t, &PathPackageClassifier{}, c, "no module information")
+
+	require.NoError(t, f.SetPackageClassifier(constClassifier(ClassVendor)))
+	c, err = f.packageClassifier(map[string]*Package{})
+	require.NoError(t, err, "no main package needed")
+	assert.Equal(t, constClassifier(ClassVendor), c)
+
+	// The packages are already set.
+	f.initPackagesDone = true
+	assert.ErrorIs(t, f.SetPackageClassifier(constClassifier(ClassMain)), ErrPackagesInitialized)
+}
+
+func TestPackageWithoutMethodWrappers(t *testing.T) {
+	call := &Function{Name: "call"}
+	name := &Method{Receiver: "T", Function: &Function{Name: "Name"}}
+	p := &Package{
+		Name:      "main",
+		Functions: []*Function{call},
+		Methods: []*Method{
+			name,
+			{Receiver: "T", Function: &Function{Name: "Name-fm"}},
+			{Receiver: "(*T)", Function: &Function{Name: "Value", Offset: 0x1000, lines: fileLineTable{0x1000: "<autogenerated>"}}},
+		},
+	}
+
+	c := p.WithoutMethodWrappers()
+	assert.Equal(t, "main", c.Name)
+	assert.Equal(t, []*Function{call}, c.Functions)
+	assert.Equal(t, []*Method{name}, c.Methods)
+	assert.Len(t, p.Methods, 3, "the package is not modified")
+}
@@ -20,6 +20,9 @@ package gore
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
 )
 
 // keep sync with debug/gosym/pclntab.go
@@ -30,8 +33,42 @@ const (
 	gopclntab120magic uint32 = 0xfffffff1
 )
 
-// searchSectionForTab looks for the PCLN table within the section.
-func searchSectionForTab(secData []byte, order binary.ByteOrder) ([]byte, error) {
+// pclntabVersion returns the Go version family that uses the pclntab magic.
+func pclntabVersion(magic uint32) (string, bool) {
+	switch magic {
+	case gopclntab12magic:
+		return "1.2", true
+	case gopclntab116magic:
+		return "1.16", true
+	case gopclntab118magic:
+		return "1.18", true
+	case gopclntab120magic:
+		return "1.20", true
+	default:
+		return "", false
+	}
+}
+
+// searchSectionForTab looks for the PCLN table within the section. If the
+// byte order is nil because the architecture is ambiguous, the table is
+// searched for in little-endian and then in big-endian. The byte order of
+// the found table is returned.
+func searchSectionForTab(secData []byte, order binary.ByteOrder) ([]byte, binary.ByteOrder, error) {
+	orders := []binary.ByteOrder{order}
+	if order == nil {
+		orders = []binary.ByteOrder{binary.LittleEndian, binary.BigEndian}
+	}
+	for _, o := range orders {
+		if tab := searchSectionForTabOrder(secData, o); tab != nil {
+			return tab, o, nil
+		}
+	}
+	return nil, nil, ErrNoPCLNTab
+}
+
+// searchSectionForTabOrder looks for the PCLN table stored in the byte order
+// within the section. If the table isn't found, nil is returned.
+func searchSectionForTabOrder(secData []byte, order binary.ByteOrder) []byte {
 	// First check for the current magic used. If this fails, it could be
 	// an older version. So check for the old header.
 MagicLoop:
@@ -57,10 +94,514 @@ MagicLoop:
 					continue
 				}
 				// Header match
-				return secData[off:], nil
+				return secData[off:]
 			}
 			break
 		}
 	}
-	return nil, ErrNoPCLNTab
+	return nil
+}
+
+// Function metadata indexes used by the runtime. These values have been stable
+// since Go 1.16 which is the oldest pclntab format parsed by pclnTable.
+const (
+	pcdataInlTreeIndex = 2
+	funcdataInlTree    = 3
+)
+
+// pclnTable is a parser for the function metadata stored in the pclntab for
+// binaries compiled with Go 1.16 and later. It gives access to information
+// that is not exposed by the debug/gosym package, for example the pcdata and
+// funcdata tables.
+type pclnTable struct {
+	data      []byte
+	order     binary.ByteOrder
+	magic     uint32
+	quantum   uint64
+	ptrSize   int
+	nfunc     int
+	textStart uint64
+	// textSections is the text section map. It is only needed for
+	// binaries with more than one text section.
+	textSections []TextSection
+
+	funcnametab []byte
+	cutab       []byte
+	filetab     []byte
+	pctab       []byte
+	// functab is the function lookup table. It is also the base for the
+	// offsets to the _func structures.
+	functab []byte
+}
+
+// funcInfo holds the information from the runtime's _func structure.
+type funcInfo struct {
+	entry     uint64
+	nameOff   int32
+	args      int32
+	pcsp      uint32
+	pcfile    uint32
+	pcln      uint32
+	cuOffset  uint32
+	startLine int32
+	funcID    uint8
+	flag      uint8
+	pcdata    []uint32
+	// funcdata holds the resolved addresses for the funcdata. A zero value
+	// means the function does not have the funcdata.
+	funcdata []uint64
+}
+
+// inlinedCall is an entry in a function's inline tree.
+type inlinedCall struct {
+	funcID   uint8
+	nameOff  int32
+	parentPc int32
+}
+
+// newPclnTable parses the header of the pclntab. The runtimeText should be
+// the address of "runtime.text" which is used as the base for the function
+// addresses.
+func newPclnTable(data []byte, order binary.ByteOrder, runtimeText uint64) (*pclnTable, error) {
+	if len(data) < 8 {
+		return nil, ErrNoPCLNTab
+	}
+	t := &pclnTable{
+		data:    data,
+		order:   order,
+		magic:   order.Uint32(data),
+		quantum: uint64(data[6]),
+		ptrSize: int(data[7]),
+	}
+	if t.ptrSize != intSize32 && t.ptrSize != intSize64 {
+		return nil, fmt.Errorf("invalid pointer size in pclntab header: %d", t.ptrSize)
+	}
+
+	// Offsets to the header fields.
+	var fields int
+	switch t.magic {
+	case gopclntab120magic, gopclntab118magic:
+		// nfunc, nfiles, textStart, funcnameOffset, cuOffset, filetabOffset, pctabOffset, pclnOffset
+		fields = 8
+	case gopclntab116magic:
+		// nfunc, nfiles, funcnameOffset, cuOffset, filetabOffset, pctabOffset, pclnOffset
+		fields = 7
+	default:
+		return nil, ErrUnsupportedPCLNTabVersion
+	}
+	if len(data) < 8+fields*t.ptrSize {
+		return nil, ErrNoPCLNTab
+	}
+
+	field := func(n int) uint64 {
+		return t.uintptr(data[8+n*t.ptrSize:])
+	}
+	section := func(n int) ([]byte, error) {
+		off := field(n)
+		if off > uint64(len(data)) {
+			return nil, fmt.Errorf("pclntab header field %d is out of bounds: 0x%x", n, off)
+		}
+		return data[off:], nil
+	}
+
+	t.nfunc = int(field(0))
+	t.textStart = runtimeText
+
+	var err error
+	i := 2
+	if t.magic != gopclntab116magic {
+		// Skip the textStart field. It may be unrelocated so runtimeText is used
+		// instead. This is the same approach taken by debug/gosym.
+		i++
+	}
+	if t.funcnametab, err = section(i); err != nil {
+		return nil, err
+	}
+	if t.cutab, err = section(i + 1); err != nil {
+		return nil, err
+	}
+	if t.filetab, err = section(i + 2); err != nil {
+		return nil, err
+	}
+	if t.pctab, err = section(i + 3); err != nil {
+		return nil, err
+	}
+	if t.functab, err = section(i + 4); err != nil {
+		return nil, err
+	}
+	if len(t.functab) < (t.nfunc+1)*t.functabFieldSize()*2 {
+		return nil, fmt.Errorf("pclntab function table is truncated")
+	}
+	return t, nil
+}
+
+func (t *pclnTable) uintptr(b []byte) uint64 {
+	if t.ptrSize == intSize32 {
+		return uint64(t.order.Uint32(b))
+	}
+	return t.order.Uint64(b)
+}
+
+func (t *pclnTable) functabFieldSize() int {
+	if t.magic == gopclntab116magic {
+		return t.ptrSize
+	}
+	return 4
+}
+
+// funcEntry returns the entry address of the i:th function in the functab.
+// The entry for index nfunc is the end of the last function.
+func (t *pclnTable) funcEntry(i int) uint64 {
+	sz := t.functabFieldSize()
+	b := t.functab[2*i*sz:]
+	if t.magic == gopclntab116magic {
+		return t.uintptr(b)
+	}
+	return t.textAddr(t.order.Uint32(b))
+}
+
+// textAddr returns the address for the offset from the start of the text.
+// This is the same as the runtime's moduledata.textAddr method.
+func (t *pclnTable) textAddr(off32 uint32) uint64 {
+	off := uint64(off32)
+	if len(t.textSections) > 1 {
+		for i, sect := range t.textSections {
+			// The end of the last section is included since it is used in
+			// the function table as the end of the last function.
+			end := sect.Offset + sect.Length
+			if off >= sect.Offset && off < end || (i == len(t.textSections)-1 && off == end) {
+				return sect.Address + off - sect.Offset
+			}
+		}
+	}
+	return t.textStart + off
+}
+
+// contiguousText returns a copy of the table data where the offsets of the
+// functions from the start of the text are rewritten so they are the
+// distance to the address of the function in its text section. debug/gosym
+// adds the offsets to the start of the text without looking at the text
+// section map, so the copy makes it resolve the real addresses in binaries
+// with more than one text section. Tables from before Go 1.18 store the
+// addresses and are returned unchanged.
+func (t *pclnTable) contiguousText() ([]byte, error) {
+	if len(t.textSections) < 2 || t.magic == gopclntab116magic {
+		return t.data, nil
+	}
+	data := make([]byte, len(t.data))
+	copy(data, t.data)
+	functab := data[len(data)-len(t.functab):]
+	rewrite := func(b []byte) error {
+		addr := t.textAddr(t.order.Uint32(b))
+		if addr < t.textStart || addr-t.textStart > math.MaxUint32 {
+			return fmt.Errorf("text address 0x%x can't be stored as an offset", addr)
+		}
+		t.order.PutUint32(b, uint32(addr-t.textStart))
+		return nil
+	}
+	for i := 0; i <= t.nfunc; i++ {
+		// The _func structure starts with the offset of the entry.
+		if i < t.nfunc {
+			off := t.funcOff(i)
+			if off+4 > uint64(len(functab)) {
+				return nil, fmt.Errorf("function %d has an out of bounds offset: 0x%x", i, off)
+			}
+			if err := rewrite(functab[off:]); err != nil {
+				return nil, err
+			}
+		}
+		if err := rewrite(functab[2*i*4:]); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// funcOff returns the offset of the i:th function's _func structure.
+func (t *pclnTable) funcOff(i int) uint64 {
+	sz := t.functabFieldSize()
+	b := t.functab[(2*i+1)*sz:]
+	if t.magic == gopclntab116magic {
+		return t.uintptr(b)
+	}
+	return uint64(t.order.Uint32(b))
+}
+
+// findFunc returns the index of the function containing the pc.
+func (t *pclnTable) findFunc(pc uint64) (int, bool) {
+	if t.nfunc == 0 || pc < t.funcEntry(0) || pc >= t.funcEntry(t.nfunc) {
+		return 0, false
+	}
+	i := sort.Search(t.nfunc, func(i int) bool {
+		return t.funcEntry(i) > pc
+	})
+	return i - 1, true
+}
+
+// funcInfo parses the _func structure for the i:th function. The goFunc value
+// is the address of the "go:func.*" symbol. It's only used for Go 1.18 and
+// later and only needed if the funcdata is used.
+func (t *pclnTable) funcInfo(i int, goFunc uint64) (*funcInfo, error) {
+	off := t.funcOff(i)
+	if off >= uint64(len(t.functab)) {
+		return nil, fmt.Errorf("function %d has an out of bounds offset: 0x%x", i, off)
+	}
+	b := t.functab[off:]
+
+	fi := &funcInfo{}
+	var pos int
+	switch t.magic {
+	case gopclntab116magic:
+		// Go 1.16 and 1.17.
+		if len(b) < t.ptrSize+36 {
+			return nil, fmt.Errorf("function %d is truncated", i)
+		}
+		fi.entry = t.uintptr(b)
+		pos = t.ptrSize
+	default:
+		if len(b) < 44 {
+			return nil, fmt.Errorf("function %d is truncated", i)
+		}
+		fi.entry = t.textAddr(t.order.Uint32(b))
+		pos = 4
+	}
+	fi.nameOff = int32(t.order.Uint32(b[pos:]))
+	fi.args = int32(t.order.Uint32(b[pos+4:]))
+	fi.pcsp = t.order.Uint32(b[pos+12:])
+	fi.pcfile = t.order.Uint32(b[pos+16:])
+	fi.pcln = t.order.Uint32(b[pos+20:])
+	npcdata := int(t.order.Uint32(b[pos+24:]))
+	fi.cuOffset = t.order.Uint32(b[pos+28:])
+	pos += 32
+	if t.magic == gopclntab120magic {
+		fi.startLine = int32(t.order.Uint32(b[pos:]))
+		pos += 4
+	}
+	fi.funcID = b[pos]
+	fi.flag = b[pos+1]
+	nfuncdata := int(b[pos+3])
+	pos += 4
+
+	if len(b) < pos+npcdata*4 {
+		return nil, fmt.Errorf("pcdata for function %d is truncated", i)
+	}
+	fi.pcdata = make([]uint32, npcdata)
+	for j := range fi.pcdata {
+		fi.pcdata[j] = t.order.Uint32(b[pos+j*4:])
+	}
+	pos += npcdata * 4
+
+	fi.funcdata = make([]uint64, nfuncdata)
+	if t.magic == gopclntab116magic {
+		// The funcdata are pointers aligned to the pointer size.
+		if t.ptrSize == intSize64 && pos%8 != 0 {
+			pos += 4
+		}
+		if len(b) < pos+nfuncdata*t.ptrSize {
+			return nil, fmt.Errorf("funcdata for function %d is truncated", i)
+		}
+		for j := range fi.funcdata {
+			fi.funcdata[j] = t.uintptr(b[pos+j*t.ptrSize:])
+		}
+		return fi, nil
+	}
+
+	// Go 1.18 and later store the funcdata as offsets from "go:func.*".
+	if len(b) < pos+nfuncdata*4 {
+		return nil, fmt.Errorf("funcdata for function %d is truncated", i)
+	}
+	for j := range fi.funcdata {
+		o := t.order.Uint32(b[pos+j*4:])
+		if o == ^uint32(0) || goFunc == 0 {
+			continue
+		}
+		fi.funcdata[j] = goFunc + uint64(o)
+	}
+	return fi, nil
+}
+
+// funcName returns the function name stored at the offset in the funcnametab.
+func (t *pclnTable) funcName(off int32) string {
+	if off < 0 || int(off) >= len(t.funcnametab) {
+		return ""
+	}
+	b := t.funcnametab[off:]
+	if i := bytes.IndexByte(b, 0); i != -1 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// pcvalue returns the value for the pc from the pc-value table at the
+// offset in the pctab. If the value can not be determined, false is returned.
+func (t *pclnTable) pcvalue(off uint32, entry, pc uint64) (int32, bool) {
+	if off == 0 || uint64(off) >= uint64(len(t.pctab)) {
+		return -1, false
+	}
+	p := t.pctab[off:]
+	val := int32(-1)
+	cur := entry
+	first := true
+	for {
+		var ok bool
+		p, ok = t.step(p, &cur, &val, first)
+		if !ok {
+			return -1, false
+		}
+		first = false
+		if pc < cur {
+			return val, true
+		}
+	}
+}
+
+// pcvalues returns all the value changes in the pc-value table. The callback
+// is called with the start and end of each pc range and the value for it.
+func (t *pclnTable) pcvalues(off uint32, entry uint64, fn func(start, end uint64, val int32)) {
+	if off == 0 || uint64(off) >= uint64(len(t.pctab)) {
+		return
+	}
+	p := t.pctab[off:]
+	val := int32(-1)
+	cur := entry
+	first := true
+	for {
+		start := cur
+		var ok bool
+		p, ok = t.step(p, &cur, &val, first)
+		if !ok {
+			return
+		}
+		first = false
+		fn(start, cur, val)
+	}
+}
+
+// step advances to the next pc, value pair in the pc-value table.
+// Keep in sync with step in runtime/symtab.go.
+func (t *pclnTable) step(p []byte, pc *uint64, val *int32, first bool) ([]byte, bool) {
+	if len(p) == 0 {
+		return nil, false
+	}
+	uvdelta, n := binary.Uvarint(p)
+	if n <= 0 || (uvdelta == 0 && !first) {
+		return nil, false
+	}
+	*val += int32(-(uvdelta & 1) ^ (uvdelta >> 1))
+	p = p[n:]
+	pcdelta, n := binary.Uvarint(p)
+	if n <= 0 {
+		return nil, false
+	}
+	p = p[n:]
+	*pc += pcdelta * t.quantum
+	return p, true
+}
+
+// fileLine returns the source file and line for the pc in the function.
+func (t *pclnTable) fileLine(fi *funcInfo, pc uint64) (string, int) {
+	line, ok := t.pcvalue(fi.pcln, fi.entry, pc)
+	if !ok {
+		return "", 0
+	}
+	fileno, ok := t.pcvalue(fi.pcfile, fi.entry, pc)
+	if !ok {
+		return "", int(line)
+	}
+	return t.fileName(fi.cuOffset, fileno), int(line)
+}
+
+// fileLines calls fn for each pc range in the function that has a source
+// position. The file and line tables are walked in parallel, so the function
+// is only decoded once.
+func (t *pclnTable) fileLines(fi *funcInfo, fn func(file string, line int)) {
+	type fileRange struct {
+		end    uint64
+		fileno int32
+	}
+	var files []fileRange
+	t.pcvalues(fi.pcfile, fi.entry, func(_, end uint64, val int32) {
+		files = append(files, fileRange{end, val})
+	})
+	i := 0
+	t.pcvalues(fi.pcln, fi.entry, func(start, _ uint64, line int32) {
+		for i < len(files) && files[i].end <= start {
+			i++
+		}
+		if i == len(files) || line <= 0 {
+			return
+		}
+		if file := t.fileName(fi.cuOffset, files[i].fileno); file != "" {
+			fn(file, int(line))
+		}
+	})
+}
+
+// maxFrameSize returns the largest stack pointer delta in the pcsp table of
+// the function. This is the size of the stack frame, excluding the arguments
+// and the return address pushed by the caller.
+func (t *pclnTable) maxFrameSize(fi *funcInfo) int {
+	var size int32
+	t.pcvalues(fi.pcsp, fi.entry, func(_, _ uint64, val int32) {
+		size = max(size, val)
+	})
+	return int(size)
+}
+
+// fileName returns the name of the file with the CU local file index.
+func (t *pclnTable) fileName(cuOffset uint32, fileno int32) string {
+	if fileno < 0 {
+		return ""
+	}
+	i := (uint64(cuOffset) + uint64(fileno)) * 4
+	if i+4 > uint64(len(t.cutab)) {
+		return ""
+	}
+	off := t.order.Uint32(t.cutab[i:])
+	if off == ^uint32(0) || uint64(off) >= uint64(len(t.filetab)) {
+		return ""
+	}
+	b := t.filetab[off:]
+	if n := bytes.IndexByte(b, 0); n != -1 {
+		b = b[:n]
+	}
+	return string(b)
+}
+
+// inlinedCallSize returns the size of the runtime's inlinedCall structure.
+func (t *pclnTable) inlinedCallSize() uint64 {
+	if t.magic == gopclntab120magic {
+		return 16
+	}
+	return 20
+}
+
+// parseInlinedCall parses an entry of the inline tree.
+func (t *pclnTable) parseInlinedCall(b []byte) inlinedCall {
+	if t.magic == gopclntab120magic {
+		// Go 1.20 and later:
+		//	funcID    funcID
+		//	_         [3]byte
+		//	nameOff   int32
+		//	parentPc  int32
+		//	startLine int32
+		return inlinedCall{
+			funcID:   b[0],
+			nameOff:  int32(t.order.Uint32(b[4:])),
+			parentPc: int32(t.order.Uint32(b[8:])),
+		}
+	}
+	// Go 1.16 to 1.19:
+	//	parent   int16
+	//	funcID   funcID
+	//	_        byte
+	//	file     int32
+	//	line     int32
+	//	func_    int32
+	//	parentPc int32
+	return inlinedCall{
+		funcID:   b[2],
+		nameOff:  int32(t.order.Uint32(b[12:])),
+		parentPc: int32(t.order.Uint32(b[16:])),
+	}
 }
@@ -18,6 +18,7 @@
 package gore
 
 import (
+	"encoding/binary"
 	"path/filepath"
 	"testing"
 
@@ -47,3 +48,140 @@ func TestGo116PCLNTab(t *testing.T) {
 	}
 
 }
+
+func TestSearchSectionForTab(t *testing.T) {
+	r := require.New(t)
+
+	header := func(order binary.ByteOrder) []byte {
+		sec := make([]byte, 32)
+		order.PutUint32(sec[8:], gopclntab120magic)
+		sec[14] = 1 // pc quantum
+		sec[15] = 8 // pointer size
+		return sec
+	}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		sec := header(order)
+
+		tab, found, err := searchSectionForTab(sec, order)
+		r.NoError(err)
+		r.Equal(order, found)
+		r.Equal(sec[8:], tab)
+
+		tab, found, err = searchSectionForTab(sec, nil)
+		r.NoError(err, "ambiguous byte order")
+		r.Equal(order, found)
+		r.Equal(sec[8:], tab)
+	}
+
+	_, _, err := searchSectionForTab(header(binary.BigEndian), binary.LittleEndian)
+	r.ErrorIs(err, ErrNoPCLNTab, "wrong byte order")
+}
+
+func TestPclnTablePCValue(t *testing.T) {
+	r := require.New(t)
+
+	// Value 10 for [0x1000, 0x1004) and value 12 for [0x1004, 0x100a).
+	tab := &pclnTable{
+		quantum: 1,
+		pctab:   []byte{0x0, 22, 4, 4, 6, 0},
+	}
+
+	val, ok := tab.pcvalue(1, 0x1000, 0x1000)
+	r.True(ok)
+	r.Equal(int32(10), val)
+
+	val, ok = tab.pcvalue(1, 0x1000, 0x1005)
+	r.True(ok)
+	r.Equal(int32(12), val)
+
+	_, ok = tab.pcvalue(1, 0x1000, 0x100a)
+	r.False(ok, "pc is outside the table")
+
+	type pcRange struct {
+		start, end uint64
+		val        int32
+	}
+	var ranges []pcRange
+	tab.pcvalues(1, 0x1000, func(start, end uint64, val int32) {
+		ranges = append(ranges, pcRange{start, end, val})
+	})
+	r.Equal([]pcRange{{0x1000, 0x1004, 10}, {0x1004, 0x100a, 12}}, ranges)
+}
+
+func TestPclnTableFileLines(t *testing.T) {
+	r := require.New(t)
+
+	// The file table has file 0 for [0x1000, 0x100a) and the line table has
+	// line 10 for [0x1000, 0x1004) and line 12 for [0x1004, 0x100a).
+	tab := &pclnTable{
+		order:   binary.LittleEndian,
+		quantum: 1,
+		pctab:   []byte{0x0, 2, 10, 0, 22, 4, 4, 6, 0},
+		cutab:   []byte{0, 0, 0, 0},
+		filetab: []byte("a.go\x00"),
+	}
+	fi := &funcInfo{entry: 0x1000, pcfile: 1, pcln: 4}
+
+	type fileLine struct {
+		file string
+		line int
+	}
+	var lines []fileLine
+	tab.fileLines(fi, func(file string, line int) {
+		lines = append(lines, fileLine{file, line})
+	})
+	r.Equal([]fileLine{{"a.go", 10}, {"a.go", 12}}, lines)
+}
+
+func TestPclnTableMaxFrameSize(t *testing.T) {
+	// The stack pointer delta is 0 for [0x1000, 0x1004), 0x18 for
+	// [0x1004, 0x100e) and 0 for [0x100e, 0x1010).
+	tab := &pclnTable{
+		order:   binary.LittleEndian,
+		quantum: 1,
+		pctab:   []byte{0x0, 2, 4, 0x30, 10, 0x2f, 2, 0},
+	}
+	fi := &funcInfo{entry: 0x1000, pcsp: 1}
+	require.Equal(t, 0x18, tab.maxFrameSize(fi))
+
+	// No pcsp table.
+	require.Equal(t, 0, tab.maxFrameSize(&funcInfo{entry: 0x1000}))
+}
+
+func TestPclntabVersion(t *testing.T) {
+	tests := []struct {
+		magic    uint32
+		expected string
+	}{
+		{gopclntab12magic, "1.2"},
+		{gopclntab116magic, "1.16"},
+		{gopclntab118magic, "1.18"},
+		{gopclntab120magic, "1.20"},
+	}
+	for _, test := range tests {
+		ver, ok := pclntabVersion(test.magic)
+		require.True(t, ok)
+		require.Equal(t, test.expected, ver)
+	}
+
+	_, ok := pclntabVersion(0xdeadbeef)
+	require.False(t, ok)
+}
+
+func TestPclnTableTextAddr(t *testing.T) {
+	r := require.New(t)
+
+	tab := &pclnTable{textStart: 0x401000}
+	r.Equal(uint64(0x401010), tab.textAddr(0x10))
+
+	// The second section is placed with a gap after the first one.
+	tab.textSections = []TextSection{
+		{Offset: 0, Length: 0x1000, Address: 0x401000},
+		{Offset: 0x1000, Length: 0x800, Address: 0x403000},
+	}
+	r.Equal(uint64(0x401010), tab.textAddr(0x10))
+	r.Equal(uint64(0x403010), tab.textAddr(0x1010))
+	// The end of the last section is included.
+	r.Equal(uint64(0x403800), tab.textAddr(0x1800))
+}
@@ -18,18 +18,18 @@
 package gore
 
 import (
-	"cmp"
+	"bytes"
 	"debug/dwarf"
 	"debug/pe"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
-	"slices"
+	"strings"
 	"sync"
 )
 
-func openPE(r io.ReaderAt) (peF *peFile, err error) {
+func openPE(r io.ReaderAt, opts Options) (peF *peFile, err error) {
 	// Parsing by the file by debug/pe can panic if the PE file is malformed.
 	// To prevent a crash, we recover the panic and return it as an error
 	// instead.
@@ -57,18 +57,50 @@ func openPE(r io.ReaderAt) (peF *peFile, err error) {
 		return
 	}
 
-	peF = &peFile{file: f, reader: r, imageBase: imageBase}
+	peF = &peFile{file: f, reader: r, imageBase: imageBase, maxSectionBytes: opts.MaxSectionBytes, ownsReader: !opts.KeepReaderOpen}
 	peF.getsymtab = sync.OnceValues(peF.initSymTab)
+	peF.byteOrder = peF.detectByteOrder()
 	return
 }
 
 var _ fileHandler = (*peFile)(nil)
 
 type peFile struct {
-	file      *pe.File
-	reader    io.ReaderAt
-	imageBase uint64
-	getsymtab func() (map[string]Symbol, error)
+	file            *pe.File
+	reader          io.ReaderAt
+	imageBase       uint64
+	getsymtab       func() (map[string]Symbol, error)
+	maxSectionBytes uint64
+	// ownsReader is true if the reader should be closed together with the
+	// file.
+	ownsReader bool
+	// byteOrder is the byte order of the architecture.
+	byteOrder binary.ByteOrder
+}
+
+// detectByteOrder returns the byte order of the architecture. All the
+// machine types accepted by debug/pe are little-endian. If the machine type
+// is unknown, the byte order is ambiguous and the pclntab is searched for in
+// both byte orders. If it isn't found, little-endian is assumed.
+func (p *peFile) detectByteOrder() binary.ByteOrder {
+	switch p.file.Machine {
+	case pe.IMAGE_FILE_MACHINE_I386, pe.IMAGE_FILE_MACHINE_AMD64,
+		pe.IMAGE_FILE_MACHINE_ARMNT, pe.IMAGE_FILE_MACHINE_ARM64,
+		pe.IMAGE_FILE_MACHINE_RISCV32, pe.IMAGE_FILE_MACHINE_RISCV64, pe.IMAGE_FILE_MACHINE_RISCV128:
+		return binary.LittleEndian
+	}
+	if _, _, order, err := p.searchPCLNTab(nil); err == nil {
+		return order
+	}
+	return binary.LittleEndian
+}
+
+// sectionData reads the section's data if it's within the size limit.
+func (p *peFile) sectionData(s *pe.Section) ([]byte, error) {
+	if err := checkSectionSize(s.Name, uint64(s.Size), p.maxSectionBytes); err != nil {
+		return nil, err
+	}
+	return s.Data()
 }
 
 func (p *peFile) initSymTab() (map[string]Symbol, error) {
@@ -92,16 +124,8 @@ func (p *peFile) initSymTab() (map[string]Symbol, error) {
 		syms = append(syms, sym)
 	}
 
-	slices.SortStableFunc(syms, func(a, b Symbol) int {
-		return cmp.Compare(a.Value, b.Value)
-	})
-
-	for i := 0; i < len(syms)-1; i++ {
-		syms[i].Size = syms[i+1].Value - syms[i].Value
-	}
-
 	symm := make(map[string]Symbol)
-	for _, sym := range syms {
+	for _, sym := range ComputeSymbolSizes(syms) {
 		symm[sym.Name] = sym
 	}
 
@@ -120,6 +144,14 @@ func (p *peFile) getSymbol(name string) (Symbol, error) {
 	return sym, nil
 }
 
+func (p *peFile) getSymbolNames() ([]string, error) {
+	symm, err := p.getsymtab()
+	if err != nil {
+		return nil, err
+	}
+	return symbolNames(symm), nil
+}
+
 func (p *peFile) getParsedFile() any {
 	return p.file
 }
@@ -129,9 +161,14 @@ func (p *peFile) getReader() io.ReaderAt {
 }
 
 func (p *peFile) Close() error {
-	err := p.file.Close()
-	if err != nil {
-		return err
+	if p.file != nil {
+		err := p.file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if !p.ownsReader {
+		return nil
 	}
 	return tryClose(p.reader)
 }
@@ -141,7 +178,7 @@ func (p *peFile) getRData() ([]byte, error) {
 	if section == nil {
 		return nil, ErrSectionDoesNotExist
 	}
-	return section.Data()
+	return p.sectionData(section)
 }
 
 func (p *peFile) getCodeSection() (uint64, []byte, error) {
@@ -149,33 +186,49 @@ func (p *peFile) getCodeSection() (uint64, []byte, error) {
 	if section == nil {
 		return 0, nil, ErrSectionDoesNotExist
 	}
-	data, err := section.Data()
+	data, err := p.sectionData(section)
 	return p.imageBase + uint64(section.VirtualAddress), data, err
 }
 
-func (p *peFile) moduledataSection() string {
-	return ".data"
+func (p *peFile) getCodeSectionReader() (uint64, io.ReaderAt, int64, error) {
+	section := p.file.Section(".text")
+	if section == nil {
+		return 0, nil, 0, ErrSectionDoesNotExist
+	}
+	return p.imageBase + uint64(section.VirtualAddress), section, int64(section.Size), nil
+}
+
+func (p *peFile) moduledataSections() []string {
+	return []string{".data"}
 }
 
 func (p *peFile) getPCLNTABData() (uint64, []byte, error) {
+	addr, tab, _, err := p.searchPCLNTab(p.byteOrder)
+	return addr, tab, err
+}
+
+// searchPCLNTab looks for the pclntab in the sections it's stored in. If the
+// byte order is nil, both byte orders are tried. The byte order of the found
+// table is returned.
+func (p *peFile) searchPCLNTab(order binary.ByteOrder) (uint64, []byte, binary.ByteOrder, error) {
 	for _, v := range []string{".rdata", ".text"} {
 		sec := p.file.Section(v)
 		if sec == nil {
 			continue
 		}
-		secData, err := sec.Data()
+		secData, err := p.sectionData(sec)
 		if err != nil {
 			continue
 		}
-		tab, err := searchSectionForTab(secData, p.getFileInfo().ByteOrder)
+		tab, tabOrder, err := searchSectionForTab(secData, order)
 		if errors.Is(ErrNoPCLNTab, err) {
 			continue
 		}
 
 		addr := uint64(sec.VirtualAddress) + uint64(len(secData)-len(tab))
-		return p.imageBase + addr, tab, err
+		return p.imageBase + addr, tab, tabOrder, err
 	}
-	return 0, []byte{}, ErrNoPCLNTab
+	return 0, []byte{}, nil, ErrNoPCLNTab
 }
 
 func (p *peFile) getSectionDataFromAddress(address uint64) (uint64, []byte, error) {
@@ -186,7 +239,7 @@ func (p *peFile) getSectionDataFromAddress(address uint64) (uint64, []byte, erro
 		}
 
 		if p.imageBase+uint64(section.VirtualAddress) <= address && address < p.imageBase+uint64(section.VirtualAddress+section.Size) {
-			data, err := section.Data()
+			data, err := p.sectionData(section)
 			return p.imageBase + uint64(section.VirtualAddress), data, err
 		}
 	}
@@ -198,12 +251,12 @@ func (p *peFile) getSectionData(name string) (uint64, []byte, error) {
 	if section == nil {
 		return 0, nil, ErrSectionDoesNotExist
 	}
-	data, err := section.Data()
+	data, err := p.sectionData(section)
 	return p.imageBase + uint64(section.VirtualAddress), data, err
 }
 
 func (p *peFile) getFileInfo() *FileInfo {
-	fi := &FileInfo{ByteOrder: binary.LittleEndian, OS: "windows"}
+	fi := &FileInfo{ByteOrder: p.byteOrder, OS: "windows"}
 	if p.file.Machine == pe.IMAGE_FILE_MACHINE_I386 {
 		fi.WordSize = intSize32
 		fi.Arch = Arch386
@@ -223,5 +276,170 @@ func (p *peFile) getBuildID() (string, error) {
 }
 
 func (p *peFile) getDwarf() (*dwarf.Data, error) {
+	for _, s := range p.file.Sections {
+		if !strings.HasPrefix(s.Name, ".debug_") && !strings.HasPrefix(s.Name, ".zdebug_") {
+			continue
+		}
+		err := checkDwarfSectionSize(s.Name, uint64(max(s.Size, s.VirtualSize)), p.reader, int64(s.Offset), p.maxSectionBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return p.file.DWARF()
 }
+
+func (p *peFile) getSections() ([]Section, error) {
+	sections := make([]Section, 0, len(p.file.Sections))
+	for _, s := range p.file.Sections {
+		sec := Section{
+			Name:           s.Name,
+			VirtualAddress: p.imageBase + uint64(s.VirtualAddress),
+			Size:           uint64(s.VirtualSize),
+			FileOffset:     uint64(s.Offset),
+		}
+		if s.Characteristics&pe.IMAGE_SCN_MEM_READ != 0 {
+			sec.Permissions |= SectionRead
+		}
+		if s.Characteristics&pe.IMAGE_SCN_MEM_WRITE != 0 {
+			sec.Permissions |= SectionWrite
+		}
+		if s.Characteristics&pe.IMAGE_SCN_MEM_EXECUTE != 0 {
+			sec.Permissions |= SectionExecute
+		}
+		sections = append(sections, sec)
+	}
+	return sections, nil
+}
+
+func (p *peFile) getEntryPoint() (uint64, error) {
+	switch hdr := p.file.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		return p.imageBase + uint64(hdr.AddressOfEntryPoint), nil
+	case *pe.OptionalHeader64:
+		return p.imageBase + uint64(hdr.AddressOfEntryPoint), nil
+	}
+	return 0, errors.New("unknown optional header type")
+}
+
+// getExports returns the named exports from the export directory.
+func (p *peFile) getExports() ([]Export, error) {
+	var dirs []pe.DataDirectory
+	switch hdr := p.file.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dirs = hdr.DataDirectory[:min(hdr.NumberOfRvaAndSizes, uint32(len(hdr.DataDirectory)))]
+	case *pe.OptionalHeader64:
+		dirs = hdr.DataDirectory[:min(hdr.NumberOfRvaAndSizes, uint32(len(hdr.DataDirectory)))]
+	}
+	if len(dirs) <= pe.IMAGE_DIRECTORY_ENTRY_EXPORT {
+		return nil, nil
+	}
+	dir := dirs[pe.IMAGE_DIRECTORY_ENTRY_EXPORT]
+	if dir.VirtualAddress == 0 || dir.Size == 0 {
+		return nil, nil
+	}
+	return peExports(p.imageBase, dir, func(rva uint32) ([]byte, error) {
+		addr := p.imageBase + uint64(rva)
+		base, data, err := p.getSectionDataFromAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		if addr-base >= uint64(len(data)) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return data[addr-base:], nil
+	})
+}
+
+// peExports parses the export directory. The read function returns the
+// data of the section holding the relative virtual address, starting at the
+// address. Exports without a name and forwarders to other DLLs are skipped.
+func peExports(imageBase uint64, dir pe.DataDirectory, read func(rva uint32) ([]byte, error)) ([]Export, error) {
+	hdr, err := read(dir.VirtualAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the export directory: %w", err)
+	}
+	if len(hdr) < 40 {
+		return nil, fmt.Errorf("export directory is too small: %w", io.ErrUnexpectedEOF)
+	}
+	numFuncs := binary.LittleEndian.Uint32(hdr[20:])
+	numNames := binary.LittleEndian.Uint32(hdr[24:])
+	funcs, err := read(binary.LittleEndian.Uint32(hdr[28:]))
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the export address table: %w", err)
+	}
+	names, err := read(binary.LittleEndian.Uint32(hdr[32:]))
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the export name table: %w", err)
+	}
+	ordinals, err := read(binary.LittleEndian.Uint32(hdr[36:]))
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the export ordinal table: %w", err)
+	}
+	if uint64(len(funcs)) < 4*uint64(numFuncs) || uint64(len(names)) < 4*uint64(numNames) || uint64(len(ordinals)) < 2*uint64(numNames) {
+		return nil, fmt.Errorf("export tables are truncated: %w", io.ErrUnexpectedEOF)
+	}
+
+	exports := make([]Export, 0, numNames)
+	for i := uint32(0); i < numNames; i++ {
+		ord := uint32(binary.LittleEndian.Uint16(ordinals[2*i:]))
+		if ord >= numFuncs {
+			continue
+		}
+		rva := binary.LittleEndian.Uint32(funcs[4*ord:])
+		if rva >= dir.VirtualAddress && rva < dir.VirtualAddress+dir.Size {
+			// Forwarded to another DLL.
+			continue
+		}
+		name, err := read(binary.LittleEndian.Uint32(names[4*i:]))
+		if err != nil {
+			return nil, fmt.Errorf("error when reading the name of export %d: %w", i, err)
+		}
+		if n := bytes.IndexByte(name, 0); n != -1 {
+			name = name[:n]
+		}
+		exports = append(exports, Export{Name: string(name), Address: imageBase + uint64(rva)})
+	}
+	return exports, nil
+}
+
+// getDynamicImports returns the symbols from the import directory. The
+// imported symbols are named "symbol:dll" by debug/pe.
+func (p *peFile) getDynamicImports() ([]DynamicImport, error) {
+	syms, err := p.file.ImportedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the imported symbols: %w", err)
+	}
+	imports := make([]DynamicImport, 0, len(syms))
+	for _, s := range syms {
+		i := strings.LastIndexByte(s, ':')
+		if i == -1 {
+			imports = append(imports, DynamicImport{Name: s})
+			continue
+		}
+		imports = append(imports, DynamicImport{Name: s[:i], Library: s[i+1:]})
+	}
+	return imports, nil
+}
+
+// getImportedLibraries returns the DLLs from the import directory. The
+// imported symbols are named "symbol:dll" by debug/pe.
+func (p *peFile) getImportedLibraries() ([]string, error) {
+	syms, err := p.file.ImportedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the imported symbols: %w", err)
+	}
+	var libs []string
+	seen := make(map[string]bool)
+	for _, s := range syms {
+		i := strings.LastIndexByte(s, ':')
+		if i == -1 {
+			continue
+		}
+		lib := s[i+1:]
+		if !seen[lib] {
+			seen[lib] = true
+			libs = append(libs, lib)
+		}
+	}
+	return libs, nil
+}
@@ -0,0 +1,321 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+)
+
+// PEResource is a resource stored in the resource section of a PE file.
+type PEResource struct {
+	// Type is the resource type. For the predefined types, the name of the
+	// type is used, for example "RT_VERSION" or "RT_MANIFEST". Other types
+	// use the type's name or numeric ID.
+	Type string
+	// Name is the name of the resource or its numeric ID.
+	Name string
+	// Language is the language ID of the resource.
+	Language uint32
+	// Address is the virtual address of the resource data.
+	Address uint64
+	// Data is the resource data.
+	Data []byte
+	// VersionInfo holds the strings from the StringFileInfo block, for
+	// example "CompanyName" and "FileVersion". It's only set for RT_VERSION
+	// resources.
+	VersionInfo map[string]string
+}
+
+// peResourceTypes are the names of the predefined resource types.
+var peResourceTypes = map[uint32]string{
+	1:  "RT_CURSOR",
+	2:  "RT_BITMAP",
+	3:  "RT_ICON",
+	4:  "RT_MENU",
+	5:  "RT_DIALOG",
+	6:  "RT_STRING",
+	7:  "RT_FONTDIR",
+	8:  "RT_FONT",
+	9:  "RT_ACCELERATOR",
+	10: "RT_RCDATA",
+	11: "RT_MESSAGETABLE",
+	12: "RT_GROUP_CURSOR",
+	14: "RT_GROUP_ICON",
+	16: "RT_VERSION",
+	17: "RT_DLGINCLUDE",
+	19: "RT_PLUGPLAY",
+	20: "RT_VXD",
+	21: "RT_ANICURSOR",
+	22: "RT_ANIICON",
+	23: "RT_HTML",
+	24: "RT_MANIFEST",
+}
+
+// GetPEResources returns the resources stored in the resource section, for
+// example the version information, icons and the manifest. Note that the
+// version information is set by whoever built the binary and may be faked.
+// This is only supported for PE files. For other file types,
+// ErrUnsupportedFile is returned.
+func (f *GoFile) GetPEResources() ([]PEResource, error) {
+	p, ok := f.fh.(*peFile)
+	if !ok {
+		return nil, ErrUnsupportedFile
+	}
+	return p.getResources()
+}
+
+func (p *peFile) getResources() ([]PEResource, error) {
+	var dirs []pe.DataDirectory
+	switch hdr := p.file.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dirs = hdr.DataDirectory[:min(hdr.NumberOfRvaAndSizes, uint32(len(hdr.DataDirectory)))]
+	case *pe.OptionalHeader64:
+		dirs = hdr.DataDirectory[:min(hdr.NumberOfRvaAndSizes, uint32(len(hdr.DataDirectory)))]
+	}
+	if len(dirs) <= pe.IMAGE_DIRECTORY_ENTRY_RESOURCE || dirs[pe.IMAGE_DIRECTORY_ENTRY_RESOURCE].VirtualAddress == 0 {
+		return nil, nil
+	}
+	dir := dirs[pe.IMAGE_DIRECTORY_ENTRY_RESOURCE]
+
+	data, err := p.rvaData(dir.VirtualAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the resource directory: %w", err)
+	}
+	return parseResourceDirectory(data, p.imageBase, p.rvaData)
+}
+
+// rvaData returns the section data starting at the relative virtual address.
+func (p *peFile) rvaData(rva uint32) ([]byte, error) {
+	for _, s := range p.file.Sections {
+		if s.VirtualAddress <= rva && rva < s.VirtualAddress+max(s.VirtualSize, s.Size) {
+			data, err := p.sectionData(s)
+			if err != nil {
+				return nil, err
+			}
+			off := rva - s.VirtualAddress
+			if off >= uint32(len(data)) {
+				return nil, fmt.Errorf("the address 0x%x is not backed by file data", rva)
+			}
+			return data[off:], nil
+		}
+	}
+	return nil, ErrSectionDoesNotExist
+}
+
+// parseResourceDirectory walks the resource directory tree. The tree has
+// three levels: type, name and language. The rvaData function is used to
+// read the resource data which can be located outside the resource section.
+func parseResourceDirectory(rsrc []byte, imageBase uint64, rvaData func(uint32) ([]byte, error)) ([]PEResource, error) {
+	type entry struct {
+		id     string
+		num    uint32
+		offset uint32
+		isDir  bool
+	}
+	readDir := func(off uint32) ([]entry, error) {
+		if uint64(off)+16 > uint64(len(rsrc)) {
+			return nil, fmt.Errorf("resource directory at 0x%x is out of bounds", off)
+		}
+		n := int(binary.LittleEndian.Uint16(rsrc[off+12:])) + int(binary.LittleEndian.Uint16(rsrc[off+14:]))
+		if uint64(off)+16+uint64(n)*8 > uint64(len(rsrc)) {
+			return nil, fmt.Errorf("resource directory at 0x%x is truncated", off)
+		}
+		entries := make([]entry, n)
+		for i := range entries {
+			b := rsrc[off+16+uint32(i)*8:]
+			name := binary.LittleEndian.Uint32(b)
+			target := binary.LittleEndian.Uint32(b[4:])
+			e := entry{offset: target &^ (1 << 31), isDir: target&(1<<31) != 0}
+			if name&(1<<31) != 0 {
+				e.id = resourceString(rsrc, name&^(1<<31))
+			} else {
+				e.num = name
+				e.id = strconv.FormatUint(uint64(name), 10)
+			}
+			entries[i] = e
+		}
+		return entries, nil
+	}
+
+	types, err := readDir(0)
+	if err != nil {
+		return nil, err
+	}
+	var resources []PEResource
+	for _, typ := range types {
+		if !typ.isDir {
+			continue
+		}
+		typeName := typ.id
+		if n, ok := peResourceTypes[typ.num]; ok {
+			typeName = n
+		}
+		names, err := readDir(typ.offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if !name.isDir {
+				continue
+			}
+			langs, err := readDir(name.offset)
+			if err != nil {
+				return nil, err
+			}
+			for _, lang := range langs {
+				if lang.isDir || uint64(lang.offset)+16 > uint64(len(rsrc)) {
+					continue
+				}
+				rva := binary.LittleEndian.Uint32(rsrc[lang.offset:])
+				size := binary.LittleEndian.Uint32(rsrc[lang.offset+4:])
+				data, err := rvaData(rva)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get the data for resource %s/%s: %w", typeName, name.id, err)
+				}
+				if uint64(size) > uint64(len(data)) {
+					return nil, fmt.Errorf("the data for resource %s/%s is truncated", typeName, name.id)
+				}
+				res := PEResource{
+					Type:     typeName,
+					Name:     name.id,
+					Language: lang.num,
+					Address:  imageBase + uint64(rva),
+					Data:     data[:size],
+				}
+				if typeName == "RT_VERSION" {
+					res.VersionInfo = parseVersionInfo(res.Data)
+				}
+				resources = append(resources, res)
+			}
+		}
+	}
+	return resources, nil
+}
+
+// resourceString reads a length prefixed UTF-16 string from the resource
+// section.
+func resourceString(rsrc []byte, off uint32) string {
+	if uint64(off)+2 > uint64(len(rsrc)) {
+		return ""
+	}
+	n := uint64(binary.LittleEndian.Uint16(rsrc[off:]))
+	start := uint64(off) + 2
+	if start+n*2 > uint64(len(rsrc)) {
+		return ""
+	}
+	return decodeUTF16(rsrc[start : start+n*2])
+}
+
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// versionBlock is a block in the version information. All the version
+// information structures share this layout.
+type versionBlock struct {
+	key      string
+	value    []byte
+	children []byte
+}
+
+// parseVersionBlock parses the block at the start of b. It also returns the
+// length of the block, padded to a 32-bit boundary.
+func parseVersionBlock(b []byte) (versionBlock, int, bool) {
+	if len(b) < 6 {
+		return versionBlock{}, 0, false
+	}
+	length := int(binary.LittleEndian.Uint16(b))
+	valueLength := int(binary.LittleEndian.Uint16(b[2:]))
+	if binary.LittleEndian.Uint16(b[4:]) == 1 {
+		// The length of text values is in words.
+		valueLength *= 2
+	}
+	if length < 6 || length > len(b) {
+		return versionBlock{}, 0, false
+	}
+	b = b[:length]
+
+	// The key is a null terminated UTF-16 string.
+	pos := 6
+	for pos+1 < len(b) && (b[pos] != 0 || b[pos+1] != 0) {
+		pos += 2
+	}
+	block := versionBlock{key: decodeUTF16(b[6:pos])}
+	pos = align4(pos + 2)
+	if pos+valueLength > len(b) {
+		return versionBlock{}, 0, false
+	}
+	block.value = b[pos : pos+valueLength]
+	pos = align4(pos + valueLength)
+	if pos < len(b) {
+		block.children = b[pos:]
+	}
+	return block, align4(length), true
+}
+
+func align4(n int) int {
+	return (n + 3) &^ 3
+}
+
+// parseVersionInfo returns the strings from the StringFileInfo block of the
+// VS_VERSIONINFO structure.
+func parseVersionInfo(data []byte) map[string]string {
+	root, _, ok := parseVersionBlock(data)
+	if !ok || root.key != "VS_VERSION_INFO" {
+		return nil
+	}
+	strs := make(map[string]string)
+	eachBlock(root.children, func(info versionBlock) {
+		if info.key != "StringFileInfo" {
+			return
+		}
+		eachBlock(info.children, func(table versionBlock) {
+			eachBlock(table.children, func(s versionBlock) {
+				val := decodeUTF16(s.value)
+				// Drop the null terminator.
+				if i := len(val) - 1; i >= 0 && val[i] == 0 {
+					val = val[:i]
+				}
+				strs[s.key] = val
+			})
+		})
+	})
+	return strs
+}
+
+func eachBlock(b []byte, fn func(versionBlock)) {
+	for len(b) > 0 {
+		block, n, ok := parseVersionBlock(b)
+		if !ok {
+			return
+		}
+		fn(block)
+		if n >= len(b) {
+			return
+		}
+		b = b[n:]
+	}
+}
@@ -0,0 +1,116 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/require"
+)
+
+func utf16Bytes(s string) []byte {
+	var b []byte
+	for _, c := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, c)
+	}
+	return b
+}
+
+// versionTestBlock encodes a version information block.
+func versionTestBlock(key string, text bool, value []byte, children ...[]byte) []byte {
+	b := make([]byte, 6)
+	b = append(b, utf16Bytes(key)...)
+	b = append(b, 0, 0)
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+	b = append(b, value...)
+	for _, c := range children {
+		for len(b)%4 != 0 {
+			b = append(b, 0)
+		}
+		b = append(b, c...)
+	}
+	valueLength := len(value)
+	if text {
+		binary.LittleEndian.PutUint16(b[4:], 1)
+		valueLength /= 2
+	}
+	binary.LittleEndian.PutUint16(b, uint16(len(b)))
+	binary.LittleEndian.PutUint16(b[2:], uint16(valueLength))
+	return b
+}
+
+func TestParseVersionInfo(t *testing.T) {
+	str := func(k, v string) []byte {
+		return versionTestBlock(k, true, utf16Bytes(v+"\x00"))
+	}
+	data := versionTestBlock("VS_VERSION_INFO", false, make([]byte, 52),
+		versionTestBlock("StringFileInfo", true, nil,
+			versionTestBlock("040904b0", true, nil,
+				str("CompanyName", "GoRE"),
+				str("FileVersion", "1.2.3"),
+			),
+		),
+		versionTestBlock("VarFileInfo", true, nil,
+			versionTestBlock("Translation", false, []byte{0x09, 0x04, 0xb0, 0x04}),
+		),
+	)
+
+	info := parseVersionInfo(data)
+	require.Equal(t, map[string]string{"CompanyName": "GoRE", "FileVersion": "1.2.3"}, info)
+
+	require.Nil(t, parseVersionInfo(data[:4]))
+}
+
+func TestParseResourceDirectory(t *testing.T) {
+	r := require.New(t)
+
+	const rsrcRVA = 0x1000
+	rsrc := make([]byte, 0x80)
+	dir := func(off int, entryName, entryTarget uint32) {
+		// One ID entry.
+		binary.LittleEndian.PutUint16(rsrc[off+14:], 1)
+		binary.LittleEndian.PutUint32(rsrc[off+16:], entryName)
+		binary.LittleEndian.PutUint32(rsrc[off+20:], entryTarget)
+	}
+	dir(0x00, 24, 1<<31|0x18) // RT_MANIFEST
+	dir(0x18, 1, 1<<31|0x30)  // Name ID 1
+	dir(0x30, 0x409, 0x48)    // Language en-US
+	binary.LittleEndian.PutUint32(rsrc[0x48:], rsrcRVA+0x60)
+	binary.LittleEndian.PutUint32(rsrc[0x4c:], 5)
+	copy(rsrc[0x60:], "<xml>")
+
+	rvaData := func(rva uint32) ([]byte, error) {
+		return rsrc[rva-rsrcRVA:], nil
+	}
+	resources, err := parseResourceDirectory(rsrc, 0x400000, rvaData)
+	r.NoError(err)
+	r.Equal([]PEResource{{
+		Type:     "RT_MANIFEST",
+		Name:     "1",
+		Language: 0x409,
+		Address:  0x400000 + rsrcRVA + 0x60,
+		Data:     []byte("<xml>"),
+	}}, resources)
+
+	_, err = parseResourceDirectory(rsrc[:0x20], 0x400000, rvaData)
+	r.Error(err)
+}
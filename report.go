@@ -0,0 +1,82 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import "encoding/json"
+
+// report is the JSON document produced by Report.
+type report struct {
+	FileInfo  *FileInfo       `json:"fileInfo"`
+	BuildInfo *BuildInfo      `json:"buildInfo"`
+	BuildID   string          `json:"buildID"`
+	Packages  []reportPackage `json:"packages"`
+	Types     []*GoType       `json:"types"`
+}
+
+// reportPackage is a package with its class and source files.
+type reportPackage struct {
+	*Package
+	Class       string        `json:"class"`
+	SourceFiles []*SourceFile `json:"sourceFiles"`
+}
+
+// Report returns a JSON document describing the file. The document holds the
+// FileInfo, BuildInfo and BuildID, the packages with their functions, methods
+// and source files, and the types. The packages are ordered by their class:
+// the main packages first, followed by the vendor, standard library,
+// generated and unknown packages. Within a class, the packages are sorted by
+// their name. The field names are part of the API and
+// don't change between releases, so the reports of different builds can be
+// compared.
+func (f *GoFile) Report() ([]byte, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	r := report{
+		FileInfo:  f.FileInfo,
+		BuildInfo: f.BuildInfo,
+		BuildID:   f.BuildID,
+	}
+	for _, class := range []struct {
+		pkgs  []*Package
+		class PackageClass
+	}{
+		{f.pkgs, ClassMain},
+		{f.vendors, ClassVendor},
+		{f.stdPkgs, ClassSTD},
+		{f.generated, ClassGenerated},
+		{f.unknown, ClassUnknown},
+	} {
+		for _, p := range class.pkgs {
+			r.Packages = append(r.Packages, reportPackage{
+				Package:     p,
+				Class:       class.class.String(),
+				SourceFiles: f.GetSourceFiles(p),
+			})
+		}
+	}
+
+	r.Types, err = f.GetTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(r, "", "  ")
+}
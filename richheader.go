@@ -0,0 +1,119 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// RichHeader is the Rich header of a PE file. It is written to the DOS stub
+// by the Microsoft linker and records the tools used to produce the object
+// files that were linked into the binary. Go's internal linker doesn't write
+// the header, but it is present in binaries that have been linked by the
+// Microsoft linker, for example when using cgo.
+type RichHeader struct {
+	// Offset is the file offset of the header.
+	Offset int64
+	// Key is the XOR key used to mask the header. It's a checksum of the DOS
+	// header and the entries.
+	Key uint32
+	// Entries is the list of tools.
+	Entries []RichEntry
+}
+
+// RichEntry is an entry in the Rich header.
+type RichEntry struct {
+	// ProductID identifies the tool, for example the C compiler or the linker
+	// of a specific Visual Studio version.
+	ProductID uint16
+	// Build is the build number of the tool.
+	Build uint16
+	// Count is the number of objects produced by the tool.
+	Count uint32
+}
+
+// GetRichHeader returns the Rich header of the PE file. If the file has no
+// Rich header, ErrNoRichHeader is returned.
+// This is only supported for PE files. For other file types,
+// ErrUnsupportedFile is returned.
+func (f *GoFile) GetRichHeader() (*RichHeader, error) {
+	p, ok := f.fh.(*peFile)
+	if !ok {
+		return nil, ErrUnsupportedFile
+	}
+
+	// The header is located between the DOS header and the PE header.
+	var hdr [0x40]byte
+	if _, err := p.reader.ReadAt(hdr[:], 0); err != nil {
+		return nil, fmt.Errorf("failed to read the DOS header: %w", err)
+	}
+	peOff := binary.LittleEndian.Uint32(hdr[0x3c:])
+	if peOff <= 0x40 || peOff > 0x1000 {
+		return nil, ErrNoRichHeader
+	}
+	stub := make([]byte, peOff)
+	if _, err := p.reader.ReadAt(stub, 0); err != nil {
+		return nil, fmt.Errorf("failed to read the DOS stub: %w", err)
+	}
+	return parseRichHeader(stub)
+}
+
+var (
+	richMarker = []byte("Rich")
+	dansMarker = uint32(0x536e6144) // "DanS"
+)
+
+// parseRichHeader parses the Rich header from the data before the PE header.
+func parseRichHeader(stub []byte) (*RichHeader, error) {
+	end := bytes.LastIndex(stub, richMarker)
+	if end == -1 || end+8 > len(stub) || end%4 != 0 {
+		return nil, ErrNoRichHeader
+	}
+	key := binary.LittleEndian.Uint32(stub[end+4:])
+
+	// Walk backwards until the start marker is found.
+	start := -1
+	for off := end - 4; off >= 0x40; off -= 4 {
+		if binary.LittleEndian.Uint32(stub[off:])^key == dansMarker {
+			start = off
+			break
+		}
+	}
+	if start == -1 {
+		return nil, ErrNoRichHeader
+	}
+
+	// The start marker is followed by three padding words that are zero
+	// before masking.
+	first := start + 16
+	if first > end || (end-first)%8 != 0 {
+		return nil, fmt.Errorf("malformed rich header at 0x%x", start)
+	}
+	rh := &RichHeader{Offset: int64(start), Key: key}
+	for off := first; off < end; off += 8 {
+		id := binary.LittleEndian.Uint32(stub[off:]) ^ key
+		rh.Entries = append(rh.Entries, RichEntry{
+			ProductID: uint16(id >> 16),
+			Build:     uint16(id),
+			Count:     binary.LittleEndian.Uint32(stub[off+4:]) ^ key,
+		})
+	}
+	return rh, nil
+}
@@ -0,0 +1,60 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRichHeader(t *testing.T) {
+	r := require.New(t)
+
+	const key = 0x1234abcd
+	stub := make([]byte, 0x80)
+	put := func(v uint32) {
+		stub = binary.LittleEndian.AppendUint32(stub, v)
+	}
+	put(dansMarker ^ key)
+	put(key)
+	put(key)
+	put(key)
+	put((0x0104<<16 | 30159) ^ key)
+	put(12 ^ key)
+	put((0x0102<<16 | 30159) ^ key)
+	put(1 ^ key)
+	stub = append(stub, richMarker...)
+	put(key)
+	stub = append(stub, make([]byte, 8)...)
+
+	rh, err := parseRichHeader(stub)
+	r.NoError(err)
+	r.Equal(&RichHeader{
+		Offset: 0x80,
+		Key:    key,
+		Entries: []RichEntry{
+			{ProductID: 0x0104, Build: 30159, Count: 12},
+			{ProductID: 0x0102, Build: 30159, Count: 1},
+		},
+	}, rh)
+
+	_, err = parseRichHeader(make([]byte, 0x80))
+	r.ErrorIs(err, ErrNoRichHeader)
+}
@@ -0,0 +1,85 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// ScriptFormatGhidra is the format for a Ghidra Python script.
+	ScriptFormatGhidra = "ghidra"
+	// ScriptFormatIDA is the format for an IDA Python script.
+	ScriptFormatIDA = "ida"
+)
+
+const ghidraScriptBody = `
+from ghidra.program.model.symbol import SourceType
+
+for addr, name in functions:
+    a = toAddr(addr)
+    fn = getFunctionAt(a)
+    if fn is None:
+        fn = createFunction(a, name)
+    if fn is not None:
+        fn.setName(name, SourceType.ANALYSIS)
+    else:
+        createLabel(a, name, True)
+`
+
+const idaScriptBody = `
+import ida_funcs
+import ida_name
+
+for addr, name in functions:
+    ida_funcs.add_func(addr)
+    ida_name.set_name(addr, name, ida_name.SN_NOWARN | ida_name.SN_NOCHECK)
+`
+
+// ExportScript returns a Python script that names the functions in a
+// disassembler using the names recovered from the binary. The format is
+// either ScriptFormatGhidra or ScriptFormatIDA. The script creates the
+// functions that the disassembler hasn't found.
+func (f *GoFile) ExportScript(format string) ([]byte, error) {
+	var body string
+	switch format {
+	case ScriptFormatGhidra:
+		body = ghidraScriptBody
+	case ScriptFormatIDA:
+		body = idaScriptBody
+	default:
+		return nil, fmt.Errorf("unsupported script format: %s", format)
+	}
+
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("# Function names recovered by GoRE.\n\n")
+	buf.WriteString("functions = [\n")
+	for _, fn := range f.pclntab.Funcs {
+		fmt.Fprintf(buf, "    (0x%x, %s),\n", fn.Entry, strconv.QuoteToASCII(fn.Name))
+	}
+	buf.WriteString("]\n")
+	buf.WriteString(body)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,74 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+// SectionPermissions are the memory access permissions of a section.
+type SectionPermissions uint8
+
+const (
+	// SectionRead is set if the section is readable.
+	SectionRead SectionPermissions = 1 << iota
+	// SectionWrite is set if the section is writable.
+	SectionWrite
+	// SectionExecute is set if the section is executable.
+	SectionExecute
+)
+
+// String returns the permissions in the "rwx" form used by ls, for example
+// "r-x" for a readable and executable section.
+func (p SectionPermissions) String() string {
+	b := []byte("---")
+	if p&SectionRead != 0 {
+		b[0] = 'r'
+	}
+	if p&SectionWrite != 0 {
+		b[1] = 'w'
+	}
+	if p&SectionExecute != 0 {
+		b[2] = 'x'
+	}
+	return string(b)
+}
+
+// Section holds the metadata of a section in the binary. The sections of
+// the different file formats are normalized into this structure.
+type Section struct {
+	// Name is the name of the section.
+	Name string
+	// VirtualAddress is the address the section is loaded at. It is zero
+	// for sections that are not loaded into memory.
+	VirtualAddress uint64
+	// Size is the size of the section in memory.
+	Size uint64
+	// FileOffset is the offset of the section's data in the file. Sections
+	// that don't have any data in the file, like the bss section, can have
+	// an offset of zero.
+	FileOffset uint64
+	// Permissions are the memory access permissions of the section. For
+	// Mach-O files, the permissions of the segment holding the section are
+	// used.
+	Permissions SectionPermissions
+}
+
+// Sections returns the sections of the binary in the order they are stored
+// in the file's section table. For WebAssembly modules, the sections of the
+// module are returned. These are not loaded into memory so only their file
+// offset and size are set.
+func (f *GoFile) Sections() ([]Section, error) {
+	return f.fh.getSections()
+}
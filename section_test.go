@@ -0,0 +1,68 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"debug/elf"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSectionPermissionsString(t *testing.T) {
+	assert.Equal(t, "---", SectionPermissions(0).String())
+	assert.Equal(t, "r-x", (SectionRead | SectionExecute).String())
+	assert.Equal(t, "rw-", (SectionRead | SectionWrite).String())
+}
+
+func TestELFSections(t *testing.T) {
+	f := &GoFile{fh: &elfFile{file: &elf.File{Sections: []*elf.Section{
+		{SectionHeader: elf.SectionHeader{Type: elf.SHT_NULL}},
+		{SectionHeader: elf.SectionHeader{
+			Name:   ".text",
+			Type:   elf.SHT_PROGBITS,
+			Flags:  elf.SHF_ALLOC | elf.SHF_EXECINSTR,
+			Addr:   0x401000,
+			Offset: 0x1000,
+			Size:   0x100,
+		}},
+		{SectionHeader: elf.SectionHeader{
+			Name:   ".data",
+			Type:   elf.SHT_PROGBITS,
+			Flags:  elf.SHF_ALLOC | elf.SHF_WRITE,
+			Addr:   0x402000,
+			Offset: 0x2000,
+			Size:   0x20,
+		}},
+		{SectionHeader: elf.SectionHeader{
+			Name:   ".symtab",
+			Type:   elf.SHT_SYMTAB,
+			Offset: 0x3000,
+			Size:   0x30,
+		}},
+	}}}}
+
+	sections, err := f.Sections()
+	require.NoError(t, err)
+	assert.Equal(t, []Section{
+		{Name: ".text", VirtualAddress: 0x401000, Size: 0x100, FileOffset: 0x1000, Permissions: SectionRead | SectionExecute},
+		{Name: ".data", VirtualAddress: 0x402000, Size: 0x20, FileOffset: 0x2000, Permissions: SectionRead | SectionWrite},
+		{Name: ".symtab", Size: 0x30, FileOffset: 0x3000},
+	}, sections)
+}
@@ -0,0 +1,137 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// FunctionSignature returns the code of the function with the operands that
+// depend on where the code and data are placed in the binary masked out.
+// This includes the targets of calls and jumps and the addresses of
+// referenced data. The same function compiled by the same compiler produces
+// the same signature in different binaries, which makes it possible to
+// build a database of known functions for MatchKnownFunctions.
+// Only x86 (i386 and amd64) and arm64 binaries are supported. For other
+// architectures, ErrArchNotSupported is returned. On i386, absolute data
+// addresses are not masked.
+func (f *GoFile) FunctionSignature(fn *Function) ([]byte, error) {
+	buf, err := f.FunctionData(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f.FileInfo.Arch {
+	case Arch386, ArchAMD64:
+		return x86Signature(buf, f.FileInfo.WordSize*8), nil
+	case ArchARM64:
+		return arm64Signature(buf, f.FileInfo.ByteOrder), nil
+	default:
+		return nil, ErrArchNotSupported
+	}
+}
+
+// MatchKnownFunctions matches the functions in the binary against a
+// database of known functions. The database maps the name of a function to
+// its signature as returned by FunctionSignature, for example extracted
+// from a binary with known function names. The result maps the address of
+// each matched function to the name from the database. Signatures shared by
+// more than one name in the database are ambiguous and never matched. This
+// can be used to label functions in binaries where the names have been
+// obfuscated or to cross-check the names in the pclntab.
+// Only x86 (i386 and amd64) and arm64 binaries are supported. For other
+// architectures, ErrArchNotSupported is returned.
+func (f *GoFile) MatchKnownFunctions(db map[string][]byte) (map[uint64]string, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]string, len(db))
+	ambiguous := make(map[string]bool)
+	for name, sig := range db {
+		if _, ok := known[string(sig)]; ok {
+			ambiguous[string(sig)] = true
+		}
+		known[string(sig)] = name
+	}
+
+	matches := make(map[uint64]string)
+	for entry, fn := range f.functionsByEntry() {
+		sig, err := f.FunctionSignature(fn)
+		if err != nil {
+			if errors.Is(err, ErrArchNotSupported) {
+				return nil, err
+			}
+			continue
+		}
+		if name, ok := known[string(sig)]; ok && !ambiguous[string(sig)] {
+			matches[entry] = name
+		}
+	}
+	return matches, nil
+}
+
+// x86Signature returns a copy of the code with the PC-relative operands
+// zeroed.
+func x86Signature(buf []byte, mode int) []byte {
+	sig := make([]byte, len(buf))
+	copy(sig, buf)
+	s := 0
+	for s < len(sig) {
+		inst, err := x86asm.Decode(buf[s:], mode)
+		if err != nil {
+			s++
+			continue
+		}
+		if inst.PCRel > 0 {
+			clear(sig[s+inst.PCRelOff : s+inst.PCRelOff+inst.PCRel])
+		}
+		s += inst.Len
+	}
+	return sig
+}
+
+// arm64Signature returns a copy of the code with the immediates of the
+// instructions used to reference code and data zeroed. These are the same
+// instructions as decoded by arm64Refs. The immediates of the loads, stores
+// and additions are masked even if they don't follow an ADRP instruction.
+func arm64Signature(buf []byte, order binary.ByteOrder) []byte {
+	sig := make([]byte, len(buf))
+	copy(sig, buf)
+	for i := 0; i+4 <= len(sig); i += 4 {
+		x := order.Uint32(sig[i:])
+		switch {
+		case x&0x7c000000 == 0x14000000:
+			// BL and B.
+			x &^= 0x03ffffff
+		case x&0x1f000000 == 0x10000000:
+			// ADR and ADRP.
+			x &^= 0x60ffffe0
+		case x&0xff800000 == 0x91000000, x&0x3b000000 == 0x39000000:
+			// ADD (immediate) and load and store register (unsigned
+			// immediate).
+			x &^= 0x003ffc00
+		}
+		order.PutUint32(sig[i:], x)
+	}
+	return sig
+}
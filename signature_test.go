@@ -0,0 +1,60 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestX86Signature(t *testing.T) {
+	code := []byte{
+		0x48, 0x8d, 0x05, 0x10, 0x20, 0x30, 0x00, // LEAQ 0x302010(IP), AX
+		0xe8, 0x11, 0x22, 0x33, 0x00, // CALL 0x332211(PC)
+		0x48, 0x89, 0xd8, // MOVQ BX, AX
+		0xc3, // RET
+	}
+	expected := []byte{
+		0x48, 0x8d, 0x05, 0x00, 0x00, 0x00, 0x00,
+		0xe8, 0x00, 0x00, 0x00, 0x00,
+		0x48, 0x89, 0xd8,
+		0xc3,
+	}
+	assert.Equal(t, expected, x86Signature(code, 64))
+	// The input is not modified.
+	assert.Equal(t, byte(0x10), code[3])
+}
+
+func TestARM64Signature(t *testing.T) {
+	le := binary.LittleEndian
+	code := make([]byte, 20)
+	le.PutUint32(code[0:], 0x94000123)  // BL
+	le.PutUint32(code[4:], 0xb0000401)  // ADRP X1, page
+	le.PutUint32(code[8:], 0x91234021)  // ADD X1, X1, #0x8d0
+	le.PutUint32(code[12:], 0xf9400821) // LDR X1, [X1, #16]
+	le.PutUint32(code[16:], 0xaa0103e0) // MOV X0, X1
+
+	sig := arm64Signature(code, le)
+	assert.Equal(t, uint32(0x94000000), le.Uint32(sig[0:]))
+	assert.Equal(t, uint32(0x90000001), le.Uint32(sig[4:]))
+	assert.Equal(t, uint32(0x91000021), le.Uint32(sig[8:]))
+	assert.Equal(t, uint32(0xf9400021), le.Uint32(sig[12:]))
+	assert.Equal(t, uint32(0xaa0103e0), le.Uint32(sig[16:]))
+}
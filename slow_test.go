@@ -19,11 +19,19 @@
 package gore
 
 import (
+	"bytes"
+	"context"
+	"debug/elf"
+	"debug/gosym"
+	"encoding/json"
 	"fmt"
+	"go/token"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -247,6 +255,394 @@ func TestGetTypesFromDynamicBuiltResources(t *testing.T) {
 	})
 }
 
+func TestTypeNameIndex(t *testing.T) {
+	getMatrix(t, nil, nil, "typeNameIndex", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		typs, err := f.GetTypes()
+		r.NoError(err)
+
+		idx, err := f.TypeNameIndex()
+		r.NoError(err)
+		r.Len(idx, len(typs))
+		for _, typ := range typs {
+			r.Equal(typ.String(), idx[typ.Addr])
+		}
+	})
+}
+
+func TestCodeReader(t *testing.T) {
+	getMatrix(t, nil, nil, "codeReader", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		cr, addr, err := f.CodeReader()
+		r.NoError(err)
+
+		buf := make([]byte, 0x100)
+		_, err = cr.ReadAt(buf, 0x100)
+		r.NoError(err)
+
+		expected, err := f.Bytes(addr+0x100, 0x100)
+		r.NoError(err)
+		r.Equal(expected, buf)
+	})
+}
+
+func TestGetMinimumOSVersion(t *testing.T) {
+	getMatrix(t, nil, nil, "minimumOSVersion", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		ver, err := f.GetMinimumOSVersion()
+		if f.FileInfo.OS != "macOS" {
+			r.ErrorIs(err, ErrUnsupportedFile)
+			return
+		}
+		r.NoError(err)
+		r.True(strings.HasPrefix(ver, "macOS "), "unexpected version %s", ver)
+	})
+}
+
+func TestHasCodeSignature(t *testing.T) {
+	getMatrix(t, nil, nil, "codeSignature", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		_, err = f.HasCodeSignature()
+		if f.FileInfo.OS != "macOS" {
+			r.ErrorIs(err, ErrUnsupportedFile)
+			return
+		}
+		r.NoError(err)
+
+		// A signature must not interfere with the section lookups.
+		_, err = f.GetPackages()
+		r.NoError(err)
+	})
+}
+
+func TestGetItabs(t *testing.T) {
+	getMatrix(t, nil, nil, "itabs", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		itabs, err := f.GetItabs()
+		r.NoError(err)
+
+		var writer *Itab
+		for _, itab := range itabs {
+			if itab.Interface.String() == "io.Writer" && itab.Type.String() == "*os.File" {
+				writer = itab
+				break
+			}
+		}
+		r.NotNil(writer, "the io.Writer itab for *os.File not found")
+		r.Len(writer.Methods, 1)
+		tab, err := f.PCLNTab()
+		r.NoError(err)
+		fn := tab.PCToFunc(writer.Methods[0])
+		r.NotNil(fn, "no function at 0x%x", writer.Methods[0])
+		r.Equal(writer.Methods[0], fn.Entry)
+		r.Equal("os.(*File).Write", fn.Name)
+		_, err = f.FunctionForAddress(writer.Methods[0])
+		r.NoError(err)
+	})
+}
+
+func TestTypeImplements(t *testing.T) {
+	getMatrix(t, nil, nil, "typeImplements", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		types, err := f.GetTypes()
+		r.NoError(err)
+
+		var file *GoType
+		for _, typ := range types {
+			if typ.String() == "*os.File" {
+				file = typ
+				break
+			}
+		}
+		r.NotNil(file, "*os.File type not found")
+
+		var writer *GoType
+		for _, iface := range file.Implements {
+			if iface.String() == "io.Writer" {
+				writer = iface
+			}
+		}
+		r.NotNil(writer, "*os.File doesn't implement io.Writer")
+		r.Len(writer.Methods, 1)
+		r.Equal("Write", writer.Methods[0].Name)
+	})
+}
+
+func TestStructFieldLayout(t *testing.T) {
+	getMatrix(t, nil, nil, "structFieldLayout", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		types, err := f.GetTypes()
+		r.NoError(err)
+
+		checked := 0
+		for _, typ := range types {
+			if typ.Kind != reflect.Struct {
+				continue
+			}
+			var prev uint64
+			for i, field := range typ.Fields {
+				if i > 0 {
+					r.GreaterOrEqual(field.FieldOffset, prev, "field %s of %s is out of order", field.FieldName, typ.Name)
+				}
+				prev = field.FieldOffset
+				if !field.FieldAnon {
+					r.Equal(token.IsExported(field.FieldName), field.FieldExported, "field %s of %s", field.FieldName, typ.Name)
+				}
+				checked++
+			}
+		}
+		r.NotZero(checked)
+	})
+}
+
+func TestReport(t *testing.T) {
+	getMatrix(t, nil, nil, "report", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		buf, err := f.Report()
+		r.NoError(err)
+
+		var report struct {
+			FileInfo struct {
+				Arch      string `json:"arch"`
+				ByteOrder string `json:"byteOrder"`
+			} `json:"fileInfo"`
+			BuildID  string `json:"buildID"`
+			Packages []struct {
+				Name        string `json:"name"`
+				Class       string `json:"class"`
+				SourceFiles []struct {
+					Name string `json:"name"`
+				} `json:"sourceFiles"`
+			} `json:"packages"`
+			Types []struct {
+				Kind string `json:"kind"`
+				Name string `json:"name"`
+			} `json:"types"`
+		}
+		r.NoError(json.Unmarshal(buf, &report))
+		r.Equal(f.FileInfo.Arch, report.FileInfo.Arch)
+		r.Equal(f.FileInfo.ByteOrder.String(), report.FileInfo.ByteOrder)
+		r.Equal(f.BuildID, report.BuildID)
+
+		var mainFound bool
+		for _, p := range report.Packages {
+			if p.Name == "main" {
+				r.Equal("main", p.Class)
+				r.NotEmpty(p.SourceFiles)
+				mainFound = true
+			}
+		}
+		r.True(mainFound, "main package not found")
+		r.NotEmpty(report.Types)
+
+		again, err := f.Report()
+		r.NoError(err)
+		r.Equal(buf, again, "the report should be stable")
+
+		// The packages are sorted by name within each class.
+		for i := 1; i < len(report.Packages); i++ {
+			prev, p := report.Packages[i-1], report.Packages[i]
+			if prev.Class == p.Class {
+				r.Less(prev.Name, p.Name)
+			}
+		}
+
+		// The report doesn't depend on the map iteration order when the
+		// file is parsed again.
+		for i := 0; i < 3; i++ {
+			other, err := Open(exe)
+			r.NoError(err)
+			again, err = other.Report()
+			other.Close()
+			r.NoError(err)
+			r.Equal(buf, again, "the report should be the same for every parse")
+		}
+	})
+}
+
+func TestPCLNTabFunctionAddresses(t *testing.T) {
+	stripped := false
+	getMatrix(t, nil, &stripped, "pclntabFunctionAddresses", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		tab, err := f.PCLNTab()
+		r.NoError(err)
+
+		checked := 0
+		for _, fn := range tab.Funcs {
+			sym, err := f.GetSymbol(fn.Name)
+			if err != nil {
+				continue
+			}
+			// The assembly functions and their ABI wrappers share the same
+			// name in the pclntab.
+			if abi0, err := f.GetSymbol(fn.Name + ".abi0"); err == nil && abi0.Value == fn.Entry {
+				continue
+			}
+			r.Equal(sym.Value, fn.Entry, "incorrect address for %s", fn.Name)
+			checked++
+		}
+		r.NotZero(checked)
+
+		mainFn := tab.LookupFunc("main.main")
+		r.NotNil(mainFn)
+		file, _, _ := tab.PCToLine(mainFn.Entry)
+		r.Equal("a.go", filepath.Base(file))
+	})
+}
+
+func TestFindItab(t *testing.T) {
+	getMatrix(t, nil, nil, "findItab", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		itabs, err := f.GetItabs()
+		r.NoError(err)
+		r.NotEmpty(itabs)
+
+		for _, itab := range itabs {
+			found, err := f.FindItab(itab.Interface, itab.Type)
+			r.NoError(err)
+			r.Equal(itab.Address, found.Address)
+		}
+
+		_, err = f.FindItab(itabs[0].Type, itabs[0].Interface)
+		r.ErrorIs(err, ErrItabNotFound)
+	})
+}
+
+func TestContextCanceled(t *testing.T) {
+	getMatrix(t, nil, nil, "contextCanceled", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = f.GetTypesContext(ctx)
+		r.ErrorIs(err, context.Canceled)
+		_, err = f.GetPackagesContext(ctx)
+		r.ErrorIs(err, context.Canceled)
+
+		// The canceled enumeration is not cached.
+		pkgs, err := f.GetPackagesContext(context.Background())
+		r.NoError(err)
+		r.NotEmpty(pkgs)
+		typs, err := f.GetTypes()
+		r.NoError(err)
+		r.NotEmpty(typs)
+	})
+}
+
+func TestWriteWithSymbols(t *testing.T) {
+	getMatrix(t, nil, nil, "writeWithSymbols", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		tab, err := f.PCLNTab()
+		r.NoError(err)
+		mainFn := tab.LookupFunc("main.main")
+		r.NotNil(mainFn)
+
+		buf := new(bytes.Buffer)
+		err = f.WriteWithSymbols(buf, map[uint64]string{mainFn.Entry: "gore_main"})
+		if _, ok := f.GetParsedFile().(*elf.File); !ok {
+			r.ErrorIs(err, ErrUnsupportedFile)
+			return
+		}
+		r.NoError(err)
+
+		out, err := elf.NewFile(bytes.NewReader(buf.Bytes()))
+		r.NoError(err)
+		syms, err := out.Symbols()
+		r.NoError(err)
+
+		found := false
+		for _, s := range syms {
+			if s.Name == "gore_main" {
+				found = true
+				r.Equal(mainFn.Entry, s.Value)
+				r.Equal(elf.STT_FUNC, elf.ST_TYPE(s.Info))
+			}
+		}
+		r.True(found, "the added symbol was not found")
+	})
+}
+
+func TestExportScript(t *testing.T) {
+	getMatrix(t, nil, nil, "exportScript", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		tab, err := f.PCLNTab()
+		r.NoError(err)
+		mainFn := tab.LookupFunc("main.main")
+		r.NotNil(mainFn)
+
+		for _, format := range []string{ScriptFormatGhidra, ScriptFormatIDA} {
+			script, err := f.ExportScript(format)
+			r.NoError(err)
+			r.Contains(string(script), fmt.Sprintf("(0x%x, \"main.main\")", mainFn.Entry))
+		}
+	})
+}
+
 func TestGetCompilerVersion(t *testing.T) {
 	testVersion := testCompilerVersion()
 	expectedVersion := ResolveGoVersion(testVersion)
@@ -340,6 +736,56 @@ func TestDwarfString(t *testing.T) {
 	})
 }
 
+func TestDwarfProducer(t *testing.T) {
+	noStrip := false
+	getMatrix(t, nil, &noStrip, "dwarfProducer", func(t *testing.T, exe string) {
+		r := require.New(t)
+
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		producers, err := f.GetDWARFProducer()
+		r.NoError(err)
+		r.NotEmpty(producers)
+		r.Contains(producers[0], runtime.Version())
+	})
+}
+
+func TestCallersOf(t *testing.T) {
+	getMatrix(t, nil, nil, "callersOf", func(t *testing.T, exe string) {
+		r := require.New(t)
+
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		callers, err := f.CallersOf("main.getData")
+		r.NoError(err)
+		r.Len(callers, 1)
+		r.Equal("main", callers[0].Name)
+		r.Equal("main", callers[0].PackageName)
+	})
+}
+
+func TestHasFullTypeMetadata(t *testing.T) {
+	getMatrix(t, nil, nil, "fullTypeMetadata", func(t *testing.T, exe string) {
+		r := require.New(t)
+
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		// The test resource doesn't look up methods via reflection.
+		full, err := f.HasFullTypeMetadata()
+		r.NoError(err)
+		r.False(full)
+	})
+}
+
 type buildResult struct {
 	exe   string
 	dir   string
@@ -402,6 +848,26 @@ func buildTestResource(body, goos, arch string, pie, stripped bool, wg *sync.Wai
 	result <- buildResult{exe: exe, dir: tmpdir, strip: stripped, pie: pie, os: goos, arch: arch}
 }
 
+// buildTestProgram builds the program for the platform and returns the path
+// to the executable. The build inherits the environment, extended with env,
+// and gets the extra flags. The executable is removed when the test ends.
+func buildTestProgram(t *testing.T, src, goos, arch string, env []string, flags ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	srcFile := filepath.Join(dir, "a.go")
+	require.NoError(t, os.WriteFile(srcFile, []byte(src), 0644))
+
+	exe := filepath.Join(dir, "a")
+	args := append([]string{"build", "-o", exe}, flags...)
+	cmd := exec.Command("go", append(args, srcFile)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH="+arch)
+	cmd.Env = append(cmd.Env, env...)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "building the test program failed: %s", out)
+	return exe
+}
+
 func testCompilerVersion() string {
 	goBin, err := exec.LookPath("go")
 	if err != nil {
@@ -413,3 +879,761 @@ func testCompilerVersion() string {
 	}
 	return strings.Split(string(out), " ")[2]
 }
+
+func TestGetAssemblyFunctions(t *testing.T) {
+	getMatrix(t, nil, nil, "asmFunctions", func(t *testing.T, exe string) {
+		r := require.New(t)
+
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		fcns, err := f.GetAssemblyFunctions()
+		r.NoError(err)
+
+		names := make(map[string]bool)
+		for _, fn := range fcns {
+			names[fn.PackageName+"."+fn.Name] = true
+		}
+		r.True(names["runtime.memmove"])
+		r.True(names["runtime.rt0_go"])
+		r.False(names["main.main"])
+		r.False(names["main.getData"])
+	})
+}
+
+func TestRawPCLNTab(t *testing.T) {
+	getMatrix(t, nil, nil, "rawPclntab", func(t *testing.T, exe string) {
+		r := require.New(t)
+
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		addr, data, err := f.RawPCLNTab()
+		r.NoError(err)
+		r.NotZero(addr)
+		r.GreaterOrEqual(len(data), 8)
+
+		_, ok := pclntabVersion(f.FileInfo.ByteOrder.Uint32(data))
+		r.True(ok, "unknown pclntab magic")
+
+		// The data is located at the address.
+		head, err := f.Bytes(addr, 8)
+		r.NoError(err)
+		r.Equal(data[:8], head)
+	})
+}
+
+func TestUnreferencedFunctions(t *testing.T) {
+	getMatrix(t, nil, nil, "unreferenced", func(t *testing.T, exe string) {
+		r := require.New(t)
+
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		fcns, err := f.UnreferencedFunctions()
+		r.NoError(err)
+
+		names := make(map[string]bool)
+		for _, fn := range fcns {
+			names[fn.PackageName+"."+fn.Name] = true
+		}
+		// The runtime starts the main goroutine via a function value.
+		r.True(names["runtime.main"])
+		r.False(names["main.getData"])
+	})
+}
+
+func TestTypeIterator(t *testing.T) {
+	getMatrix(t, nil, nil, "typeIterator", func(t *testing.T, exe string) {
+		r := require.New(t)
+
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		types, err := f.GetTypes()
+		r.NoError(err)
+
+		it, err := f.NewTypeIterator()
+		r.NoError(err)
+		names := make(map[uint64]string)
+		for {
+			typ, ok := it.Next()
+			if !ok {
+				break
+			}
+			_, dup := names[typ.Addr]
+			r.False(dup, "type %s returned twice", typ)
+			names[typ.Addr] = typ.String()
+		}
+		r.NoError(it.Err())
+
+		r.Len(names, len(types))
+		for _, typ := range types {
+			r.Equal(typ.String(), names[typ.Addr])
+		}
+	})
+}
+
+func TestStartupChain(t *testing.T) {
+	getMatrix(t, nil, nil, "startupChain", func(t *testing.T, exe string) {
+		r := require.New(t)
+
+		f, err := Open(exe)
+		r.NoError(err)
+		r.NotNil(f)
+		defer f.Close()
+
+		chain, err := f.StartupChain()
+		r.NoError(err)
+		r.GreaterOrEqual(len(chain), 4)
+
+		r.True(strings.HasPrefix(chain[0].Name, "_rt0_"), chain[0].Name)
+		n := len(chain)
+		r.Equal("runtime.rt0_go", chain[n-3].PackageName+"."+chain[n-3].Name)
+		r.Equal("runtime.main", chain[n-2].PackageName+"."+chain[n-2].Name)
+		r.Equal("main.main", chain[n-1].PackageName+"."+chain[n-1].Name)
+	})
+}
+
+func TestGetTypesConcurrently(t *testing.T) {
+	getMatrix(t, nil, nil, "typesConcurrently", func(t *testing.T, exe string) {
+		r := require.New(t)
+		parse := func(concurrency int) []*GoType {
+			f, err := Open(exe)
+			r.NoError(err)
+			defer f.Close()
+			f.SetConcurrency(concurrency)
+			types, err := f.GetTypes()
+			r.NoError(err)
+			return types
+		}
+		serial, concurrent := parse(1), parse(4)
+		r.Equal(len(serial), len(concurrent))
+
+		byAddr := make(map[uint64]*GoType, len(concurrent))
+		for _, typ := range concurrent {
+			byAddr[typ.Addr] = typ
+		}
+		for i, typ := range concurrent {
+			r.Equal(serial[i].Addr, typ.Addr)
+			r.Equal(serial[i].String(), typ.String())
+			r.Len(typ.Fields, len(serial[i].Fields), typ.Name)
+
+			// The references point to the same types as the ones returned.
+			for _, ref := range append([]*GoType{typ.Element, typ.Key}, typ.FuncArgs...) {
+				if ref != nil && byAddr[ref.Addr] != nil {
+					r.Same(byAddr[ref.Addr], ref, typ.Name)
+				}
+			}
+		}
+	})
+}
+
+func TestCustomPackageClassifier(t *testing.T) {
+	getMatrix(t, nil, nil, "customPackageClassifier", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		defer f.Close()
+
+		r.NoError(f.SetPackageClassifier(constClassifier(ClassVendor)))
+		vendors, err := f.GetVendors()
+		r.NoError(err)
+		r.NotEmpty(vendors)
+		std, err := f.GetSTDLib()
+		r.NoError(err)
+		r.Empty(std)
+		class, err := f.ClassifyPackage("runtime", "")
+		r.NoError(err)
+		r.Equal(ClassVendor, class)
+
+		r.ErrorIs(f.SetPackageClassifier(nil), ErrPackagesInitialized)
+	})
+}
+
+func TestVerifyBuildID(t *testing.T) {
+	for _, test := range []struct {
+		goos, arch string
+		flags      []string
+	}{
+		{"linux", "amd64", nil},
+		{"linux", "amd64", []string{"-buildmode=pie"}},
+		{"linux", "amd64", []string{"-ldflags=-s -w"}},
+		{"linux", "386", nil},
+		{"linux", "arm64", nil},
+		{"windows", "amd64", nil},
+		{"windows", "386", nil},
+		{"darwin", "amd64", nil},
+		{"darwin", "arm64", nil},
+		{"wasip1", "wasm", nil},
+	} {
+		test := test
+		name := test.goos + "-" + test.arch + strings.Join(test.flags, "")
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			exe := buildTestProgram(t, testresourcesrc, test.goos, test.arch, nil, test.flags...)
+			f, err := Open(exe)
+			require.NoError(t, err)
+			defer f.Close()
+
+			ok, err := f.VerifyBuildID()
+			require.NoError(t, err)
+			assert.True(t, ok, "build ID of a freshly built binary")
+		})
+	}
+}
+
+func TestMaxSectionBytesDwarf(t *testing.T) {
+	nostrip := false
+	getMatrix(t, nil, &nostrip, "maxSectionBytesDwarf", func(t *testing.T, exe string) {
+		f, err := Open(exe)
+		require.NoError(t, err)
+		defer f.Close()
+		_, err = f.fh.getDwarf()
+		require.NoError(t, err)
+
+		f, err = OpenWithOptions(exe, Options{MaxSectionBytes: 1})
+		require.NoError(t, err)
+		defer f.Close()
+		_, err = f.fh.getDwarf()
+		assert.ErrorIs(t, err, ErrSectionTooLarge)
+	})
+}
+
+func TestGetStringsNotInText(t *testing.T) {
+	getMatrix(t, nil, nil, "getStringsNotInText", func(t *testing.T, exe string) {
+		f, err := Open(exe)
+		require.NoError(t, err)
+		defer f.Close()
+
+		strs, err := f.GetStrings()
+		require.NoError(t, err)
+		require.NotEmpty(t, strs)
+
+		sects, err := f.Sections()
+		require.NoError(t, err)
+		for _, sect := range sects {
+			if sect.Name != ".text" && sect.Name != "__text" {
+				continue
+			}
+			for _, s := range strs {
+				assert.False(t, s.Address >= sect.VirtualAddress && s.Address < sect.VirtualAddress+sect.Size,
+					"%q at 0x%x is in %s", s.Value, s.Address, sect.Name)
+			}
+		}
+	})
+}
+
+func TestGetGoSymtab(t *testing.T) {
+	getMatrix(t, nil, nil, "goSymtab", func(t *testing.T, exe string) {
+		f, err := Open(exe)
+		require.NoError(t, err)
+		defer f.Close()
+
+		data, err := f.GetGoSymtab()
+		if f.FileInfo.OS == "windows" && !strings.Contains(t.Name(), "nostrip") {
+			// The table is only found with the symbols since PE files
+			// don't have a section for it.
+			assert.ErrorIs(t, err, ErrSectionDoesNotExist)
+			return
+		}
+		require.NoError(t, err)
+		// The table is empty since Go 1.3.
+		assert.NotNil(t, data)
+		assert.Empty(t, data)
+
+		// The data can be used to construct a symbol table.
+		expected, err := f.PCLNTab()
+		require.NoError(t, err)
+		tab, err := gosym.NewTable(data, gosym.NewLineTable(f.pclntabBytes, f.runtimeText))
+		require.NoError(t, err)
+		assert.Equal(t, len(expected.Funcs), len(tab.Funcs))
+		assert.NotNil(t, tab.LookupFunc("main.main"))
+	})
+}
+
+// inlineSrc is a program where inner is inlined into outer, which is
+// inlined into main.
+const inlineSrc = `package main
+
+import "os"
+
+func inner(x int) int { return x*3 + len(os.Args[0]) }
+
+func outer(x int) int { return inner(x) + 2 }
+
+//go:noinline
+func sink(x int) { println(x) }
+
+func main() {
+	sink(outer(len(os.Args)))
+}
+`
+
+func TestPCToInlineStack(t *testing.T) {
+	for _, test := range []struct{ goos, arch string }{
+		{"linux", "amd64"},
+		{"linux", "arm64"},
+		{"windows", "amd64"},
+		{"darwin", "amd64"},
+	} {
+		test := test
+		t.Run(test.goos+"-"+test.arch, func(t *testing.T) {
+			t.Parallel()
+			exe := buildTestProgram(t, inlineSrc, test.goos, test.arch, nil)
+			f, err := Open(exe)
+			require.NoError(t, err)
+			defer f.Close()
+
+			tab, err := f.PCLNTab()
+			require.NoError(t, err)
+			fn := tab.LookupFunc("main.main")
+			require.NotNil(t, fn)
+
+			// Find an instruction of inner in main.
+			var frames []Frame
+			for pc := fn.Entry; pc < fn.End && len(frames) != 3; pc++ {
+				frames, err = f.PCToInlineStack(pc)
+				require.NoError(t, err)
+			}
+			require.Len(t, frames, 3, "no pc in the inlined inner function")
+
+			for i, expected := range []struct {
+				name    string
+				line    int
+				inlined bool
+			}{
+				{"main.inner", 5, true},
+				{"main.outer", 7, true},
+				{"main.main", 13, false},
+			} {
+				assert.Equal(t, expected.name, frames[i].Function)
+				assert.Equal(t, expected.line, frames[i].Line, expected.name)
+				assert.Equal(t, expected.inlined, frames[i].Inlined, expected.name)
+				assert.Equal(t, "a.go", filepath.Base(frames[i].File), expected.name)
+			}
+		})
+	}
+}
+
+// dwarfTypeSrc is a program with a struct type that is kept in the binary.
+const dwarfTypeSrc = `package main
+
+import "fmt"
+
+type point struct {
+	X    int
+	Name string
+	tags []byte
+	next *point
+}
+
+func main() {
+	p := &point{X: 1, Name: "a"}
+	fmt.Println(p)
+}
+`
+
+func TestGetDWARFTypes(t *testing.T) {
+	for _, test := range []struct{ goos, arch string }{
+		{"linux", "amd64"},
+		{"windows", "amd64"},
+		{"darwin", "amd64"},
+	} {
+		test := test
+		t.Run(test.goos+"-"+test.arch, func(t *testing.T) {
+			t.Parallel()
+			exe := buildTestProgram(t, dwarfTypeSrc, test.goos, test.arch, nil)
+			f, err := Open(exe)
+			require.NoError(t, err)
+			defer f.Close()
+
+			types, err := f.GetDWARFTypes()
+			require.NoError(t, err)
+			idx := slices.IndexFunc(types, func(typ *GoType) bool { return typ.Name == "main.point" })
+			require.NotEqual(t, -1, idx, "main.point not found")
+			typ := types[idx]
+
+			assert.Equal(t, reflect.Struct, typ.Kind)
+			assert.Equal(t, "main", typ.PackagePath)
+			require.Len(t, typ.Fields, 4)
+			for i, expected := range []struct {
+				name     string
+				kind     reflect.Kind
+				offset   uint64
+				exported bool
+			}{
+				{"X", reflect.Int, 0, true},
+				{"Name", reflect.String, 8, true},
+				{"tags", reflect.Slice, 24, false},
+				{"next", reflect.Ptr, 48, false},
+			} {
+				field := typ.Fields[i]
+				assert.Equal(t, expected.name, field.FieldName)
+				assert.Equal(t, expected.kind, field.Kind, expected.name)
+				assert.Equal(t, expected.offset, field.FieldOffset, expected.name)
+				assert.Equal(t, expected.exported, field.FieldExported, expected.name)
+			}
+			require.NotNil(t, typ.Fields[2].Element)
+			assert.Equal(t, reflect.Uint8, typ.Fields[2].Element.Kind)
+			require.NotNil(t, typ.Fields[3].Element)
+			assert.Same(t, typ, typ.Fields[3].Element)
+
+			// The runtime type is the one returned by GetTypes.
+			rtypes, err := f.GetTypes()
+			require.NoError(t, err)
+			idx = slices.IndexFunc(rtypes, func(rt *GoType) bool { return rt.Addr == typ.Addr })
+			require.NotEqual(t, -1, idx, "no runtime type at 0x%x", typ.Addr)
+			assert.Equal(t, "main.point", rtypes[idx].Name)
+		})
+	}
+}
+
+// goStatementSrc is a program launching a goroutine without arguments, so
+// the go statement launches the function directly.
+const goStatementSrc = `package main
+
+import "time"
+
+func worker() { time.Sleep(time.Hour) }
+
+func main() {
+	go worker()
+	time.Sleep(time.Millisecond)
+}
+`
+
+func TestGetGoroutineLaunchSites(t *testing.T) {
+	for _, test := range []struct{ goos, arch string }{
+		{"linux", "amd64"},
+		{"linux", "386"},
+		{"linux", "arm64"},
+		{"windows", "amd64"},
+		{"darwin", "amd64"},
+	} {
+		test := test
+		t.Run(test.goos+"-"+test.arch, func(t *testing.T) {
+			t.Parallel()
+			exe := buildTestProgram(t, goStatementSrc, test.goos, test.arch, nil)
+			f, err := Open(exe)
+			require.NoError(t, err)
+			defer f.Close()
+
+			sites, err := f.GetGoroutineLaunchSites()
+			require.NoError(t, err)
+			var found []GoroutineSite
+			for _, s := range sites {
+				if s.Caller.PackageName == "main" && s.Caller.Name == "main" {
+					found = append(found, s)
+				}
+			}
+			require.Len(t, found, 1)
+			require.NotNil(t, found[0].Target)
+			assert.Equal(t, "main", found[0].Target.PackageName)
+			assert.Equal(t, "worker", found[0].Target.Name)
+			assert.True(t, found[0].PC >= found[0].Caller.Offset && found[0].PC < found[0].Caller.End)
+		})
+	}
+}
+
+// cSharedSrc is a library exporting a function to C.
+const cSharedSrc = `package main
+
+import "C"
+
+//export GoreAdd
+func GoreAdd(a, b C.int) C.int { return a + b }
+
+func main() {}
+`
+
+// wasmExportSrc is a WebAssembly library exporting a function to the host.
+const wasmExportSrc = `package main
+
+//go:wasmexport goreAdd
+func goreAdd(a, b int32) int32 { return a + b }
+
+func main() {}
+`
+
+func TestGetExportsCShared(t *testing.T) {
+	for _, test := range []struct {
+		goos, arch, cc string
+		// native is set if the C compiler only builds for the host.
+		native bool
+		// name is the exported name of GoreAdd.
+		name string
+	}{
+		{"linux", "amd64", "gcc", true, "GoreAdd"},
+		{"windows", "amd64", "x86_64-w64-mingw32-gcc", false, "GoreAdd"},
+		{"darwin", runtime.GOARCH, "clang", true, "_GoreAdd"},
+	} {
+		test := test
+		t.Run(test.goos+"-"+test.arch, func(t *testing.T) {
+			if test.native && (test.goos != runtime.GOOS || test.arch != runtime.GOARCH) {
+				t.Skip("the C compiler can't build for the target")
+			}
+			if _, err := exec.LookPath(test.cc); err != nil {
+				t.Skipf("%s not found", test.cc)
+			}
+			exe := buildTestProgram(t, cSharedSrc, test.goos, test.arch, []string{"CGO_ENABLED=1", "CC=" + test.cc}, "-buildmode=c-shared")
+			f, err := Open(exe)
+			require.NoError(t, err)
+			defer f.Close()
+
+			exports, err := f.GetExports()
+			require.NoError(t, err)
+			var export, cgoexp *Export
+			for i, e := range exports {
+				switch {
+				case e.Name == test.name:
+					export = &exports[i]
+				case strings.HasPrefix(e.Name, "_cgoexp_") && strings.HasSuffix(e.Name, "_GoreAdd"):
+					cgoexp = &exports[i]
+				}
+			}
+			require.NotNil(t, export, "GoreAdd is not exported")
+			assert.NotZero(t, export.Address)
+
+			// The C wrapper calls the Go function through the exported
+			// _cgoexp function.
+			if cgoexp != nil {
+				fn, err := f.FunctionForAddress(cgoexp.Address)
+				require.NoError(t, err)
+				assert.Equal(t, cgoexp.Address, fn.Offset)
+			}
+		})
+	}
+
+	t.Run("wasip1-wasm", func(t *testing.T) {
+		if GoVersionCompare(testCompilerVersion(), "go1.24") < 0 {
+			t.Skip("go:wasmexport requires Go 1.24")
+		}
+		exe := buildTestProgram(t, wasmExportSrc, "wasip1", "wasm", nil, "-buildmode=c-shared")
+		f, err := Open(exe)
+		require.NoError(t, err)
+		defer f.Close()
+
+		exports, err := f.GetExports()
+		require.NoError(t, err)
+		names := make(map[string]uint64)
+		for _, e := range exports {
+			names[e.Name] = e.Address
+		}
+		require.Contains(t, names, "goreAdd")
+		require.Contains(t, names, "_initialize")
+		assert.Zero(t, names["goreAdd"]&0xffff, "the block number is not zero")
+	})
+}
+
+// methodWrapperSrc is a program with a method value, which is wrapped by
+// T.Name-fm, and methods called through an interface, which need the
+// pointer receiver and promoted method wrappers.
+const methodWrapperSrc = `package main
+
+import "fmt"
+
+type inner struct{ n int }
+
+//go:noinline
+func (i inner) Value() int { return i.n }
+
+type T struct {
+	inner
+	name string
+}
+
+//go:noinline
+func (t T) Name() string { return t.name }
+
+type valuer interface{ Value() int }
+
+//go:noinline
+func call(f func() string) string { return f() }
+
+//go:noinline
+func value(v valuer) int { return v.Value() }
+
+func main() {
+	t := &T{inner{1}, "t"}
+	fmt.Println(call(t.Name), value(t), value(t.inner))
+}
+`
+
+func TestMethodWrappers(t *testing.T) {
+	exe := buildTestProgram(t, methodWrapperSrc, "linux", "amd64", nil)
+	f, err := Open(exe)
+	require.NoError(t, err)
+	defer f.Close()
+
+	pkgs, err := f.GetPackages()
+	require.NoError(t, err)
+	idx := slices.IndexFunc(pkgs, func(p *Package) bool { return p.Name == "main" })
+	require.NotEqual(t, -1, idx)
+	mainPkg := pkgs[idx]
+
+	names := func(methods []*Method) map[string]bool {
+		m := make(map[string]bool)
+		for _, meth := range methods {
+			m[meth.Receiver+"."+meth.Name] = meth.IsMethodWrapper()
+		}
+		return m
+	}
+	assert.Equal(t, map[string]bool{
+		"inner.Value":    false,
+		"T.Name":         false,
+		"T.Name-fm":      true,
+		"T.Value":        true,
+		"(*T).Value":     true,
+		"(*inner).Value": true,
+	}, names(mainPkg.Methods))
+
+	filtered := mainPkg.WithoutMethodWrappers()
+	assert.Equal(t, map[string]bool{
+		"inner.Value": false,
+		"T.Name":      false,
+	}, names(filtered.Methods))
+	assert.Len(t, filtered.Functions, len(mainPkg.Functions))
+}
+
+// coreDumpSrc is a program that aborts itself while its workers are
+// blocked. The workers are launched without arguments so the compiler
+// doesn't wrap them in closures.
+const coreDumpSrc = `package main
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+var c = make(chan int)
+
+func sleeper() {
+	time.Sleep(time.Hour)
+}
+
+func blocker() {
+	<-c
+}
+
+func main() {
+	for i := 0; i < 3; i++ {
+		go sleeper()
+		go blocker()
+	}
+	time.Sleep(100 * time.Millisecond)
+	syscall.Kill(os.Getpid(), syscall.SIGABRT)
+	time.Sleep(time.Second)
+}
+`
+
+func TestReadGoroutinesFromCore(t *testing.T) {
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		t.Skip("core dumps are only produced on linux/amd64")
+	}
+	pattern, err := os.ReadFile("/proc/sys/kernel/core_pattern")
+	if err != nil || bytes.HasPrefix(pattern, []byte("|")) || bytes.ContainsRune(pattern, '/') {
+		t.Skip("core dumps are not written to the working directory")
+	}
+
+	for _, pie := range []bool{false, true} {
+		pie := pie
+		t.Run(fmt.Sprintf("pie=%v", pie), func(t *testing.T) {
+			var flags []string
+			if pie {
+				flags = append(flags, "-buildmode=pie")
+			}
+			exe := buildTestProgram(t, coreDumpSrc, "linux", "amd64", nil, flags...)
+			dir := filepath.Dir(exe)
+
+			// Allow the crashing process to write the core dump.
+			cmd := exec.Command("sh", "-c", "ulimit -c unlimited && exec "+exe)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOTRACEBACK=crash")
+			_ = cmd.Run()
+			cores, _ := filepath.Glob(filepath.Join(dir, "core*"))
+			if len(cores) == 0 {
+				t.Skip("no core dump was written")
+			}
+
+			core, err := os.Open(cores[0])
+			require.NoError(t, err)
+			defer core.Close()
+			f, err := OpenWithOptions(exe, Options{Core: core})
+			require.NoError(t, err)
+			defer f.Close()
+
+			gs, err := f.ReadGoroutines()
+			require.NoError(t, err)
+			starts := make(map[string]int)
+			for _, g := range gs {
+				if g.StartFunction != nil {
+					starts[g.StartFunction.PackageName+"."+g.StartFunction.Name]++
+				}
+			}
+			assert.Equal(t, 1, starts["runtime.main"], "main goroutine")
+			assert.Equal(t, 3, starts["main.sleeper"])
+			assert.Equal(t, 3, starts["main.blocker"])
+		})
+	}
+}
+
+func TestContiguousText(t *testing.T) {
+	getMatrix(t, nil, nil, "contiguousText", func(t *testing.T, exe string) {
+		r := require.New(t)
+		f, err := Open(exe)
+		r.NoError(err)
+		defer f.Close()
+		tab, err := f.PCLNTab()
+		r.NoError(err)
+		_, raw, err := f.RawPCLNTab()
+		r.NoError(err)
+
+		// Split the text in the middle and move the second half up as if the
+		// linker had placed it in a second text section.
+		const gap = 0x100000
+		text := f.runtimeText
+		split := tab.Funcs[len(tab.Funcs)/2].Entry - text
+		end := tab.Funcs[len(tab.Funcs)-1].End - text
+		ptab, err := newPclnTable(raw, f.FileInfo.ByteOrder, text)
+		r.NoError(err)
+		ptab.textSections = []TextSection{
+			{Offset: 0, Length: split, Address: text},
+			{Offset: split, Length: end - split, Address: text + split + gap},
+		}
+		data, err := ptab.contiguousText()
+		r.NoError(err)
+		moved, err := gosym.NewTable(nil, gosym.NewLineTable(data, text))
+		r.NoError(err)
+
+		r.Len(moved.Funcs, len(tab.Funcs))
+		for i, fn := range tab.Funcs {
+			want := fn.Entry
+			if fn.Entry-text >= split {
+				want += gap
+			}
+			r.Equal(want, moved.Funcs[i].Entry, fn.Name)
+			// debug/gosym ends a function at the next one, so the last
+			// function of the first section spans the gap.
+			if fn.End-text != split {
+				r.Equal(fn.End-fn.Entry, moved.Funcs[i].End-moved.Funcs[i].Entry, fn.Name)
+			}
+
+			file, line, got := moved.PCToLine(want)
+			r.NotNil(got, fn.Name)
+			r.Equal(fn.Name, got.Name)
+			wantFile, wantLine, _ := tab.PCToLine(fn.Entry)
+			r.Equal(wantFile, file, fn.Name)
+			r.Equal(wantLine, line, fn.Name)
+		}
+	})
+}
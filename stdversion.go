@@ -0,0 +1,158 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import "fmt"
+
+// stdlibVersionMarker is a standard library package that was only part of a
+// range of Go releases.
+type stdlibVersionMarker struct {
+	// pkg is the name of the package.
+	pkg string
+	// added is the first release with the package.
+	added string
+	// removed is the first release without the package. It's empty if the
+	// package is still part of the standard library.
+	removed string
+	// alwaysLinked is true if the package is linked into every binary since
+	// the release in added, for example because the runtime depends on it.
+	// The absence of the package then means the binary is older.
+	alwaysLinked string
+}
+
+// stdlibVersionMarkers are the packages used to estimate the Go version. The
+// releases are taken from the source trees of the Go distributions. For the
+// packages linked into every binary, the release is the first one where the
+// package has code that ends up in the binary, which can be later than when
+// the package was added.
+var stdlibVersionMarkers = []stdlibVersionMarker{
+	{pkg: "internal/bytealg", added: "go1.11", alwaysLinked: "go1.11"},
+	{pkg: "internal/fmtsort", added: "go1.12"},
+	{pkg: "internal/reflectlite", added: "go1.13"},
+	{pkg: "internal/oserror", added: "go1.13"},
+	{pkg: "internal/unsafeheader", added: "go1.15"},
+	{pkg: "io/fs", added: "go1.16"},
+	{pkg: "embed", added: "go1.16"},
+	{pkg: "internal/abi", added: "go1.17", alwaysLinked: "go1.21.0"},
+	{pkg: "internal/itoa", added: "go1.17", removed: "go1.26.0"},
+	{pkg: "internal/goarch", added: "go1.18"},
+	{pkg: "internal/goos", added: "go1.18"},
+	{pkg: "internal/godebug", added: "go1.18"},
+	{pkg: "net/netip", added: "go1.18"},
+	{pkg: "internal/safefilepath", added: "go1.18", removed: "go1.23.0"},
+	{pkg: "runtime/internal/syscall", added: "go1.18", removed: "go1.23.0"},
+	{pkg: "crypto/ecdh", added: "go1.20"},
+	{pkg: "cmp", added: "go1.21.0"},
+	{pkg: "slices", added: "go1.21.0"},
+	{pkg: "maps", added: "go1.21.0"},
+	{pkg: "log/slog", added: "go1.21.0"},
+	{pkg: "internal/bisect", added: "go1.21.0"},
+	{pkg: "internal/godebugs", added: "go1.21.0"},
+	{pkg: "math/rand/v2", added: "go1.22.0"},
+	{pkg: "internal/chacha8rand", added: "go1.22.0", alwaysLinked: "go1.22.0"},
+	{pkg: "iter", added: "go1.22.0"},
+	{pkg: "unique", added: "go1.23.0"},
+	{pkg: "structs", added: "go1.23.0"},
+	{pkg: "internal/runtime/atomic", added: "go1.23.0", alwaysLinked: "go1.23.0"},
+	{pkg: "internal/runtime/exithook", added: "go1.23.0"},
+	{pkg: "internal/runtime/syscall", added: "go1.23.0", removed: "go1.26.0"},
+	{pkg: "internal/stringslite", added: "go1.23.0"},
+	{pkg: "internal/filepathlite", added: "go1.23.0"},
+	{pkg: "internal/byteorder", added: "go1.23.0"},
+	{pkg: "runtime/internal/atomic", removed: "go1.23.0"},
+	{pkg: "runtime/internal/sys", removed: "go1.24.0"},
+	{pkg: "internal/runtime/sys", added: "go1.24.0"},
+	{pkg: "internal/runtime/math", added: "go1.24.0"},
+	{pkg: "internal/runtime/maps", added: "go1.24.0"},
+	{pkg: "internal/sync", added: "go1.24.0"},
+	{pkg: "weak", added: "go1.24.0"},
+	{pkg: "crypto/mlkem", added: "go1.24.0"},
+	{pkg: "crypto/sha3", added: "go1.24.0"},
+	{pkg: "crypto/hkdf", added: "go1.24.0"},
+	{pkg: "internal/runtime/cgroup", added: "go1.25.0"},
+	{pkg: "internal/runtime/gc", added: "go1.25.0"},
+	{pkg: "internal/strconv", added: "go1.26.0"},
+}
+
+// EstimateVersionFromStdlib estimates the version of the Go compiler from the
+// standard library packages in the binary. It's a last resort for binaries
+// where both the build information and the version string have been removed.
+// Packages that were added to the standard library give the oldest release
+// that can have compiled the binary, which is the version returned. Packages
+// that were removed from the standard library, and packages linked into every
+// binary that are missing, give the newest release. If they contradict each
+// other or if none of the packages are found, an error wrapping
+// ErrNoGoVersionFound is returned.
+// The estimate only covers Go 1.9 and later, and since a package can be
+// missing because the code doesn't use it, the version can be older than the
+// actual version.
+func (f *GoFile) EstimateVersionFromStdlib() (*GoVersion, error) {
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+	// Standard library packages newer than the library's list of packages are
+	// classified as vendor or unknown packages.
+	pkgs := make(map[string]bool)
+	for _, class := range [][]*Package{f.stdPkgs, f.vendors, f.unknown} {
+		for _, p := range class {
+			pkgs[p.Name] = true
+		}
+	}
+
+	oldest, newest := stdlibVersionRange(pkgs)
+	if oldest == "" {
+		return nil, fmt.Errorf("no version specific standard library packages found: %w", ErrNoGoVersionFound)
+	}
+	if newest != "" && GoVersionCompare(oldest, newest) >= 0 {
+		return nil, fmt.Errorf("the standard library packages require %s or later but also a release before %s: %w", oldest, newest, ErrNoGoVersionFound)
+	}
+	if ver := ResolveGoVersion(oldest); ver != nil {
+		return ver, nil
+	}
+	return &GoVersion{Name: oldest}, nil
+}
+
+// stdlibVersionRange returns the oldest release that can have compiled a
+// binary with the packages and the first release that can't. Either is empty
+// if the packages don't limit the range in that direction.
+func stdlibVersionRange(pkgs map[string]bool) (oldest, newest string) {
+	lower := func(v string) {
+		if oldest == "" || GoVersionCompare(v, oldest) > 0 {
+			oldest = v
+		}
+	}
+	upper := func(v string) {
+		if newest == "" || GoVersionCompare(v, newest) < 0 {
+			newest = v
+		}
+	}
+	for _, m := range stdlibVersionMarkers {
+		if pkgs[m.pkg] {
+			if m.added != "" {
+				lower(m.added)
+			}
+			if m.removed != "" {
+				upper(m.removed)
+			}
+		} else if m.alwaysLinked != "" {
+			upper(m.alwaysLinked)
+		}
+	}
+	return oldest, newest
+}
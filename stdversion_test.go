@@ -0,0 +1,74 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdlibVersionRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		pkgs   []string
+		oldest string
+		newest string
+	}{
+		{"none", []string{"fmt", "os"}, "", "go1.11"},
+		{"go1.13", []string{"internal/bytealg", "internal/reflectlite", "runtime/internal/sys"}, "go1.13", "go1.21.0"},
+		{"go1.21", []string{"internal/bytealg", "internal/abi", "slices", "runtime/internal/atomic"}, "go1.21.0", "go1.22.0"},
+		{"go1.23", []string{"internal/bytealg", "internal/abi", "internal/chacha8rand", "internal/runtime/atomic", "iter", "runtime/internal/sys"}, "go1.23.0", "go1.24.0"},
+		{"go1.26", []string{"internal/bytealg", "internal/abi", "internal/chacha8rand", "internal/runtime/atomic", "internal/strconv"}, "go1.26.0", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pkgs := make(map[string]bool)
+			for _, p := range test.pkgs {
+				pkgs[p] = true
+			}
+			oldest, newest := stdlibVersionRange(pkgs)
+			assert.Equal(t, test.oldest, oldest)
+			assert.Equal(t, test.newest, newest)
+		})
+	}
+}
+
+func TestEstimateVersionFromStdlib(t *testing.T) {
+	newFile := func(names ...string) *GoFile {
+		f := &GoFile{}
+		for _, n := range names {
+			f.stdPkgs = append(f.stdPkgs, &Package{Name: n})
+		}
+		// The packages are already set.
+		f.initPackagesDone = true
+		return f
+	}
+
+	ver, err := newFile("internal/bytealg", "internal/fmtsort").EstimateVersionFromStdlib()
+	assert.NoError(t, err)
+	assert.Equal(t, "go1.12", ver.Name)
+
+	_, err = newFile("fmt").EstimateVersionFromStdlib()
+	assert.ErrorIs(t, err, ErrNoGoVersionFound)
+
+	// The unique package requires Go 1.23 but the missing chacha8rand
+	// package means the binary is older than Go 1.22.
+	_, err = newFile("internal/bytealg", "internal/abi", "unique").EstimateVersionFromStdlib()
+	assert.ErrorIs(t, err, ErrNoGoVersionFound)
+}
@@ -1,7 +1,9 @@
 package gore
 
 import (
+	"cmp"
 	"errors"
+	"slices"
 )
 
 var ErrSymbolNotFound = errors.New("symbol not found")
@@ -15,3 +17,32 @@ type Symbol struct {
 	// Size of the symbol. Only accurate on ELF files. For Mach-O and PE files, it was inferred by looking at the next symbol.
 	Size uint64
 }
+
+// ComputeSymbolSizes infers the size of the symbols by looking at where the
+// next symbol begins. The symbols are returned sorted by address in a new
+// slice. The last symbol has a size of 0 since there is no next symbol. This
+// can be used for symbols from sources that don't record the size, for
+// example map files or the symbol tables of PE and Mach-O files.
+func ComputeSymbolSizes(symbols []Symbol) []Symbol {
+	syms := slices.Clone(symbols)
+	slices.SortStableFunc(syms, func(a, b Symbol) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+
+	for i := 0; i < len(syms)-1; i++ {
+		syms[i].Size = syms[i+1].Value - syms[i].Value
+	}
+	if len(syms) > 0 {
+		syms[len(syms)-1].Size = 0
+	}
+	return syms
+}
+
+// symbolNames returns the names of the symbols in the symbol table.
+func symbolNames(symm map[string]Symbol) []string {
+	names := make([]string, 0, len(symm))
+	for name := range symm {
+		names = append(names, name)
+	}
+	return names
+}
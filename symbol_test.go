@@ -0,0 +1,28 @@
+package gore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeSymbolSizes(t *testing.T) {
+	assert := assert.New(t)
+
+	syms := []Symbol{
+		{Name: "c", Value: 0x1030},
+		{Name: "a", Value: 0x1000},
+		{Name: "b", Value: 0x1010},
+	}
+	sized := ComputeSymbolSizes(syms)
+
+	assert.Equal([]Symbol{
+		{Name: "a", Value: 0x1000, Size: 0x10},
+		{Name: "b", Value: 0x1010, Size: 0x20},
+		{Name: "c", Value: 0x1030, Size: 0},
+	}, sized)
+
+	// The input is not modified.
+	assert.Equal("c", syms[0].Name)
+	assert.Empty(ComputeSymbolSizes(nil))
+}
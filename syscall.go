@@ -0,0 +1,179 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"debug/elf"
+	"slices"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// syscallWrappers are the functions that take the syscall number as their
+// first argument.
+var syscallWrappers = []string{
+	"syscall.Syscall",
+	"syscall.Syscall6",
+	"syscall.RawSyscall",
+	"syscall.RawSyscall6",
+	"syscall.rawSyscallNoError",
+	"syscall.rawVforkSyscall",
+	"runtime/internal/syscall.Syscall6",
+	"internal/runtime/syscall.Syscall6",
+	"internal/runtime/syscall/linux.Syscall6",
+	"golang.org/x/sys/unix.Syscall",
+	"golang.org/x/sys/unix.Syscall6",
+	"golang.org/x/sys/unix.RawSyscall",
+	"golang.org/x/sys/unix.RawSyscall6",
+	"golang.org/x/sys/unix.SyscallNoError",
+	"golang.org/x/sys/unix.RawSyscallNoError",
+}
+
+// DetectSyscalls returns the numbers of the syscalls the binary potentially
+// makes. The code is scanned for syscall instructions and for calls to the
+// syscall wrappers, like syscall.Syscall, and the syscall number is taken
+// from the constant loaded as the first argument before the instruction or
+// the call. Syscalls where the number is computed at runtime are not found,
+// so the result is a heuristic. The numbers are returned sorted.
+// Only Linux amd64 binaries are supported. For other binaries,
+// ErrArchNotSupported is returned.
+func (f *GoFile) DetectSyscalls() ([]int, error) {
+	if !f.isLinuxELF() || f.FileInfo.Arch != ArchAMD64 {
+		return nil, ErrArchNotSupported
+	}
+	err := f.initPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	wrappers := make(map[uint64]bool)
+	for _, name := range syscallWrappers {
+		if fn := f.pclntab.LookupFunc(name); fn != nil {
+			wrappers[fn.Entry] = true
+		}
+	}
+
+	var nums []int
+	for _, fn := range f.functionsByEntry() {
+		if fn.End <= fn.Offset {
+			continue
+		}
+		buf, err := f.Bytes(fn.Offset, fn.End-fn.Offset)
+		if err != nil {
+			continue
+		}
+		nums = append(nums, x86Syscalls(buf, fn.Offset, wrappers)...)
+	}
+	slices.Sort(nums)
+	return slices.Compact(nums), nil
+}
+
+// isLinuxELF returns true if the file is an ELF file built for Linux. The
+// target is taken from the build settings if available. Otherwise, the ELF
+// OS ABI is used which is not set for Linux but is for the BSDs.
+func (f *GoFile) isLinuxELF() bool {
+	e, ok := f.fh.(*elfFile)
+	if !ok {
+		return false
+	}
+	if f.BuildInfo != nil && f.BuildInfo.ModInfo != nil {
+		for _, s := range f.BuildInfo.ModInfo.Settings {
+			if s.Key == "GOOS" {
+				return s.Value == "linux" || s.Value == "android"
+			}
+		}
+	}
+	return e.file.OSABI == elf.ELFOSABI_NONE || e.file.OSABI == elf.ELFOSABI_LINUX
+}
+
+// x86Syscalls returns the syscall numbers used by the amd64 code. The
+// number is tracked in the register used for the first argument, which is
+// AX for both the syscall instruction and the register based calling
+// convention, and in the first stack slot used by the stack based calling
+// convention:
+//
+//	MOVL $1, AX
+//	SYSCALL
+//
+//	MOVL $1, AX
+//	CALL syscall.Syscall
+//
+//	MOVQ $1, 0(SP)
+//	CALL syscall.Syscall
+func x86Syscalls(buf []byte, start uint64, wrappers map[uint64]bool) []int {
+	var nums []int
+	ax, sp := -1, -1
+	s := 0
+	for s < len(buf) {
+		inst, err := x86asm.Decode(buf[s:], 64)
+		if err != nil {
+			s++
+			continue
+		}
+		next := start + uint64(s) + uint64(inst.Len)
+		s += inst.Len
+
+		switch {
+		case inst.Op == x86asm.SYSCALL:
+			if ax >= 0 {
+				nums = append(nums, ax)
+			}
+			ax = -1
+		case inst.Op == x86asm.CALL:
+			if rel, ok := inst.Args[0].(x86asm.Rel); ok && wrappers[uint64(int64(next)+int64(rel))] {
+				if ax >= 0 {
+					nums = append(nums, ax)
+				} else if sp >= 0 {
+					nums = append(nums, sp)
+				}
+			}
+			// The called function clobbers the registers and the
+			// arguments.
+			ax, sp = -1, -1
+		case inst.Op == x86asm.XOR && isAXReg(inst.Args[0]) && inst.Args[0] == inst.Args[1]:
+			ax = 0
+		case inst.Op == x86asm.MOV && isAXReg(inst.Args[0]):
+			ax = -1
+			if imm, ok := inst.Args[1].(x86asm.Imm); ok && imm >= 0 {
+				ax = int(imm)
+			}
+		case inst.Op == x86asm.MOV && isFirstStackSlot(inst.Args[0]):
+			sp = -1
+			if imm, ok := inst.Args[1].(x86asm.Imm); ok && imm >= 0 {
+				sp = int(imm)
+			}
+		case isAXReg(inst.Args[0]):
+			// Any other write to the register.
+			ax = -1
+		}
+	}
+	return nums
+}
+
+func isAXReg(arg x86asm.Arg) bool {
+	switch arg {
+	case x86asm.AL, x86asm.AX, x86asm.EAX, x86asm.RAX:
+		return true
+	}
+	return false
+}
+
+func isFirstStackSlot(arg x86asm.Arg) bool {
+	m, ok := arg.(x86asm.Mem)
+	return ok && m.Base == x86asm.RSP && m.Index == 0 && m.Disp == 0
+}
@@ -0,0 +1,44 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestX86Syscalls(t *testing.T) {
+	code := []byte{
+		0xb8, 0x01, 0x00, 0x00, 0x00, // MOVL $1, AX
+		0x0f, 0x05, // SYSCALL
+		0x31, 0xc0, // XORL AX, AX
+		0x0f, 0x05, // SYSCALL
+		0x48, 0xc7, 0x04, 0x24, 0x3c, 0x00, 0x00, 0x00, // MOVQ $60, 0(SP)
+		0xe8, 0xe8, 0x0f, 0x00, 0x00, // CALL 0x2000
+		0xb8, 0x27, 0x00, 0x00, 0x00, // MOVL $39, AX
+		0xe8, 0xde, 0x0f, 0x00, 0x00, // CALL 0x2000
+		0xb8, 0x02, 0x00, 0x00, 0x00, // MOVL $2, AX
+		0xe8, 0xd4, 0x1f, 0x00, 0x00, // CALL 0x3000
+		0x0f, 0x05, // SYSCALL
+		0x48, 0x89, 0xd8, // MOVQ BX, AX
+		0x0f, 0x05, // SYSCALL
+	}
+	nums := x86Syscalls(code, 0x1000, map[uint64]bool{0x2000: true})
+	assert.Equal(t, []int{1, 0, 60, 39}, nums)
+}
@@ -19,10 +19,16 @@ package gore
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/token"
 	"io"
 	"reflect"
+	"strings"
+	"sync"
 )
 
 const (
@@ -55,9 +61,9 @@ const (
 	ChanBoth = ChanRecv | ChanSend
 )
 
-func getTypes(fileInfo *FileInfo, f fileHandler, md moduledata) (map[uint64]*GoType, error) {
+func getTypes(ctx context.Context, fileInfo *FileInfo, f fileHandler, md moduledata, concurrency int) (map[uint64]*GoType, error) {
 	if GoVersionCompare(fileInfo.goversion.Name, "go1.7beta1") < 0 {
-		return getLegacyTypes(fileInfo, f, md)
+		return getLegacyTypes(ctx, fileInfo, f, md)
 	}
 
 	types, err := md.Types().Data()
@@ -70,18 +76,106 @@ func getTypes(fileInfo *FileInfo, f fileHandler, md moduledata) (map[uint64]*GoT
 		return nil, fmt.Errorf("failed to get type link data: %w", err)
 	}
 
+	if concurrency > 1 && len(typeLink) >= concurrency {
+		return getTypesConcurrently(ctx, fileInfo, types, md.Types().Address, typeLink, concurrency)
+	}
+
 	// New parser
 	parser := newTypeParser(types, md.Types().Address, fileInfo)
+	if err = parseTypeLinks(ctx, parser, typeLink); err != nil {
+		return nil, err
+	}
+	return parser.parsedTypes(), nil
+}
+
+// parseTypeLinks parses the types at the offsets in the typelinks.
+func parseTypeLinks(ctx context.Context, parser *typeParser, typeLink []int32) error {
 	for _, off := range typeLink {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		typ, err := parser.parseType(uint64(off) + parser.base)
 		if err != nil || typ == nil {
-			return nil, fmt.Errorf("failed to parse type at offset 0x%x: %w", off, err)
+			return fmt.Errorf("failed to parse type at offset 0x%x: %w", off, err)
+		}
+	}
+	return nil
+}
+
+// getTypesConcurrently splits the typelinks between the given number of
+// goroutines. Each goroutine uses its own parser, so types referenced from
+// more than one part are parsed more than once. The parsed types are merged
+// in the order of the parts and the references between the types are
+// updated to point to the merged types, so each type is only represented by
+// one GoType as when parsed by a single parser.
+func getTypesConcurrently(ctx context.Context, fileInfo *FileInfo, types []byte, base uint64, typeLink []int32, concurrency int) (map[uint64]*GoType, error) {
+	parsers := make([]*typeParser, concurrency)
+	errs := make([]error, concurrency)
+	size := (len(typeLink) + concurrency - 1) / concurrency
+
+	var wg sync.WaitGroup
+	for i := range parsers {
+		start := min(i*size, len(typeLink))
+		end := min(start+size, len(typeLink))
+		parsers[i] = newTypeParser(types, base, fileInfo)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = parseTypeLinks(ctx, parsers[i], typeLink[start:end])
+		}(i)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	merged := make(map[uint64]*GoType, len(parsers[0].parsedTypes()))
+	for _, p := range parsers {
+		for addr, typ := range p.parsedTypes() {
+			if _, ok := merged[addr]; !ok {
+				merged[addr] = typ
+			}
+		}
+	}
+	for _, p := range parsers {
+		for _, typ := range p.parsedTypes() {
+			relinkType(typ, merged)
+			for _, field := range typ.Fields {
+				relinkType(field, merged)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// relinkType replaces the types referenced by the type with the ones in the
+// map.
+func relinkType(typ *GoType, types map[uint64]*GoType) {
+	relink := func(t *GoType) *GoType {
+		if t == nil {
+			return nil
+		}
+		if m, ok := types[t.Addr]; ok {
+			return m
+		}
+		return t
+	}
+	typ.Element = relink(typ.Element)
+	typ.Key = relink(typ.Key)
+	for i, t := range typ.FuncArgs {
+		typ.FuncArgs[i] = relink(t)
+	}
+	for i, t := range typ.FuncReturnVals {
+		typ.FuncReturnVals[i] = relink(t)
+	}
+	for _, m := range typ.Methods {
+		if m != nil {
+			m.Type = relink(m.Type)
 		}
 	}
-	return parser.parsedTypes(), nil
 }
 
-func getLegacyTypes(fileInfo *FileInfo, f fileHandler, md moduledata) (map[uint64]*GoType, error) {
+func getLegacyTypes(ctx context.Context, fileInfo *FileInfo, f fileHandler, md moduledata) (map[uint64]*GoType, error) {
 	typelinkAddr, typelinkData, err := f.getSectionDataFromAddress(md.TypelinkAddr)
 	if err != nil {
 		return nil, fmt.Errorf("no typelink section found: %w", err)
@@ -94,6 +188,9 @@ func getLegacyTypes(fileInfo *FileInfo, f fileHandler, md moduledata) (map[uint6
 
 	goTypes := make(map[uint64]*GoType)
 	for i := uint64(0); i < md.TypelinkLen; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// Type offsets are always *_type
 		address, err := readUIntTo64(r, fileInfo.ByteOrder, fileInfo.WordSize == intSize32)
 		if err != nil {
@@ -114,42 +211,128 @@ func getLegacyTypes(fileInfo *FileInfo, f fileHandler, md moduledata) (map[uint6
 // GoType is a representation of all types in Go.
 type GoType struct {
 	// Kind indicates the specific kind of type the GoType
-	Kind reflect.Kind
+	Kind reflect.Kind `json:"kind"`
 	// Name is the name of the type.
-	Name string
+	Name string `json:"name"`
 	// Addr is the virtual address to where the type struct is defined.
-	Addr uint64
+	Addr uint64 `json:"addr"`
 	// PtrResolvAddr is the address to where the resolved structure is located
 	// if the GoType is of pointer kind.
-	PtrResolvAddr uint64
+	PtrResolvAddr uint64 `json:"ptrResolvAddr"`
 	// PackagePath is the name of the package import path for the GoType.
-	PackagePath string
+	PackagePath string `json:"packagePath"`
 	// Fields is a slice of the struct fields if the GoType is of kind struct.
-	Fields []*GoType
+	Fields []*GoType `json:"fields"`
 	// FieldName is the name of the field if the GoType is a struct field.
-	FieldName string
+	FieldName string `json:"-"`
 	// FieldTag holds the extracted tag for the field.
-	FieldTag string
+	FieldTag string `json:"-"`
 	// FieldAnon is true if the field does not have a name and is an embedded type.
-	FieldAnon bool
+	FieldAnon bool `json:"-"`
+	// FieldOffset is the byte offset of the field within the struct.
+	FieldOffset uint64 `json:"-"`
+	// FieldExported is true if the field is exported. For embedded fields,
+	// this depends on the name of the embedded type.
+	FieldExported bool `json:"-"`
 	// Element is the element type for arrays, slices channels or the resolved type for
 	// a pointer type. For example int if the slice is a []int.
-	Element *GoType
+	Element *GoType `json:"element"`
 	// Length is the array or slice length.
-	Length int
+	Length int `json:"length"`
 	// ChanDir is the channel direction
-	ChanDir ChanDir
+	ChanDir ChanDir `json:"chanDir"`
 	// Key is the key type for a map.
-	Key *GoType
+	Key *GoType `json:"key"`
 	// FuncArgs holds the argument types for the function if the type is a function kind.
-	FuncArgs []*GoType
+	FuncArgs []*GoType `json:"funcArgs"`
 	// FuncReturnVals holds the return types for the function if the type is a function kind.
-	FuncReturnVals []*GoType
+	FuncReturnVals []*GoType `json:"funcReturnVals"`
 	// IsVariadic is true if the last argument type is variadic. For example "func(s string, n ...int)"
-	IsVariadic bool
+	IsVariadic bool `json:"isVariadic"`
 	// Methods holds information of the types methods.
-	Methods []*TypeMethod
-	flag    uint8
+	Methods []*TypeMethod `json:"methods"`
+	// Implements holds the interfaces the type is converted to in the
+	// binary, sorted by their address. The list is resolved from the itabs so
+	// it only holds the interfaces the compiler needed an itab for, not every
+	// interface the type satisfies. The interface methods are in the Methods
+	// of each interface type.
+	Implements []*GoType `json:"implements"`
+	flag       uint8
+}
+
+// goTypeRef is the JSON representation of a type referenced by another type.
+// The referenced types are not inlined since the type graph can have cycles.
+type goTypeRef struct {
+	Name string `json:"name"`
+	Addr uint64 `json:"addr"`
+}
+
+func newGoTypeRef(t *GoType) *goTypeRef {
+	if t == nil {
+		return nil
+	}
+	return &goTypeRef{Name: t.String(), Addr: t.Addr}
+}
+
+func newGoTypeRefs(types []*GoType) []*goTypeRef {
+	if types == nil {
+		return nil
+	}
+	refs := make([]*goTypeRef, len(types))
+	for i, t := range types {
+		refs[i] = newGoTypeRef(t)
+	}
+	return refs
+}
+
+// goTypeField is the JSON representation of a struct field.
+type goTypeField struct {
+	Name     string     `json:"name"`
+	Type     *goTypeRef `json:"type"`
+	Tag      string     `json:"tag"`
+	Offset   uint64     `json:"offset"`
+	Anon     bool       `json:"anonymous"`
+	Exported bool       `json:"exported"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The kind is encoded by
+// its name and the referenced types, for example the element type or the
+// type of a struct field, are encoded by their name and address.
+func (t *GoType) MarshalJSON() ([]byte, error) {
+	var fields []goTypeField
+	if t.Fields != nil {
+		fields = make([]goTypeField, len(t.Fields))
+		for i, f := range t.Fields {
+			fields[i] = goTypeField{
+				Name:     f.FieldName,
+				Type:     newGoTypeRef(f),
+				Tag:      f.FieldTag,
+				Offset:   f.FieldOffset,
+				Anon:     f.FieldAnon,
+				Exported: f.FieldExported,
+			}
+		}
+	}
+	type goType GoType
+	return json.Marshal(&struct {
+		*goType
+		Kind           string        `json:"kind"`
+		Fields         []goTypeField `json:"fields"`
+		Element        *goTypeRef    `json:"element"`
+		Key            *goTypeRef    `json:"key"`
+		FuncArgs       []*goTypeRef  `json:"funcArgs"`
+		FuncReturnVals []*goTypeRef  `json:"funcReturnVals"`
+		Implements     []*goTypeRef  `json:"implements"`
+	}{
+		goType:         (*goType)(t),
+		Kind:           t.Kind.String(),
+		Fields:         fields,
+		Element:        newGoTypeRef(t.Element),
+		Key:            newGoTypeRef(t.Key),
+		FuncArgs:       newGoTypeRefs(t.FuncArgs),
+		FuncReturnVals: newGoTypeRefs(t.FuncReturnVals),
+		Implements:     newGoTypeRefs(t.Implements),
+	})
 }
 
 // String implements the fmt.Stringer interface.
@@ -288,23 +471,33 @@ func MethodDef(typ *GoType) string {
 // TypeMethod is description of a method owned by the GoType.
 type TypeMethod struct {
 	// Name is the string name for the method.
-	Name string
+	Name string `json:"name"`
 	// Type is the specific function type for the method.
 	// This can be nil. If it is nil, the method is not part of an
 	// implementation of a interface or it is not exported.
-	Type *GoType
+	Type *GoType `json:"type"`
 	// IfaceCallOffset is the offset from the beginning of the .text section
 	// where the function code starts. According to code comments in the
 	// standard library, it is used for interface calls.
 	// Can be 0 if the code is not called in the binary and was optimized out
 	// by the compiler or linker.
-	IfaceCallOffset uint64
+	IfaceCallOffset uint64 `json:"ifaceCallOffset"`
 	// FuncCallOffset is the offset from the beginning of the .text section
 	// where the function code starts. According to code comments in the
 	// standard library, it is used for normal method calls.
 	// Can be 0 if the code is not called in the binary and was optimized out
 	// by the compiler or linker.
-	FuncCallOffset uint64
+	FuncCallOffset uint64 `json:"funcCallOffset"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The method's type is
+// encoded by its name and address.
+func (m *TypeMethod) MarshalJSON() ([]byte, error) {
+	type typeMethod TypeMethod
+	return json.Marshal(&struct {
+		*typeMethod
+		Type *goTypeRef `json:"type"`
+	}{(*typeMethod)(m), newGoTypeRef(m.Type)})
 }
 
 /*
@@ -481,6 +674,12 @@ func typeParse(types map[uint64]*GoType, fileInfo *FileInfo, offset uint64, sect
 			// Older versions has no field name for anonymous fields. New versions
 			// uses a bit flag on the offset.
 			field.FieldAnon = fieldName == "" || uptr&1 != 0
+			field.FieldOffset = uptr
+			exportName := fieldName
+			if exportName == "" {
+				exportName = strings.TrimLeft(gt.Name[strings.LastIndexByte(gt.Name, '.')+1:], "*")
+			}
+			field.FieldExported = token.IsExported(exportName)
 			typ.Fields[i] = &field
 		}
 	case reflect.Array:
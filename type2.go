@@ -164,6 +164,30 @@ func (p *typeParser) resolveName(ptr uint64, flags uint8) (string, int) {
 	return name, nl
 }
 
+// rawName returns the encoded name at the offset. The region includes the
+// flags byte, the name, the tag and the package path offset if present.
+func (p *typeParser) rawName(off uint64) ([]byte, error) {
+	if off+3 > uint64(len(p.typesData)) {
+		return nil, fmt.Errorf("name offset 0x%x is out of bounds", off)
+	}
+	flags := p.typesData[off]
+	nl, nll := p.parseNameLen(p, off+1)
+	end := off + 1 + uint64(nll) + nl
+	if flags&(1<<1) != 0 && end+2 <= uint64(len(p.typesData)) {
+		// Has a tag.
+		tl, tll := p.parseNameLen(p, end)
+		end += uint64(tll) + tl
+	}
+	if flags&(1<<2) != 0 {
+		// Has a package path offset.
+		end += 4
+	}
+	if nll <= 0 || end > uint64(len(p.typesData)) {
+		return nil, fmt.Errorf("name at offset 0x%x is out of bounds", off)
+	}
+	return bytes.Clone(p.typesData[off:end]), nil
+}
+
 func (p *typeParser) resolveTag(o uint64) string {
 	if !p.hasTag(o) {
 		return ""
@@ -597,6 +621,16 @@ func (p *typeParser) parseType(address uint64) (*GoType, error) {
 					field.FieldAnon = name == "" || sf.OffsetEmbed&1 != 0
 				}
 
+				// Between Go 1.9 and 1.18, the offset is shifted to make room for
+				// the embedded bit.
+				field.FieldOffset = sf.OffsetEmbed
+				if GoVersionCompare(p.goversion, "go1.9beta1") >= 0 && GoVersionCompare(p.goversion, "go1.19rc1") < 0 {
+					field.FieldOffset >>= 1
+				}
+
+				// The first bit of the name's flags is set for exported names.
+				field.FieldExported = p.typesData[sf.Name-p.base]&1 != 0
+
 				typ.Fields[i] = &field
 			}
 		}
@@ -18,6 +18,7 @@
 package gore
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"reflect"
@@ -76,6 +77,12 @@ func TestGetTypes(t *testing.T) {
 					a.Equal(reflect.Int, typ.Fields[1].Kind, "Second field is the wrong kind.")
 					a.Equal("age", typ.Fields[1].FieldName, "Second field has the wrong name.")
 
+					// The int field is located after the string header.
+					a.Equal(uint64(0), typ.Fields[0].FieldOffset, "First field has the wrong offset.")
+					a.Equal(uint64(2*f.FileInfo.WordSize), typ.Fields[1].FieldOffset, "Second field has the wrong offset.")
+					a.False(typ.Fields[0].FieldExported, "First field should not be exported.")
+					a.False(typ.Fields[1].FieldExported, "Second field should not be exported.")
+
 					simpleStructTested = true
 				}
 
@@ -88,6 +95,8 @@ func TestGetTypes(t *testing.T) {
 					a.Equal(reflect.String, typ.Fields[0].Kind, "First field is the wrong kind.")
 					a.Equal("MyString", typ.Fields[0].FieldName, "First field has the wrong name.")
 					a.Equal(`json:"String"`, typ.Fields[0].FieldTag, "Field tag incorrectly parsed")
+					a.True(typ.Fields[0].FieldExported, "First field should be exported.")
+					a.Equal(uint64(2*f.FileInfo.WordSize), typ.Fields[1].FieldOffset, "Second field has the wrong offset.")
 
 					a.Equal(reflect.Ptr, typ.Fields[1].Kind, "Second field is the wrong kind.")
 					a.Equal("person", typ.Fields[1].FieldName, "Second field has the wrong name.")
@@ -344,3 +353,137 @@ const ifDef = `type geometry interface {
 const methodAll = `func (myStruct) Read([]int8) (int, error)
 func (myStruct) Close() error
 func (myStruct) private()`
+
+func TestTypeParserRawName(t *testing.T) {
+	r := require.New(t)
+
+	data := []byte{
+		0xff,                      // Unrelated data.
+		0x00, 0x03, 'i', 'n', 't', // Name without tag.
+		0x02, 0x01, 'A', 0x04, 'j', 's', 'o', 'n', // Name with a tag.
+		0x04, 0x01, 'b', 0x10, 0x00, 0x00, 0x00, // Name with a package path offset.
+		0xff,
+	}
+	p := newTypeParser(data, 0x1000, &FileInfo{WordSize: intSize64, goversion: goversions["go1.20"]})
+
+	raw, err := p.rawName(1)
+	r.NoError(err)
+	r.Equal(data[1:6], raw)
+
+	raw, err = p.rawName(6)
+	r.NoError(err)
+	r.Equal(data[6:14], raw)
+
+	raw, err = p.rawName(14)
+	r.NoError(err)
+	r.Equal(data[14:21], raw)
+
+	_, err = p.rawName(uint64(len(data)))
+	r.Error(err)
+}
+
+func TestResolveImplements(t *testing.T) {
+	stringer := &GoType{Kind: reflect.Interface, Name: "fmt.Stringer", Addr: 0x2000}
+	writer := &GoType{Kind: reflect.Interface, Name: "io.Writer", Addr: 0x1000}
+	typ := &GoType{Kind: reflect.Ptr, Name: "*main.T", Addr: 0x3000}
+	other := &GoType{Kind: reflect.Struct, Name: "main.U", Addr: 0x4000}
+	// The error interface is not in the types.
+	errIface := &GoType{Kind: reflect.Interface, Name: "error", Addr: 0x5000}
+
+	f := &GoFile{itabIndex: map[itabKey]*Itab{
+		{stringer.Addr, typ.Addr}: {Interface: stringer, Type: typ},
+		{writer.Addr, typ.Addr}:   {Interface: writer, Type: typ},
+		{errIface.Addr, typ.Addr}: {Interface: errIface, Type: typ},
+		{writer.Addr, 0x6000}:     {Interface: writer, Type: &GoType{Addr: 0x6000}},
+	}}
+	f.resolveImplements(map[uint64]*GoType{
+		stringer.Addr: stringer,
+		writer.Addr:   writer,
+		typ.Addr:      typ,
+		other.Addr:    other,
+	})
+
+	assert.Equal(t, []*GoType{writer, stringer, errIface}, typ.Implements)
+	assert.Empty(t, other.Implements)
+	assert.Empty(t, writer.Implements)
+}
+
+func TestGoTypeMarshalJSON(t *testing.T) {
+	r := require.New(t)
+
+	// type node struct { Next *node }
+	node := &GoType{Kind: reflect.Struct, Name: "main.node", Addr: 0x1000, PackagePath: "main"}
+	ptr := &GoType{Kind: reflect.Ptr, Addr: 0x2000, Element: node}
+	next := *ptr
+	next.FieldName = "Next"
+	next.FieldOffset = 0
+	next.FieldExported = true
+	node.Fields = []*GoType{&next}
+	node.Methods = []*TypeMethod{{Name: "Len", Type: &GoType{
+		Kind:           reflect.Func,
+		Addr:           0x3000,
+		FuncReturnVals: []*GoType{{Kind: reflect.Int, Name: "int"}},
+	}}}
+
+	buf, err := json.Marshal(node)
+	r.NoError(err)
+
+	var decoded map[string]any
+	r.NoError(json.Unmarshal(buf, &decoded))
+	r.Equal("struct", decoded["kind"])
+	r.Equal("main.node", decoded["name"])
+	r.Equal([]any{map[string]any{
+		"name":      "Next",
+		"type":      map[string]any{"name": "*main.node", "addr": float64(0x2000)},
+		"tag":       "",
+		"offset":    float64(0),
+		"anonymous": false,
+		"exported":  true,
+	}}, decoded["fields"])
+	r.Equal([]any{map[string]any{
+		"name":            "Len",
+		"type":            map[string]any{"name": "func() int", "addr": float64(0x3000)},
+		"ifaceCallOffset": float64(0),
+		"funcCallOffset":  float64(0),
+	}}, decoded["methods"])
+	r.Nil(decoded["element"])
+
+	buf, err = json.Marshal(ptr)
+	r.NoError(err)
+	r.NoError(json.Unmarshal(buf, &decoded))
+	r.Equal(map[string]any{"name": "main.node", "addr": float64(0x1000)}, decoded["element"])
+}
+
+func TestRelinkType(t *testing.T) {
+	elem := &GoType{Name: "int", Addr: 0x10}
+	dup := &GoType{Name: "int", Addr: 0x10}
+	other := &GoType{Name: "string", Addr: 0x30}
+	fn := &GoType{Name: "func(int) string", Kind: reflect.Func, Addr: 0x20,
+		FuncArgs: []*GoType{dup}, FuncReturnVals: []*GoType{other},
+		Methods: []*TypeMethod{{Name: "M", Type: dup}}}
+
+	relinkType(fn, map[uint64]*GoType{0x10: elem})
+	assert.Same(t, elem, fn.FuncArgs[0])
+	assert.Same(t, other, fn.FuncReturnVals[0], "types not in the map are kept")
+	assert.Same(t, elem, fn.Methods[0].Type)
+}
+
+func TestItabFunOffset(t *testing.T) {
+	tests := []struct {
+		version  string
+		wordSize int
+		expected uint64
+	}{
+		{"go1.9", intSize64, 32},
+		{"go1.9", intSize32, 20},
+		{"go1.10", intSize64, 24},
+		{"go1.22.8", intSize64, 24},
+		{"go1.22.8", intSize32, 16},
+		{"go1.23rc1", intSize32, 12},
+		{"go1.23.4", intSize64, 24},
+		{"go1.23.4", intSize32, 12},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, itabFunOffset(test.version, test.wordSize), "%s %d", test.version, test.wordSize)
+	}
+}
@@ -0,0 +1,127 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// TypeIterator parses the types in the binary one at a time. Unlike GetTypes,
+// the parsed types are not kept by the iterator, so a caller that processes
+// and discards the types keeps the memory usage low. Since no types are kept,
+// types that are referenced by multiple other types are parsed again for each
+// of them. This means the child types of two returned types may be different
+// objects representing the same type. Each type is only returned once.
+// For binaries compiled with Go versions before 1.7, all types are parsed
+// when the iterator is created.
+type TypeIterator struct {
+	parser *typeParser
+	links  []int32
+	// pending holds the parsed types that have not been returned yet.
+	pending []*GoType
+	// returned holds the addresses of the types that have been returned.
+	returned map[uint64]struct{}
+	err      error
+}
+
+// NewTypeIterator returns an iterator over the types in the binary.
+func (f *GoFile) NewTypeIterator() (*TypeIterator, error) {
+	err := f.initModuleData()
+	if err != nil {
+		return nil, err
+	}
+	md := f.moduledata
+
+	it := &TypeIterator{returned: make(map[uint64]struct{})}
+	if GoVersionCompare(f.FileInfo.goversion.Name, "go1.7beta1") < 0 {
+		types, err := getLegacyTypes(context.Background(), f.FileInfo, f.fh, md)
+		if err != nil {
+			return nil, err
+		}
+		it.pending = sortedByAddress(types)
+		return it, nil
+	}
+
+	types, err := md.Types().Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get types data section: %w", err)
+	}
+	it.links, err = md.TypeLinkData()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get type link data: %w", err)
+	}
+	it.parser = newTypeParser(types, md.Types().Address, f.FileInfo)
+	return it, nil
+}
+
+// Next returns the next type. When there are no more types or parsing a type
+// failed, false is returned. The error can be retrieved by calling Err.
+func (it *TypeIterator) Next() (*GoType, bool) {
+	for len(it.pending) == 0 {
+		if it.err != nil || len(it.links) == 0 {
+			return nil, false
+		}
+		it.parseNext()
+	}
+	typ := it.pending[0]
+	it.pending = it.pending[1:]
+	return typ, true
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TypeIterator) Err() error {
+	return it.err
+}
+
+// parseNext parses the type for the next type link together with all the
+// types it references and queues the types that have not been returned.
+func (it *TypeIterator) parseNext() {
+	off := it.links[0]
+	it.links = it.links[1:]
+
+	// Start with an empty cache so the types parsed for the previous link
+	// can be garbage collected.
+	it.parser.cache = make(map[uint64]*GoType)
+	typ, err := it.parser.parseType(uint64(off) + it.parser.base)
+	if err != nil || typ == nil {
+		it.err = fmt.Errorf("failed to parse type at offset 0x%x: %w", off, err)
+		return
+	}
+
+	for _, t := range sortedByAddress(it.parser.parsedTypes()) {
+		if _, ok := it.returned[t.Addr]; ok {
+			continue
+		}
+		it.returned[t.Addr] = struct{}{}
+		it.pending = append(it.pending, t)
+	}
+	it.parser.cache = nil
+}
+
+func sortedByAddress(types map[uint64]*GoType) []*GoType {
+	list := make([]*GoType, 0, len(types))
+	for _, t := range types {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Addr < list[j].Addr
+	})
+	return list
+}
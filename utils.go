@@ -1,6 +1,11 @@
 package gore
 
-import "io"
+import (
+	"errors"
+	"io"
+	"math"
+	"os"
+)
 
 func tryClose(r io.ReaderAt) error {
 	if c, ok := r.(io.Closer); ok {
@@ -8,3 +13,45 @@ func tryClose(r io.ReaderAt) error {
 	}
 	return nil
 }
+
+// readerSize returns the number of bytes that can be read from the reader.
+// The size is taken from the reader if it reports it, for example for files
+// and section readers. Otherwise, the end is found by probing reads.
+func readerSize(r io.ReaderAt) (int64, error) {
+	switch v := r.(type) {
+	case interface{ Size() int64 }:
+		return v.Size(), nil
+	case interface{ Stat() (os.FileInfo, error) }:
+		fi, err := v.Stat()
+		if err != nil {
+			return 0, err
+		}
+		return fi.Size(), nil
+	}
+
+	b := make([]byte, 1)
+	exists := func(off int64) bool {
+		n, _ := r.ReadAt(b, off)
+		return n == 1
+	}
+	if !exists(0) {
+		return 0, nil
+	}
+	// The byte at lo exists, the one at hi doesn't.
+	lo, hi := int64(0), int64(1)
+	for exists(hi) {
+		if hi > math.MaxInt64/2 {
+			return 0, errors.New("the reader has no end")
+		}
+		lo, hi = hi, hi*2
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if exists(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo + 1, nil
+}
@@ -0,0 +1,33 @@
+package gore
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainReaderAt hides the Size method of the wrapped reader.
+type plainReaderAt struct {
+	r io.ReaderAt
+}
+
+func (p plainReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	return p.r.ReadAt(b, off)
+}
+
+func TestReaderSize(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 1000, 4096, 4097} {
+		data := make([]byte, n)
+
+		size, err := readerSize(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, int64(n), size)
+
+		size, err = readerSize(plainReaderAt{bytes.NewReader(data)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(n), size, "probed")
+	}
+}
@@ -0,0 +1,653 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bufio"
+	"bytes"
+	"debug/dwarf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime/debug"
+	"sync"
+)
+
+var wasmMagic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// WebAssembly section IDs.
+const (
+	wasmSectionCustom = 0
+	wasmSectionImport = 2
+	wasmSectionMemory = 5
+	wasmSectionCode   = 10
+	wasmSectionData   = 11
+)
+
+// wasmSectionNames are the names of the known sections. Custom sections
+// are named by their content.
+var wasmSectionNames = map[byte]string{
+	1:  "type",
+	2:  "import",
+	3:  "function",
+	4:  "table",
+	5:  "memory",
+	6:  "global",
+	7:  "export",
+	8:  "start",
+	9:  "element",
+	10: "code",
+	11: "data",
+	12: "datacount",
+}
+
+// wasmMemorySection is the name of the section holding the initialized linear
+// memory. The data segments are copied to their offsets in the memory so the
+// Go data, for example the pclntab and the moduledata, can be accessed by its
+// address. The memory section itself only holds the limits of the memory so
+// its name is reused.
+const wasmMemorySection = "memory"
+
+func openWasm(r io.ReaderAt, opts Options) (*wasmFile, error) {
+	size, err := readerSize(r)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the size of the WebAssembly file: %w", err)
+	}
+	sections, err := parseWasmSections(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing the WebAssembly file: %w", err)
+	}
+	ret := &wasmFile{sections: sections, reader: r, size: size, maxSectionBytes: opts.MaxSectionBytes, ownsReader: !opts.KeepReaderOpen}
+	ret.getmemory = sync.OnceValues(ret.initMemory)
+	return ret, nil
+}
+
+var _ fileHandler = (*wasmFile)(nil)
+
+// wasmFile is a WebAssembly module compiled with GOARCH=wasm. The code isn't
+// mapped into the linear memory, the Go linker instead uses the function
+// indexes to derive the PC values. The code section is reported at address
+// zero which is where the linker places runtime.text.
+type wasmFile struct {
+	sections        []wasmSection
+	reader          io.ReaderAt
+	size            int64
+	getmemory       func() ([]byte, error)
+	maxSectionBytes uint64
+	// ownsReader is true if the reader should be closed together with the
+	// file.
+	ownsReader bool
+}
+
+type wasmSection struct {
+	name   string
+	offset int64
+	size   uint64
+}
+
+// parseWasmSections parses the section headers. Sections that extend past
+// the end of the file are rejected so their sizes can be trusted when they
+// are read.
+func parseWasmSections(r io.ReaderAt, fileSize int64) ([]wasmSection, error) {
+	hdr := make([]byte, 8)
+	if _, err := r.ReadAt(hdr, 0); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hdr[:4], wasmMagic) {
+		return nil, errors.New("invalid magic")
+	}
+	if v := binary.LittleEndian.Uint32(hdr[4:]); v != 1 {
+		return nil, fmt.Errorf("unsupported version %d", v)
+	}
+
+	var sections []wasmSection
+	off := int64(len(hdr))
+	for {
+		br := bufio.NewReader(io.NewSectionReader(r, off, math.MaxInt64-off))
+		id, err := br.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return sections, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		size, n, err := readULEB128(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the size of section %d: %w", id, err)
+		}
+		if size > uint64(fileSize) || off+1+int64(n) > fileSize-int64(size) {
+			return nil, fmt.Errorf("section %d at 0x%x is out of bounds", id, off)
+		}
+		s := wasmSection{name: wasmSectionNames[id], offset: off + 1 + int64(n), size: size}
+		if id == wasmSectionCustom {
+			// The content starts with the name of the section.
+			nameLen, n, err := readULEB128(br)
+			if err != nil || uint64(n) > size || nameLen > size-uint64(n) {
+				return nil, fmt.Errorf("malformed custom section at 0x%x", off)
+			}
+			name := make([]byte, nameLen)
+			if _, err = io.ReadFull(br, name); err != nil {
+				return nil, err
+			}
+			s.name = string(name)
+			s.offset += int64(n) + int64(nameLen)
+			s.size -= uint64(n) + nameLen
+		}
+		sections = append(sections, s)
+		off = s.offset + int64(s.size)
+	}
+}
+
+// readULEB128 reads an unsigned LEB128 encoded value. It also returns the
+// number of bytes read.
+func readULEB128(r io.ByteReader) (uint64, int, error) {
+	var v uint64
+	for n, shift := 1, uint(0); shift < 64; n, shift = n+1, shift+7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, n, err
+		}
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return v, n, nil
+		}
+	}
+	return 0, 0, errors.New("LEB128 value overflows")
+}
+
+func (w *wasmFile) section(name string) (wasmSection, bool) {
+	for _, s := range w.sections {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return wasmSection{}, false
+}
+
+// sectionData reads the section's data if it's within the size limit.
+func (w *wasmFile) sectionData(s wasmSection) ([]byte, error) {
+	if err := checkSectionSize(s.name, s.size, w.maxSectionBytes); err != nil {
+		return nil, err
+	}
+	data := make([]byte, s.size)
+	if _, err := w.reader.ReadAt(data, s.offset); err != nil {
+		return nil, fmt.Errorf("failed to read section %s: %w", s.name, err)
+	}
+	return data, nil
+}
+
+// initMemory copies the active data segments into the linear memory. The
+// memory is kept from address zero to the end of the last segment. The linker
+// omits zeros at the start of the data, so the first segment may start after
+// the first Go symbol.
+func (w *wasmFile) initMemory() ([]byte, error) {
+	s, ok := w.section("data")
+	if !ok {
+		return nil, ErrSectionDoesNotExist
+	}
+	data, err := w.sectionData(s)
+	if err != nil {
+		return nil, err
+	}
+
+	type segment struct {
+		offset uint64
+		data   []byte
+	}
+	r := bytes.NewReader(data)
+	count, _, err := readULEB128(r)
+	if err != nil {
+		return nil, err
+	}
+	var segments []segment
+	var end uint64
+	for i := uint64(0); i < count; i++ {
+		mode, _, err := readULEB128(r)
+		if err != nil {
+			return nil, err
+		}
+		if mode != 0 {
+			// Passive segments and other memories are not used by Go.
+			return nil, fmt.Errorf("unsupported data segment mode %d", mode)
+		}
+		// The offset is a constant expression: i32.const offset end
+		op, _ := r.ReadByte()
+		offset, _, err := readULEB128(r)
+		if err != nil || op != 0x41 {
+			return nil, fmt.Errorf("unsupported offset expression for data segment %d", i)
+		}
+		if b, _ := r.ReadByte(); b != 0x0b {
+			return nil, fmt.Errorf("unsupported offset expression for data segment %d", i)
+		}
+		size, _, err := readULEB128(r)
+		if err != nil || size > uint64(r.Len()) {
+			return nil, fmt.Errorf("data segment %d is truncated", i)
+		}
+		pos := len(data) - r.Len()
+		segments = append(segments, segment{offset: offset, data: data[pos : pos+int(size)]})
+		_, _ = r.Seek(int64(size), io.SeekCurrent)
+		end = max(end, offset+size)
+	}
+	if len(segments) == 0 {
+		return nil, ErrSectionDoesNotExist
+	}
+	if err = checkSectionSize(wasmMemorySection, end, w.maxSectionBytes); err != nil {
+		return nil, err
+	}
+	// The segments must fit in the initial memory. The memory is also
+	// bounded by the file size since it is mostly made of the segments.
+	limit, err := w.initialMemorySize()
+	if err != nil {
+		return nil, err
+	}
+	if end > limit || end > uint64(w.size) {
+		return nil, fmt.Errorf("the data segments end at 0x%x, outside the memory", end)
+	}
+	mem := make([]byte, end)
+	for _, seg := range segments {
+		copy(mem[seg.offset:], seg.data)
+	}
+	return mem, nil
+}
+
+// wasmPageSize is the size of a WebAssembly memory page.
+const wasmPageSize = 64 << 10
+
+// initialMemorySize returns the initial size of the linear memory declared
+// in the memory section. If the module doesn't declare a memory, the size is
+// not limited.
+func (w *wasmFile) initialMemorySize() (uint64, error) {
+	s, ok := w.section(wasmMemorySection)
+	if !ok {
+		return math.MaxUint64, nil
+	}
+	data, err := w.sectionData(s)
+	if err != nil {
+		return 0, err
+	}
+	r := bytes.NewReader(data)
+	count, _, err := readULEB128(r)
+	if err != nil || count == 0 {
+		return 0, errors.New("malformed memory section")
+	}
+	if _, err = r.ReadByte(); err != nil {
+		return 0, errors.New("malformed memory section")
+	}
+	pages, _, err := readULEB128(r)
+	if err != nil {
+		return 0, errors.New("malformed memory section")
+	}
+	return min(pages, math.MaxUint64/wasmPageSize) * wasmPageSize, nil
+}
+
+func (w *wasmFile) getSymbol(string) (Symbol, error) {
+	return Symbol{}, ErrSymbolNotFound
+}
+
+func (w *wasmFile) getSymbolNames() ([]string, error) {
+	return nil, nil
+}
+
+func (w *wasmFile) getParsedFile() any {
+	return nil
+}
+
+func (w *wasmFile) getReader() io.ReaderAt {
+	return w.reader
+}
+
+func (w *wasmFile) Close() error {
+	if !w.ownsReader {
+		return nil
+	}
+	return tryClose(w.reader)
+}
+
+func (w *wasmFile) getRData() ([]byte, error) {
+	return w.getmemory()
+}
+
+func (w *wasmFile) getCodeSection() (uint64, []byte, error) {
+	s, ok := w.section("code")
+	if !ok {
+		return 0, nil, ErrSectionDoesNotExist
+	}
+	data, err := w.sectionData(s)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error when getting the code section: %w", err)
+	}
+	return 0, data, nil
+}
+
+func (w *wasmFile) getCodeSectionReader() (uint64, io.ReaderAt, int64, error) {
+	s, ok := w.section("code")
+	if !ok {
+		return 0, nil, 0, ErrSectionDoesNotExist
+	}
+	return 0, io.NewSectionReader(w.reader, s.offset, int64(s.size)), int64(s.size), nil
+}
+
+func (w *wasmFile) getPCLNTABData() (uint64, []byte, error) {
+	mem, err := w.getmemory()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get the linear memory: %w", err)
+	}
+	tab, _, err := searchSectionForTab(mem, binary.LittleEndian)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error when search for pclntab: %w", err)
+	}
+	return uint64(len(mem) - len(tab)), tab, nil
+}
+
+func (w *wasmFile) moduledataSections() []string {
+	return []string{wasmMemorySection}
+}
+
+func (w *wasmFile) getSectionDataFromAddress(address uint64) (uint64, []byte, error) {
+	mem, err := w.getmemory()
+	if err != nil {
+		return 0, nil, err
+	}
+	if address < uint64(len(mem)) {
+		return 0, mem, nil
+	}
+	return 0, nil, ErrSectionDoesNotExist
+}
+
+func (w *wasmFile) getSectionData(name string) (uint64, []byte, error) {
+	if name == wasmMemorySection {
+		mem, err := w.getmemory()
+		return 0, mem, err
+	}
+	s, ok := w.section(name)
+	if !ok {
+		return 0, nil, ErrSectionDoesNotExist
+	}
+	data, err := w.sectionData(s)
+	return 0, data, err
+}
+
+func (w *wasmFile) getFileInfo() *FileInfo {
+	return &FileInfo{
+		ByteOrder: binary.LittleEndian,
+		OS:        w.os(),
+		WordSize:  intSize64,
+		Arch:      ArchWasm,
+	}
+}
+
+// os returns the GOOS the module was built for. It's derived from the
+// module the runtime imports its host functions from. Older versions of Go
+// used "go" instead of "gojs".
+func (w *wasmFile) os() string {
+	modules, err := w.getImportedLibraries()
+	if err != nil {
+		return ""
+	}
+	for _, m := range modules {
+		switch m {
+		case "wasi_snapshot_preview1":
+			return "wasip1"
+		case "gojs", "go":
+			return "js"
+		}
+	}
+	return ""
+}
+
+func (w *wasmFile) getSections() ([]Section, error) {
+	sections := make([]Section, 0, len(w.sections))
+	for _, s := range w.sections {
+		sections = append(sections, Section{
+			Name:       s.name,
+			Size:       s.size,
+			FileOffset: uint64(s.offset),
+		})
+	}
+	return sections, nil
+}
+
+func (w *wasmFile) getEntryPoint() (uint64, error) {
+	return 0, ErrUnsupportedFile
+}
+
+// wasmFuncValueOffset is the offset the Go linker adds to the function
+// index in the PC values. The PC of a function is its index, without the
+// imported functions, plus the offset shifted left by 16 bits. The lower
+// 16 bits hold the block number within the function.
+const wasmFuncValueOffset = 0x1000
+
+// getExports returns the functions in the export section. The address of an
+// exported function is the PC the Go linker assigned to it. Exported
+// memories, tables and globals don't have a PC and are not included.
+func (w *wasmFile) getExports() ([]Export, error) {
+	s, ok := w.section("export")
+	if !ok {
+		return nil, nil
+	}
+	_, importedFuncs, err := w.imports()
+	if err != nil {
+		return nil, err
+	}
+	data, err := w.sectionData(s)
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(data)
+	count, _, err := readULEB128(r)
+	if err != nil {
+		return nil, fmt.Errorf("malformed export section: %w", err)
+	}
+	var exports []Export
+	for i := uint64(0); i < count; i++ {
+		name, err := readWasmName(r)
+		if err != nil {
+			return nil, fmt.Errorf("malformed export %d: %w", i, err)
+		}
+		kind, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("malformed export %d: %w", i, err)
+		}
+		idx, _, err := readULEB128(r)
+		if err != nil {
+			return nil, fmt.Errorf("malformed export %d: %w", i, err)
+		}
+		if kind != 0 || idx < importedFuncs {
+			// Not a function or a re-exported import.
+			continue
+		}
+		exports = append(exports, Export{
+			Name:    name,
+			Address: (idx - importedFuncs + wasmFuncValueOffset) << 16,
+		})
+	}
+	return exports, nil
+}
+
+// getImportedLibraries returns the names of the modules in the import
+// section.
+func (w *wasmFile) getImportedLibraries() ([]string, error) {
+	imports, err := w.getDynamicImports()
+	if err != nil {
+		return nil, err
+	}
+	var modules []string
+	seen := make(map[string]bool)
+	for _, imp := range imports {
+		if !seen[imp.Library] {
+			seen[imp.Library] = true
+			modules = append(modules, imp.Library)
+		}
+	}
+	return modules, nil
+}
+
+// getDynamicImports returns the entries in the import section. The library
+// is the name of the module the entry is imported from.
+func (w *wasmFile) getDynamicImports() ([]DynamicImport, error) {
+	imports, _, err := w.imports()
+	return imports, err
+}
+
+// readWasmName reads a name encoded as its length followed by the UTF-8
+// bytes.
+func readWasmName(r *bytes.Reader) (string, error) {
+	n, _, err := readULEB128(r)
+	if err != nil {
+		return "", err
+	}
+	if n > uint64(r.Len()) {
+		return "", io.ErrUnexpectedEOF
+	}
+	name := make([]byte, n)
+	_, err = io.ReadFull(r, name)
+	return string(name), err
+}
+
+// imports parses the import section. It also returns the number of imported
+// functions, which come first in the function index space.
+func (w *wasmFile) imports() ([]DynamicImport, uint64, error) {
+	s, ok := w.section("import")
+	if !ok {
+		return nil, 0, nil
+	}
+	data, err := w.sectionData(s)
+	if err != nil {
+		return nil, 0, err
+	}
+	r := bytes.NewReader(data)
+	readLimits := func() error {
+		flags, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if _, _, err = readULEB128(r); err != nil {
+			return err
+		}
+		if flags&1 != 0 {
+			_, _, err = readULEB128(r)
+		}
+		return err
+	}
+
+	count, _, err := readULEB128(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("malformed import section: %w", err)
+	}
+	var imports []DynamicImport
+	var funcs uint64
+	for i := uint64(0); i < count; i++ {
+		module, err := readWasmName(r)
+		if err != nil {
+			return nil, 0, fmt.Errorf("malformed import %d: %w", i, err)
+		}
+		name, err := readWasmName(r)
+		if err != nil {
+			return nil, 0, fmt.Errorf("malformed import %d: %w", i, err)
+		}
+		kind, err := r.ReadByte()
+		if err != nil {
+			return nil, 0, fmt.Errorf("malformed import %d: %w", i, err)
+		}
+		switch kind {
+		case 0: // Function: type index.
+			_, _, err = readULEB128(r)
+			funcs++
+		case 1: // Table: reference type and limits.
+			if _, err = r.ReadByte(); err == nil {
+				err = readLimits()
+			}
+		case 2: // Memory: limits.
+			err = readLimits()
+		case 3: // Global: value type and mutability.
+			_, err = r.Seek(2, io.SeekCurrent)
+		default:
+			err = fmt.Errorf("unknown import kind %d", kind)
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("malformed import %d: %w", i, err)
+		}
+		imports = append(imports, DynamicImport{Name: name, Library: module})
+	}
+	return imports, funcs, nil
+}
+
+func (w *wasmFile) getBuildID() (string, error) {
+	s, ok := w.section("go:buildid")
+	if !ok {
+		return "", nil
+	}
+	data, err := w.sectionData(s)
+	if err != nil {
+		return "", fmt.Errorf("error when getting the build id section: %w", err)
+	}
+	return parseBuildIDFromRaw(data)
+}
+
+func (w *wasmFile) getDwarf() (*dwarf.Data, error) {
+	var dat [7][]byte
+	for i, name := range []string{".debug_abbrev", ".debug_info", ".debug_str", ".debug_line", ".debug_ranges", ".debug_frame", ".debug_loc"} {
+		s, ok := w.section(name)
+		if !ok {
+			if i < 2 {
+				return nil, ErrSectionDoesNotExist
+			}
+			continue
+		}
+		data, err := w.sectionData(s)
+		if err != nil {
+			return nil, err
+		}
+		dat[i] = data
+	}
+	return dwarf.New(dat[0], nil, dat[5], dat[1], dat[3], nil, dat[4], dat[2])
+}
+
+// The module information is stored between these sentinels by the go
+// command.
+var (
+	modInfoStart = []byte("0w\xaf\x0c\x92t\x08\x02A\xe1\xc1\x07\xe6\xd6\x18\xe6")
+	modInfoEnd   = []byte("\xf92C1\x86\x18 r\x00\x82B\x10A\x16\xd8\xf2")
+)
+
+// readBuildInfo reads the module information from the linear memory. The
+// debug/buildinfo package doesn't support WebAssembly modules and the linker
+// doesn't write the build information blob, so the module information is
+// located by its sentinels. The Go version isn't part of it.
+func (w *wasmFile) readBuildInfo() (*debug.BuildInfo, error) {
+	mem, err := w.getmemory()
+	if err != nil {
+		return nil, err
+	}
+	start := bytes.Index(mem, modInfoStart)
+	if start == -1 {
+		return nil, ErrNoBuildInfo
+	}
+	data := mem[start+len(modInfoStart):]
+	end := bytes.Index(data, modInfoEnd)
+	if end == -1 {
+		return nil, ErrNoBuildInfo
+	}
+	info, err := debug.ParseBuildInfo(string(data[:end]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the module information: %w", err)
+	}
+	return info, nil
+}
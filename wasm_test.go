@@ -0,0 +1,196 @@
+// This file is part of GoRE.
+//
+// Copyright (C) 2019-2024 GoRE Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package gore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wasmTestSection encodes a WebAssembly section.
+func wasmTestSection(id byte, content []byte) []byte {
+	b := []byte{id}
+	b = binary.AppendUvarint(b, uint64(len(content)))
+	return append(b, content...)
+}
+
+// wasmTestModule encodes a module with an import section, a custom build ID
+// section and a data section with the given segments.
+func wasmTestModule(segments map[uint64][]byte, offsets ...uint64) []byte {
+	b := append([]byte{}, wasmMagic...)
+	b = binary.LittleEndian.AppendUint32(b, 1)
+
+	name := []byte("go:buildid")
+	custom := binary.AppendUvarint(nil, uint64(len(name)))
+	custom = append(custom, name...)
+	custom = append(custom, "\xff Go build ID: \"abc/def\"\n \xff"...)
+	b = append(b, wasmTestSection(wasmSectionCustom, custom)...)
+
+	b = append(b, wasmTestSection(wasmSectionImport, []byte("\x01\x16wasi_snapshot_preview1\x08fd_write\x00\x00"))...)
+
+	data := binary.AppendUvarint(nil, uint64(len(offsets)))
+	for _, off := range offsets {
+		data = append(data, 0, 0x41)
+		data = binary.AppendUvarint(data, off)
+		data = append(data, 0x0b)
+		data = binary.AppendUvarint(data, uint64(len(segments[off])))
+		data = append(data, segments[off]...)
+	}
+	return append(b, wasmTestSection(wasmSectionData, data)...)
+}
+
+func TestOpenWasm(t *testing.T) {
+	module := wasmTestModule(map[uint64][]byte{
+		0x10: []byte("first"),
+		0x20: []byte("second"),
+	}, 0x10, 0x20)
+
+	f, err := OpenReader(bytes.NewReader(module))
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, "abc/def", f.BuildID)
+	assert.Equal(t, ArchWasm, f.FileInfo.Arch)
+	assert.Equal(t, "wasip1", f.FileInfo.OS)
+	assert.Equal(t, intSize64, f.FileInfo.WordSize)
+
+	libs, err := f.ImportedLibraries()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wasi_snapshot_preview1"}, libs)
+
+	imports, err := f.GetDynamicImports()
+	require.NoError(t, err)
+	assert.Equal(t, []DynamicImport{{Name: "fd_write", Library: "wasi_snapshot_preview1"}}, imports)
+
+	addr, mem, err := f.fh.getSectionData(wasmMemorySection)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), addr)
+	require.Len(t, mem, 0x26)
+	assert.Equal(t, []byte("first"), mem[0x10:0x15])
+	assert.Equal(t, []byte("second"), mem[0x20:])
+
+	b, err := f.Bytes(0x20, 6)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), b)
+
+	_, _, err = f.fh.getSectionData("code")
+	assert.ErrorIs(t, err, ErrSectionDoesNotExist)
+}
+
+func TestWasmExports(t *testing.T) {
+	module := wasmTestModule(map[uint64][]byte{0x10: []byte("data")}, 0x10)
+	export := []byte{4}
+	for _, e := range []struct {
+		name string
+		kind byte
+		idx  uint64
+	}{
+		{"add", 0, 3},
+		{"memory", 2, 0},
+		{"_start", 0, 1},
+		{"fd_write", 0, 0},
+	} {
+		export = binary.AppendUvarint(export, uint64(len(e.name)))
+		export = append(export, e.name...)
+		export = append(export, e.kind)
+		export = binary.AppendUvarint(export, e.idx)
+	}
+	module = append(module, wasmTestSection(7, export)...)
+
+	f, err := OpenReader(bytes.NewReader(module))
+	require.NoError(t, err)
+	defer f.Close()
+
+	// The functions are indexed after the imported fd_write.
+	exports, err := f.GetExports()
+	require.NoError(t, err)
+	assert.Equal(t, []Export{
+		{Name: "_start", Address: 0x1000 << 16},
+		{Name: "add", Address: 0x1002 << 16},
+	}, exports)
+
+	// An export with a truncated name.
+	module = wasmTestModule(map[uint64][]byte{0x10: []byte("data")}, 0x10)
+	module = append(module, wasmTestSection(7, []byte{1, 0x10, 'a'})...)
+	f, err = OpenReader(bytes.NewReader(module))
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.GetExports()
+	assert.ErrorContains(t, err, "malformed export 0")
+}
+
+func TestOpenWasmMaxSectionBytes(t *testing.T) {
+	module := wasmTestModule(map[uint64][]byte{0x1000: []byte("data")}, 0x1000)
+
+	f, err := OpenReaderWithOptions(bytes.NewReader(module), Options{MaxSectionBytes: 0x100})
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, _, err = f.fh.getSectionData(wasmMemorySection)
+	assert.ErrorIs(t, err, ErrSectionTooLarge)
+}
+
+func TestOpenWasmMalformed(t *testing.T) {
+	header := binary.LittleEndian.AppendUint32(append([]byte{}, wasmMagic...), 1)
+
+	// A go:buildid section declaring a size of about 4 EiB.
+	name := []byte("go:buildid")
+	custom := append(append([]byte{}, header...), wasmSectionCustom)
+	custom = binary.AppendUvarint(custom, 1<<62)
+	custom = binary.AppendUvarint(custom, uint64(len(name)))
+	custom = append(custom, name...)
+	_, err := OpenReader(bytes.NewReader(custom))
+	assert.ErrorContains(t, err, "out of bounds")
+
+	// A data segment at the end of the 32-bit address space.
+	module := wasmTestModule(map[uint64][]byte{0xfffffff0: []byte("data")}, 0xfffffff0)
+	f, err := OpenReader(bytes.NewReader(module))
+	require.NoError(t, err)
+	defer f.Close()
+	_, _, err = f.fh.getSectionData(wasmMemorySection)
+	assert.ErrorContains(t, err, "outside the memory")
+
+	// The segment doesn't fit in the single page of the declared memory.
+	module = append(append([]byte{}, header...), wasmTestSection(wasmSectionMemory, []byte{1, 0, 1})...)
+	module = append(module, wasmTestModule(map[uint64][]byte{wasmPageSize: []byte("data")}, wasmPageSize)[len(header):]...)
+	f, err = OpenReader(bytes.NewReader(module))
+	require.NoError(t, err)
+	defer f.Close()
+	_, _, err = f.fh.getSectionData(wasmMemorySection)
+	assert.ErrorContains(t, err, "outside the memory")
+}
+
+func TestReadWasmBuildInfo(t *testing.T) {
+	modinfo := append([]byte{}, modInfoStart...)
+	modinfo = append(modinfo, "path\texample.com/app\nmod\texample.com/app\t(devel)\t\nbuild\t-compiler=gc\n"...)
+	modinfo = append(modinfo, modInfoEnd...)
+	module := wasmTestModule(map[uint64][]byte{0x10: modinfo}, 0x10)
+
+	f, err := OpenReader(bytes.NewReader(module))
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.NotNil(t, f.BuildInfo)
+	assert.Equal(t, "example.com/app", f.BuildInfo.ModInfo.Main.Path)
+	require.Len(t, f.BuildInfo.ModInfo.Settings, 1)
+	assert.Equal(t, "-compiler", f.BuildInfo.ModInfo.Settings[0].Key)
+}